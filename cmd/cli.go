@@ -0,0 +1,528 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/diagnostics"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// Output formats accepted by --format
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// errUsage reports bad arguments; the command's usage has already been printed
+var errUsage = errors.New("invalid arguments")
+
+// cli runs one subcommand against a console. Commands that have a matching
+// MCP tool call its handler so both share validation and results.
+type cli struct {
+	client *unifi.ProtectClient
+	server *mcp.Server
+	stdout io.Writer
+	stderr io.Writer
+	format string
+}
+
+// command is a CLI subcommand such as "cameras list"
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(c *cli, ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{"cameras list", "", "List cameras", (*cli).camerasList},
+	{"camera snapshot", "<camera> -o <file>", "Save a JPEG snapshot from a camera (- writes to stdout)", (*cli).cameraSnapshot},
+	{"events tail", "", "Print events from the events subscription as they happen", (*cli).eventsTail},
+	{"light set", "<light> --mode <mode>", "Set when a floodlight turns on", (*cli).lightSet},
+	{"ptz goto", "<camera> <slot>", "Move a PTZ camera to a preset", (*cli).ptzGoto},
+	{"doctor", "", "Diagnose DNS, TLS, the API key, endpoints and subscriptions, with a fix for each failure", (*cli).doctor},
+}
+
+// isCLICommand reports whether the arguments name a subcommand or ask for help
+func isCLICommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	_, _, ok := findCommand(args)
+	return ok || isHelp(args[0])
+}
+
+func isHelp(arg string) bool {
+	switch arg {
+	case "help", "-h", "-help", "--help":
+		return true
+	}
+	return false
+}
+
+// findCommand matches the leading arguments against the command names
+func findCommand(args []string) (command, []string, bool) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd, args[len(words):], true
+		}
+	}
+	return command{}, nil, false
+}
+
+// runCLI runs the subcommand named by args and returns the process exit code
+func runCLI(ctx context.Context, client *unifi.ProtectClient, args []string, stdout, stderr io.Writer) int {
+	cmd, rest, ok := findCommand(args)
+	if !ok {
+		if len(args) > 0 && isHelp(args[0]) {
+			printUsage(stdout)
+			return 0
+		}
+		printUsage(stderr)
+		return 2
+	}
+
+	c := &cli{client: client, server: mcp.NewServer(client), stdout: stdout, stderr: stderr, format: formatTable}
+	if err := cmd.run(c, ctx, rest); err != nil {
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: unifi-protect-mcp [command] [flags]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "With no command the MCP server starts. Commands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", cmd.name, cmd.args, cmd.summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Every command accepts --format table|json (or --json). The console is")
+	fmt.Fprintln(w, "configured with the same environment variables as the server.")
+}
+
+// flags creates a flag set for a command with the shared --format flags
+func (c *cli) flags(cmd string, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.StringVar(&c.format, "format", formatTable, "Output format: table or json")
+	fs.BoolFunc("json", "Shorthand for --format json", func(string) error {
+		c.format = formatJSON
+		return nil
+	})
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: unifi-protect-mcp %s %s [flags]\n", cmd, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses flags that may appear before, between or after the positional
+// arguments and checks the number of positionals
+func (c *cli) parse(fs *flag.FlagSet, args []string, positional int) ([]string, error) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		rest = append(rest, args[0])
+		args = args[1:]
+	}
+
+	if c.format != formatTable && c.format != formatJSON {
+		fmt.Fprintf(c.stderr, "unknown format %q, expected table or json\n", c.format)
+		return nil, errUsage
+	}
+	if len(rest) != positional {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return rest, nil
+}
+
+// callTool runs an MCP tool and returns its structured result decoded into
+// generic JSON values
+func (c *cli) callTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	result, err := c.server.CallTool(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		var messages []string
+		for _, content := range result.Content {
+			if text, ok := content.(mcpgo.TextContent); ok {
+				messages = append(messages, text.Text)
+			}
+		}
+		return nil, errors.New(strings.Join(messages, "; "))
+	}
+
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s result: %w", name, err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode %s result: %w", name, err)
+	}
+	return decoded, nil
+}
+
+// printJSON writes v as indented JSON
+func (c *cli) printJSON(v interface{}) error {
+	encoder := json.NewEncoder(c.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printTable writes rows of the given columns, reading each column from the
+// matching field of every object
+func (c *cli) printTable(rows []interface{}, columns []string, fields []string) error {
+	tw := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	for _, row := range rows {
+		object, _ := row.(map[string]interface{})
+		values := make([]string, len(fields))
+		for i, field := range fields {
+			values[i] = formatValue(object[field])
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printFields writes an object as sorted "key  value" lines
+func (c *cli) printFields(v interface{}) error {
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		return c.printJSON(v)
+	}
+	if len(object) == 0 {
+		fmt.Fprintln(c.stdout, "OK")
+		return nil
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	for _, key := range keys {
+		fmt.Fprintf(tw, "%s\t%s\n", key, formatValue(object[key]))
+	}
+	return tw.Flush()
+}
+
+// formatValue renders a JSON value for a table cell
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		if value == "" {
+			return "-"
+		}
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// resolveDevice returns the ID of the device whose ID or case-insensitive name is ref
+func resolveDevice(kind, ref string, devices []unifi.ProtectDeviceSummary) (string, error) {
+	var matches []string
+	for _, device := range devices {
+		if device.ID == ref {
+			return ref, nil
+		}
+		if strings.EqualFold(device.Name, ref) {
+			matches = append(matches, device.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%s %q not found", kind, ref)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("%s name %q is ambiguous, use one of the IDs %v", kind, ref, matches)
+}
+
+// resolveCamera returns the ID of the camera with the given ID or name
+func (c *cli) resolveCamera(ctx context.Context, ref string) (string, error) {
+	cameras, err := c.client.GetCameras(ctx)
+	if err != nil {
+		return "", err
+	}
+	devices := make([]unifi.ProtectDeviceSummary, len(cameras))
+	for i, camera := range cameras {
+		devices[i] = unifi.ProtectDeviceSummary{ID: camera.ID, Name: camera.Name}
+	}
+	return resolveDevice("camera", ref, devices)
+}
+
+// resolveLight returns the ID of the light with the given ID or name
+func (c *cli) resolveLight(ctx context.Context, ref string) (string, error) {
+	lights, err := c.client.GetLights(ctx)
+	if err != nil {
+		return "", err
+	}
+	devices := make([]unifi.ProtectDeviceSummary, len(lights))
+	for i, light := range lights {
+		devices[i] = unifi.ProtectDeviceSummary{ID: light.ID, Name: light.Name}
+	}
+	return resolveDevice("light", ref, devices)
+}
+
+func (c *cli) camerasList(ctx context.Context, args []string) error {
+	fs := c.flags("cameras list", "")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	result, err := c.callTool(ctx, "get_protect_cameras", nil)
+	if err != nil {
+		return err
+	}
+	if c.format == formatJSON {
+		return c.printJSON(result)
+	}
+	cameras, _ := result.(map[string]interface{})["cameras"].([]interface{})
+	return c.printTable(cameras,
+		[]string{"NAME", "ID", "STATE", "VIDEO MODE", "HDR"},
+		[]string{"name", "id", "state", "videoMode", "hdrType"})
+}
+
+func (c *cli) cameraSnapshot(ctx context.Context, args []string) error {
+	fs := c.flags("camera snapshot", "<camera> -o <file>")
+	output := fs.String("o", "", "File to write the JPEG to, or - for stdout (required)")
+	highQuality := fs.Bool("high-quality", false, "Force a 1080p or larger snapshot")
+	rest, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	cameraID, err := c.resolveCamera(ctx, rest[0])
+	if err != nil {
+		return err
+	}
+	data, err := c.client.GetCameraSnapshot(ctx, cameraID, *highQuality)
+	if err != nil {
+		return err
+	}
+
+	if *output == "-" {
+		_, err := c.stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if c.format == formatJSON {
+		return c.printJSON(map[string]interface{}{"camera_id": cameraID, "file": *output, "bytes": len(data)})
+	}
+	fmt.Fprintf(c.stdout, "Wrote %d bytes to %s\n", len(data), *output)
+	return nil
+}
+
+func (c *cli) eventsTail(ctx context.Context, args []string) error {
+	fs := c.flags("events tail", "")
+	eventType := fs.String("type", "", "Only print events of this type, e.g. motion or ring")
+	count := fs.Int("count", 0, "Stop after this many events (default: run until interrupted)")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	names := map[string]string{}
+	if devices, err := c.client.GetDevices(ctx); err == nil {
+		for _, device := range devices {
+			names[device.ID] = device.Name
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := c.client.SubscribeEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(c.stdout)
+	seen := 0
+	for msg := range events {
+		if *eventType != "" && msg.Item.Type != *eventType {
+			continue
+		}
+		if c.format == formatJSON {
+			if err := encoder.Encode(msg); err != nil {
+				return err
+			}
+		} else {
+			device := names[msg.Item.Device]
+			if device == "" {
+				device = msg.Item.Device
+			}
+			fmt.Fprintf(c.stdout, "%s  %-6s  %-18s  %s  %s\n",
+				time.UnixMilli(msg.Item.Start).Format(time.RFC3339), msg.Type, msg.Item.Type, device, strings.Join(msg.Item.SmartDetectTypes, ","))
+		}
+		seen++
+		if *count > 0 && seen >= *count {
+			break
+		}
+	}
+	return nil
+}
+
+func (c *cli) lightSet(ctx context.Context, args []string) error {
+	fs := c.flags("light set", "<light> --mode <mode>")
+	mode := fs.String("mode", "", "When the light turns on: "+strings.Join(unifi.LightModes, ", ")+" (required)")
+	enableAt := fs.String("enable-at", "", "fulltime or dark (default: keep the current value)")
+	rest, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *mode == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	lightID, err := c.resolveLight(ctx, rest[0])
+	if err != nil {
+		return err
+	}
+	result, err := c.callTool(ctx, "set_light_mode", map[string]interface{}{"light_id": lightID, "mode": *mode, "enable_at": *enableAt})
+	if err != nil {
+		return err
+	}
+	if c.format == formatJSON {
+		return c.printJSON(result)
+	}
+	return c.printFields(result)
+}
+
+func (c *cli) ptzGoto(ctx context.Context, args []string) error {
+	fs := c.flags("ptz goto", "<camera> <slot>")
+	rest, err := c.parse(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	slot, err := strconv.Atoi(rest[1])
+	if err != nil {
+		return fmt.Errorf("preset slot must be a number, got %q", rest[1])
+	}
+
+	cameraID, err := c.resolveCamera(ctx, rest[0])
+	if err != nil {
+		return err
+	}
+	result, err := c.callTool(ctx, "camera_goto_ptz_preset", map[string]interface{}{"camera_id": cameraID, "slot": slot})
+	if err != nil {
+		return err
+	}
+	if c.format == formatJSON {
+		return c.printJSON(result)
+	}
+	return c.printFields(result)
+}
+
+func (c *cli) doctor(ctx context.Context, args []string) error {
+	fs := c.flags("doctor", "")
+	groups := fs.String("checks", "", "Comma separated check groups to run: "+strings.Join(diagnostics.Groups, ", ")+" (default: all)")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	var selected []string
+	if *groups != "" {
+		selected = strings.Split(*groups, ",")
+	}
+
+	// The run_diagnostics tool returns the same report
+	report, err := diagnostics.New(c.client).Run(ctx, selected...)
+	if err != nil {
+		return err
+	}
+	if c.format == formatJSON {
+		if err := c.printJSON(report); err != nil {
+			return err
+		}
+	} else {
+		c.printReport(report)
+	}
+	if report.Failed() {
+		return fmt.Errorf("%d of %d checks failed", report.Summary.Fail, len(report.Checks))
+	}
+	return nil
+}
+
+// printReport writes a diagnostics report as a table followed by the
+// certificate and a hint for each check that did not pass
+func (c *cli) printReport(report *diagnostics.Report) {
+	fmt.Fprintf(c.stdout, "Console %s", report.BaseURL)
+	if report.Version != "" {
+		fmt.Fprintf(c.stdout, " (Protect %s)", report.Version)
+	}
+	fmt.Fprint(c.stdout, "\n\n")
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tLATENCY\tDETAIL")
+	for _, check := range report.Checks {
+		latency := "-"
+		if check.LatencyMS > 0 {
+			latency = strconv.FormatFloat(check.LatencyMS, 'f', 1, 64) + "ms"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", check.Name, strings.ToUpper(string(check.Status)), latency, formatValue(check.Detail))
+	}
+	tw.Flush()
+
+	if cert := report.Certificate; cert != nil {
+		fmt.Fprintf(c.stdout, "\nCertificate: %s, issued by %s, expires %s (%d days), trusted %t, self-signed %t\n",
+			cert.Subject, cert.Issuer, cert.NotAfter.Format("2006-01-02"), cert.DaysRemaining, cert.Trusted, cert.SelfSigned)
+	}
+
+	hinted := false
+	for _, check := range report.Checks {
+		if check.Hint == "" {
+			continue
+		}
+		if !hinted {
+			fmt.Fprintln(c.stdout, "\nHints:")
+			hinted = true
+		}
+		fmt.Fprintf(c.stdout, "  %s: %s\n", check.Name, check.Hint)
+	}
+	fmt.Fprintf(c.stdout, "\n%d ok, %d warnings, %d failed, %d skipped\n",
+		report.Summary.OK, report.Summary.Warn, report.Summary.Fail, report.Summary.Skip)
+}
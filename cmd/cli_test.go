@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/protectmock"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// run runs a CLI command against the mock console
+func run(t *testing.T, url string, args ...string) (int, string, string) {
+	t.Helper()
+	client := unifi.NewProtectClient(url, protectmock.DefaultAPIKey, false)
+	var stdout, stderr bytes.Buffer
+	code := runCLI(context.Background(), client, args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCLICommands(t *testing.T) {
+	mock, srv := protectmock.NewServer()
+	defer srv.Close()
+
+	code, out, _ := run(t, srv.URL, "cameras", "list")
+	if code != 0 || !strings.Contains(out, "Driveway") || !strings.HasPrefix(out, "NAME") {
+		t.Errorf("cameras list: %d %q", code, out)
+	}
+
+	code, out, _ = run(t, srv.URL, "cameras", "list", "--json")
+	var cameras struct {
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(out), &cameras); code != 0 || err != nil || cameras.Count != 2 {
+		t.Errorf("cameras list --json: %d %v %q", code, err, out)
+	}
+
+	file := filepath.Join(t.TempDir(), "driveway.jpg")
+	code, _, errOut := run(t, srv.URL, "camera", "snapshot", "driveway", "-o", file)
+	if data, err := os.ReadFile(file); code != 0 || err != nil || !bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+		t.Errorf("camera snapshot: %d %v %q", code, err, errOut)
+	}
+
+	code, out, _ = run(t, srv.URL, "light", "set", "Driveway Flood", "--mode", "always", "--format", "json")
+	if code != 0 || !strings.Contains(out, `"mode": "always"`) {
+		t.Errorf("light set: %d %q", code, out)
+	}
+	if got := mock.Snapshot().Lights[0]["lightModeSettings"].(map[string]interface{})["mode"]; got != "always" {
+		t.Errorf("light mode not changed: %v", got)
+	}
+
+	code, out, _ = run(t, srv.URL, "ptz", "goto", protectmock.CameraID, "2")
+	if code != 0 || out != "OK\n" {
+		t.Errorf("ptz goto: %d %q", code, out)
+	}
+
+	code, out, _ = run(t, srv.URL, "doctor")
+	if code != 0 || !strings.Contains(out, "(Protect 6.2.72)") || !strings.Contains(out, "subscribe devices") || !strings.Contains(out, " 0 failed") {
+		t.Errorf("doctor: %d %q", code, out)
+	}
+}
+
+func TestCLIDoctorFailure(t *testing.T) {
+	_, srv := protectmock.NewServer()
+	defer srv.Close()
+
+	client := unifi.NewProtectClient(srv.URL, "wrong-key", false)
+	var stdout, stderr bytes.Buffer
+	code := runCLI(context.Background(), client, []string{"doctor", "--checks", "api,endpoints"}, &stdout, &stderr)
+	if code != 1 || !strings.Contains(stdout.String(), "api_key: Create an API key") || !strings.Contains(stderr.String(), "checks failed") {
+		t.Errorf("expected the rejected key to fail with a hint, got %d %q %q", code, stdout.String(), stderr.String())
+	}
+}
+
+func TestCLIErrors(t *testing.T) {
+	_, srv := protectmock.NewServer()
+	defer srv.Close()
+
+	if code, _, errOut := run(t, srv.URL, "ptz", "goto", "Garage", "1"); code != 1 || !strings.Contains(errOut, `camera "Garage" not found`) {
+		t.Errorf("expected an unknown camera to fail, got %d %q", code, errOut)
+	}
+	if code, _, errOut := run(t, srv.URL, "ptz", "goto", "Driveway"); code != 2 || !strings.Contains(errOut, "Usage:") {
+		t.Errorf("expected a missing slot to print usage, got %d %q", code, errOut)
+	}
+	if code, _, _ := run(t, srv.URL, "light", "set", "Driveway Flood"); code != 2 {
+		t.Errorf("expected a missing --mode to fail, got %d", code)
+	}
+	if code, _, errOut := run(t, srv.URL, "light", "set", "Driveway Flood", "--mode", "sometimes"); code != 1 || !strings.Contains(errOut, "sometimes") {
+		t.Errorf("expected the tool to reject an unknown mode, got %d %q", code, errOut)
+	}
+	if code, _, _ := run(t, srv.URL, "cameras", "list", "--format", "yaml"); code != 2 {
+		t.Errorf("expected an unknown format to fail, got %d", code)
+	}
+}
+
+func TestCLIEventsTail(t *testing.T) {
+	mock, srv := protectmock.NewServer()
+	defer srv.Close()
+
+	type outcome struct {
+		code int
+		out  string
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		code, out, _ := run(t, srv.URL, "events", "tail", "--type", "ring", "--count", "1", "--json")
+		done <- outcome{code, out}
+	}()
+
+	for deadline := time.Now().Add(2 * time.Second); mock.Subscribers("events") == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("events tail never subscribed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mock.EmitEvent("motion", protectmock.CameraID, nil)
+	mock.EmitEvent("ring", protectmock.DoorbellID, nil)
+
+	select {
+	case got := <-done:
+		var msg unifi.ProtectEventMessage
+		if err := json.Unmarshal([]byte(got.out), &msg); got.code != 0 || err != nil || msg.Item.Type != "ring" {
+			t.Errorf("events tail: %d %v %q", got.code, err, got.out)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("events tail did not stop after one event")
+	}
+}
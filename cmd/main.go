@@ -5,13 +5,25 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/cassette"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/devices"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/eventlog"
 	"github.com/surrealwolf/unifi-protect-mcp/internal/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/rules"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/scenes"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/scheduler"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/talkback"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/telemetry"
 	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/webhooks"
 )
 
 func init() {
@@ -31,33 +43,221 @@ func init() {
 }
 
 func main() {
+	if args := os.Args[1:]; isCLICommand(args) {
+		if isHelp(args[0]) {
+			printUsage(os.Stdout)
+			return
+		}
+		// Keep command output free of informational logs
+		if os.Getenv("LOG_LEVEL") == "" {
+			logrus.SetLevel(logrus.WarnLevel)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		code := runCLI(ctx, newProtectClient(), args, os.Stdout, os.Stderr)
+		stop()
+		os.Exit(code)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	// Get configuration from environment
-	baseURL := os.Getenv("UNIFI_BASE_URL")
-	if baseURL == "" {
-		baseURL = "https://192.168.1.1"
+	protectClient := newProtectClient()
+
+	// Background workers that save state on shutdown
+	var workers sync.WaitGroup
+
+	// Capture console traffic to a sanitized cassette for replay in tests
+	cassettePath := os.Getenv("PROTECT_CASSETTE_RECORD")
+	var tape *cassette.Cassette
+	if cassettePath != "" {
+		tape = cassette.New()
+		protectClient.WrapTransport(tape.Recorder)
+		logrus.Infof("Recording Protect API traffic to %s", cassettePath)
 	}
 
-	apiKey := os.Getenv("UNIFI_API_KEY")
-	if apiKey == "" {
-		logrus.Fatal("UNIFI_API_KEY environment variable is required")
+	// The version is reported by get_protect_info and in capability errors
+	if _, err := protectClient.DetectVersion(ctx); err != nil {
+		logrus.WithError(err).Warn("Could not detect Protect version")
+	} else {
+		// Learn which optional endpoints the console lacks before tools are listed
+		protectClient.ProbeCapabilities(ctx)
 	}
 
-	// Check for SSL verification flag (default is to verify)
-	skipSSLVerify := os.Getenv("UNIFI_SKIP_SSL_VERIFY") == "true"
-	if skipSSLVerify {
-		logrus.Warn("SSL verification disabled - only use for self-signed certificates")
+	// Directory for persisted state (webhook queue, rules, scheduled jobs, scenes, etc.)
+	dataDir := os.Getenv("MCP_DATA_DIR")
+	if dataDir == "" {
+		dataDir = "data"
 	}
 
-	protectClient := unifi.NewProtectClient(baseURL, apiKey, skipSSLVerify)
+	sched, err := scheduler.New(protectClient, dataDir)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to load scheduled jobs")
+	}
+	sched.Start(ctx)
+
+	sceneManager, err := scenes.NewManager(protectClient, dataDir)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to load scenes")
+	}
+	opts := []mcp.Option{mcp.WithScheduler(sched), mcp.WithScenes(sceneManager)}
+
+	if os.Getenv("TELEMETRY_ENABLED") != "false" {
+		var interval time.Duration
+		if v := os.Getenv("TELEMETRY_INTERVAL"); v != "" {
+			interval, err = time.ParseDuration(v)
+			if err != nil {
+				logrus.WithError(err).Fatal("Invalid TELEMETRY_INTERVAL")
+			}
+		}
+		sampler, err := telemetry.New(protectClient, dataDir, interval)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load sensor history")
+		}
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			sampler.Run(ctx)
+		}()
+		opts = append(opts, mcp.WithTelemetry(sampler))
+	}
+
+	var registry *devices.Registry
+	if os.Getenv("DEVICES_STREAM_ENABLED") != "false" {
+		deviceStream := unifi.NewDeviceStream(protectClient)
+		registry = devices.NewRegistry(protectClient)
+		go registry.Run(ctx, deviceStream.Subscribe())
+		go func() {
+			if err := deviceStream.Run(ctx); err != nil {
+				logrus.WithError(err).Error("Device subscription stopped")
+			}
+		}()
+		opts = append(opts, mcp.WithDevices(registry))
+	}
+
+	player := talkback.NewPlayer(protectClient, os.Getenv("TALKBACK_TTS_COMMAND"), os.Getenv("TALKBACK_FFMPEG"))
+	opts = append(opts, mcp.WithTalkback(player))
+
+	// Optional subsystems fed by the Protect events subscription
+	eventStream := unifi.NewEventStream(protectClient)
+	streamEvents := false
+
+	var dispatcher *webhooks.Dispatcher
+	if webhookConfig := os.Getenv("WEBHOOKS_CONFIG"); webhookConfig != "" {
+		config, err := webhooks.LoadConfig(webhookConfig)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load webhook configuration")
+		}
+		dispatcher, err = webhooks.NewDispatcher(config, dataDir)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to initialize webhook dispatcher")
+		}
+		go dispatcher.Run(ctx, eventStream.Subscribe())
+		opts = append(opts, mcp.WithWebhooks(dispatcher))
+		streamEvents = true
+	}
+
+	// Device offline and online alerts are delivered through a webhook endpoint
+	if endpoint := os.Getenv("DEVICE_ALERT_WEBHOOK_ENDPOINT"); endpoint != "" {
+		if dispatcher == nil || registry == nil {
+			logrus.Fatal("DEVICE_ALERT_WEBHOOK_ENDPOINT needs WEBHOOKS_CONFIG and the device subscription")
+		}
+		registry.NotifyStateChanges(dispatcher, endpoint)
+	}
+
+	// The rules engine runs when asked to, or when there are rules to run
+	rulesFile := os.Getenv("RULES_FILE")
+	if rulesFile == "" {
+		rulesFile = filepath.Join(dataDir, "rules.yaml")
+	}
+	rulesEnabled := os.Getenv("RULES_ENABLED") == "true"
+	if _, err := os.Stat(rulesFile); err == nil && os.Getenv("RULES_ENABLED") != "false" {
+		rulesEnabled = true
+	}
+	if rulesEnabled {
+		engine, err := rules.NewEngine(protectClient, rulesFile)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load rules")
+		}
+		go engine.Run(ctx, eventStream.Subscribe())
+		opts = append(opts, mcp.WithRules(engine))
+		streamEvents = true
+	}
+
+	digestDir := os.Getenv("DIGEST_DIR")
+	digestEndpoint := os.Getenv("DIGEST_WEBHOOK_ENDPOINT")
+	if os.Getenv("EVENT_HISTORY_ENABLED") != "true" && (digestDir != "" || digestEndpoint != "") {
+		logrus.Fatal("The event digest needs EVENT_HISTORY_ENABLED=true")
+	}
+	if os.Getenv("EVENT_HISTORY_ENABLED") == "true" {
+		recorder, err := eventlog.New(dataDir, 0)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load event history")
+		}
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			recorder.Run(ctx, eventStream.Subscribe())
+		}()
+		opts = append(opts, mcp.WithEvents(recorder))
+		streamEvents = true
+
+		if digestDir != "" || digestEndpoint != "" {
+			config := eventlog.DigestConfig{
+				Schedule: os.Getenv("DIGEST_SCHEDULE"),
+				Dir:      digestDir,
+				Endpoint: digestEndpoint,
+			}
+			if v := os.Getenv("DIGEST_FORMATS"); v != "" {
+				for _, format := range strings.Split(v, ",") {
+					config.Formats = append(config.Formats, strings.TrimSpace(format))
+				}
+			}
+			if v := os.Getenv("DIGEST_INCIDENT_GAP"); v != "" {
+				if config.Options.Gap, err = time.ParseDuration(v); err != nil {
+					logrus.WithError(err).Fatal("Invalid DIGEST_INCIDENT_GAP")
+				}
+			}
+			// Only hand over a configured dispatcher; a nil pointer would
+			// still satisfy the interface
+			var notifier eventlog.Notifier
+			if dispatcher != nil {
+				notifier = dispatcher
+			}
+			digest, err := eventlog.NewDigest(recorder, protectClient, notifier, config)
+			if err != nil {
+				logrus.WithError(err).Fatal("Invalid event digest configuration")
+			}
+			digest.Start(ctx)
+		}
+	}
+
+	if streamEvents {
+		go func() {
+			if err := eventStream.Run(ctx); err != nil {
+				logrus.WithError(err).Error("Event subscription stopped")
+			}
+		}()
+	}
+
+	// Limit the tools offered to connected assistants
+	policy := mcp.Policy{ReadOnly: os.Getenv("MCP_READ_ONLY") == "true"}
+	if allow := os.Getenv("MCP_TOOL_ALLOWLIST"); allow != "" {
+		for _, name := range strings.Split(allow, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				policy.Allow = append(policy.Allow, name)
+			}
+		}
+	}
+	if policy.ReadOnly {
+		logrus.Info("Read-only mode: tools that change state are disabled")
+	}
+	opts = append(opts, mcp.WithPolicy(policy))
 
 	// Initialize MCP server
-	server := mcp.NewServer(protectClient)
+	server := mcp.NewServer(protectClient, opts...)
 
 	// Determine transport mode
 	transport := strings.ToLower(os.Getenv("MCP_TRANSPORT"))
@@ -90,5 +290,32 @@ func main() {
 	<-sigChan
 	fmt.Println("\nShutting down gracefully...")
 	cancel()
+	workers.Wait()
+	if tape != nil {
+		if err := tape.Save(cassettePath); err != nil {
+			logrus.WithError(err).Error("Failed to save cassette")
+		}
+	}
 	logrus.Info("UniFi Protect MCP Server stopped")
 }
+
+// newProtectClient creates the Protect client from the environment
+func newProtectClient() *unifi.ProtectClient {
+	baseURL := os.Getenv("UNIFI_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://192.168.1.1"
+	}
+
+	apiKey := os.Getenv("UNIFI_API_KEY")
+	if apiKey == "" {
+		logrus.Fatal("UNIFI_API_KEY environment variable is required")
+	}
+
+	// Check for SSL verification flag (default is to verify)
+	skipSSLVerify := os.Getenv("UNIFI_SKIP_SSL_VERIFY") == "true"
+	if skipSSLVerify {
+		logrus.Warn("SSL verification disabled - only use for self-signed certificates")
+	}
+
+	return unifi.NewProtectClient(baseURL, apiKey, skipSSLVerify)
+}
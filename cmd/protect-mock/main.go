@@ -0,0 +1,66 @@
+// Command protect-mock serves a fake UniFi Protect console for developing and
+// demoing the MCP server without hardware. Point the server at it with
+// UNIFI_BASE_URL=http://localhost:7080 and UNIFI_API_KEY=mock-api-key.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/protectmock"
+)
+
+func main() {
+	addr := flag.String("addr", ":7080", "Listen address")
+	apiKey := flag.String("api-key", protectmock.DefaultAPIKey, "API key clients must send")
+	fixture := flag.String("fixture", "", "JSON fixture file with the console state (default: built-in sample site)")
+	latency := flag.Duration("latency", 0, "Delay added to every response")
+	errorRate := flag.Float64("error-rate", 0, "Fraction of requests to fail with 503, from 0 to 1")
+	eventInterval := flag.Duration("event-interval", 15*time.Second, "How often to emit a synthetic event, 0 to disable")
+	talkbackURL := flag.String("talkback-url", "rtp://127.0.0.1:7004", "RTP URL returned by talkback sessions")
+	flag.Parse()
+
+	logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+
+	opts := []protectmock.Option{
+		protectmock.WithAPIKey(*apiKey),
+		protectmock.WithLatency(*latency),
+		protectmock.WithErrorRate(*errorRate),
+		protectmock.WithTalkbackURL(*talkbackURL),
+	}
+	if *fixture != "" {
+		state, err := protectmock.LoadState(*fixture)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load fixture")
+		}
+		opts = append(opts, protectmock.WithState(state))
+	}
+	mock := protectmock.New(opts...)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if *eventInterval > 0 {
+		go mock.Run(ctx, *eventInterval)
+	}
+
+	server := &http.Server{Addr: *addr, Handler: mock, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logrus.WithField("addr", *addr).Info("Mock Protect console listening")
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logrus.WithError(err).Error("Mock server failed")
+		os.Exit(1)
+	}
+}
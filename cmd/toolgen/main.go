@@ -0,0 +1,31 @@
+// Command toolgen writes the MCP tool definitions derived from the Protect
+// integration API spec. Run it with go generate ./internal/mcp.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/openapi"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/toolgen"
+)
+
+func main() {
+	specPath := flag.String("spec", "docs/protect_integration.json", "OpenAPI spec to read")
+	out := flag.String("out", "internal/mcp/spec_tools_gen.go", "Go file to write")
+	pkg := flag.String("package", "mcp", "package of the generated file")
+	flag.Parse()
+
+	spec, err := openapi.Load(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := toolgen.Generate(spec, *pkg, openapi.ProtectQuirks...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
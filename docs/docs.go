@@ -0,0 +1,10 @@
+// Package docs embeds the Protect integration API description so the server
+// can validate requests against it without the file on disk
+package docs
+
+import _ "embed"
+
+// ProtectIntegration is docs/protect_integration.json
+//
+//go:embed protect_integration.json
+var ProtectIntegration []byte
@@ -0,0 +1,302 @@
+// Package cassette records ProtectClient HTTP traffic to files and replays it
+// in tests. Recorded cassettes are sanitized: the API key is never written,
+// and MAC and IP addresses are replaced with documentation values.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the part of a request used for matching. Headers other than
+// Content-Type are never recorded.
+type Request struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Query       string `json:"query,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        Body   `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        Body   `json:"body,omitempty"`
+}
+
+// Body holds a payload as inline JSON when it is JSON, so cassettes stay
+// readable, and as base64 otherwise
+type Body []byte
+
+// MarshalJSON writes JSON payloads inline and anything else as base64
+func (b Body) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return []byte(`""`), nil
+	}
+	if json.Valid(b) {
+		var out bytes.Buffer
+		if err := json.Compact(&out, b); err == nil {
+			return json.Marshal(map[string]json.RawMessage{"json": out.Bytes()})
+		}
+	}
+	return json.Marshal(map[string][]byte{"base64": b})
+}
+
+// UnmarshalJSON reads the forms MarshalJSON writes
+func (b *Body) UnmarshalJSON(data []byte) error {
+	if string(data) == `""` || string(data) == "null" {
+		*b = nil
+		return nil
+	}
+	var forms struct {
+		JSON   json.RawMessage `json:"json"`
+		Base64 []byte          `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return err
+	}
+	if len(forms.JSON) > 0 {
+		*b = Body(forms.JSON)
+		return nil
+	}
+	*b = forms.Base64
+	return nil
+}
+
+// Cassette is a list of interactions. It is safe for concurrent use.
+type Cassette struct {
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+	scrubber     *scrubber
+}
+
+type file struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// New creates an empty cassette for recording
+func New() *Cassette {
+	return &Cassette{scrubber: newScrubber()}
+}
+
+// Load reads a cassette file for replay
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &Cassette{interactions: f.Interactions, used: make([]bool, len(f.Interactions)), scrubber: newScrubber()}, nil
+}
+
+// Save writes the cassette. A cassette whose addresses could not all be
+// scrubbed is refused.
+func (c *Cassette) Save(path string) error {
+	c.mu.Lock()
+	if err := c.scrubber.err; err != nil {
+		c.mu.Unlock()
+		return fmt.Errorf("cassette not saved: %w", err)
+	}
+	data, err := json.MarshalIndent(file{Interactions: c.interactions}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Interactions returns the recorded interactions. Tests may edit them before
+// replay, for example to point a talkback URL at a local listener.
+func (c *Cassette) Interactions() []*Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.interactions
+}
+
+// Unused returns interactions that replay has not served
+func (c *Cassette) Unused() []*Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var unused []*Interaction
+	for i, in := range c.interactions {
+		if !c.used[i] {
+			unused = append(unused, in)
+		}
+	}
+	return unused
+}
+
+// Recorder returns a transport that forwards requests to next and appends
+// each sanitized exchange to the cassette
+func (c *Cassette) Recorder(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripper(func(req *http.Request) (*http.Response, error) {
+		reqBody, err := readBody(req)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		contentType := req.Header.Get("Content-Type")
+		c.interactions = append(c.interactions, &Interaction{
+			Request: Request{
+				Method:      req.Method,
+				Path:        req.URL.Path,
+				Query:       c.scrubber.scrub(req.URL.RawQuery),
+				ContentType: normalizeContentType(contentType),
+				Body:        Body(c.scrubber.scrubBody(contentType, normalizeBody(contentType, reqBody))),
+			},
+			Response: Response{
+				Status:      resp.StatusCode,
+				ContentType: resp.Header.Get("Content-Type"),
+				Body:        Body(c.scrubber.scrubBody(resp.Header.Get("Content-Type"), respBody)),
+			},
+		})
+		c.used = append(c.used, true)
+		return resp, nil
+	})
+}
+
+// Player returns a transport that answers requests from the cassette. A
+// request matches an unused interaction with the same method, path, query
+// and body; JSON bodies are compared by value. Unmatched requests fail.
+func (c *Cassette) Player() http.RoundTripper {
+	return roundTripper(func(req *http.Request) (*http.Response, error) {
+		body, err := readBody(req)
+		if err != nil {
+			return nil, err
+		}
+		contentType := req.Header.Get("Content-Type")
+		got := Request{
+			Method:      req.Method,
+			Path:        req.URL.Path,
+			Query:       req.URL.RawQuery,
+			ContentType: normalizeContentType(contentType),
+			Body:        normalizeBody(contentType, body),
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for i, in := range c.interactions {
+			if c.used[i] || !matches(in.Request, got) {
+				continue
+			}
+			c.used[i] = true
+			header := http.Header{}
+			if in.Response.ContentType != "" {
+				header.Set("Content-Type", in.Response.ContentType)
+			}
+			return &http.Response{
+				Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+				StatusCode:    in.Response.Status,
+				Proto:         "HTTP/1.1",
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Header:        header,
+				Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+				ContentLength: int64(len(in.Response.Body)),
+				Request:       req,
+			}, nil
+		}
+		return nil, fmt.Errorf("cassette has no unused interaction for %s %s body %s", got.Method, pathWithQuery(got), got.Body)
+	})
+}
+
+func matches(want, got Request) bool {
+	if want.Method != got.Method || want.Path != got.Path || want.Query != got.Query || want.ContentType != got.ContentType {
+		return false
+	}
+	if bytes.Equal(want.Body, got.Body) {
+		return true
+	}
+	var w, g interface{}
+	if json.Unmarshal(want.Body, &w) != nil || json.Unmarshal(got.Body, &g) != nil {
+		return false
+	}
+	return reflect.DeepEqual(w, g)
+}
+
+func pathWithQuery(r Request) string {
+	if r.Query == "" {
+		return r.Path
+	}
+	return r.Path + "?" + r.Query
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// multipartBoundary replaces random multipart boundaries so uploads match
+const multipartBoundary = "cassette-boundary"
+
+func normalizeContentType(contentType string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return contentType
+	}
+	params["boundary"] = multipartBoundary
+	return mime.FormatMediaType(mediaType, params)
+}
+
+func normalizeBody(contentType string, body []byte) []byte {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return body
+	}
+	return bytes.ReplaceAll(body, []byte(params["boundary"]), []byte(multipartBoundary))
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,149 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScrub(t *testing.T) {
+	s := newScrubber()
+	in := `{"mac":"AABBCCDDEEFF","host":"10.0.4.17","peer":"aa:bb:cc:dd:ee:ff","other":"11-22-33-44-55-66","local":"127.0.0.1","version":"6.2.72","again":"10.0.4.17"}`
+	out := s.scrub(in)
+	want := `{"mac":"00005E005301","host":"192.0.2.1","peer":"00:00:5E:00:53:01","other":"00-00-5E-00-53-02","local":"127.0.0.1","version":"6.2.72","again":"192.0.2.1"}`
+	if out != want {
+		t.Errorf("unexpected scrub\n got: %s\nwant: %s", out, want)
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-KEY") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"path":"` + r.URL.Path + `","mac":"AABBCCDDEEFF"}`))
+	}))
+	defer srv.Close()
+
+	rec := New()
+	client := &http.Client{Transport: rec.Recorder(nil)}
+	patch := func(c *http.Client, body string) (*http.Response, error) {
+		req, _ := http.NewRequest("PATCH", srv.URL+"/v1/cameras/abc?x=1", strings.NewReader(body))
+		req.Header.Set("X-API-KEY", "secret")
+		req.Header.Set("Content-Type", "application/json")
+		return c.Do(req)
+	}
+	upload := func(c *http.Client) (*http.Response, error) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		part, _ := mw.CreateFormFile("file", "a.gif")
+		part.Write([]byte("GIF89a"))
+		mw.Close()
+		req, _ := http.NewRequest("POST", srv.URL+"/v1/files/animations", &buf)
+		req.Header.Set("X-API-KEY", "secret")
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return c.Do(req)
+	}
+	if _, err := patch(client, `{"name":"Front","level":3}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := upload(client); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret")) || bytes.Contains(data, []byte("AABBCCDDEEFF")) {
+		t.Errorf("cassette was not sanitized: %s", data)
+	}
+
+	play := mustLoad(t, path)
+	client = &http.Client{Transport: play.Player()}
+	// Key order differs but the JSON value is the same
+	resp, err := patch(client, `{"level":3,"name":"Front"}`)
+	if err != nil {
+		t.Fatalf("expected replay to match: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 || !strings.Contains(string(body), `"00005E005301"`) {
+		t.Errorf("unexpected replay %d %s", resp.StatusCode, body)
+	}
+	if _, err := upload(client); err != nil {
+		t.Fatalf("expected multipart replay to match despite a new boundary: %v", err)
+	}
+	if _, err := patch(client, `{"level":3,"name":"Front"}`); err == nil {
+		t.Error("expected an interaction to be served only once")
+	}
+	if _, err := patch(&http.Client{Transport: mustLoad(t, path).Player()}, `{"level":4}`); err == nil {
+		t.Error("expected a different body to be rejected")
+	}
+	if unused := play.Unused(); len(unused) != 0 {
+		t.Errorf("expected every interaction used, got %d unused", len(unused))
+	}
+}
+
+func mustLoad(t *testing.T, path string) *Cassette {
+	t.Helper()
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRecorderLeavesBinaryBodies(t *testing.T) {
+	jpeg := []byte("\xff\xd8\xff\xe0 10.0.4.17 AA:BB:CC:DD:EE:FF \xff\xd9")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(jpeg)
+	}))
+	defer srv.Close()
+
+	rec := New()
+	resp, err := (&http.Client{Transport: rec.Recorder(nil)}).Get(srv.URL + "/v1/cameras/abc/snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := rec.Interactions()[0].Response.Body; !bytes.Equal(got, jpeg) {
+		t.Errorf("expected the JPEG to be recorded untouched, got %q", got)
+	}
+}
+
+func TestScrubNeverReusesReplacements(t *testing.T) {
+	s := newScrubber()
+	seen := map[string]bool{}
+	for i := 0; i < maxIPs; i++ {
+		fake := s.ip(fmt.Sprintf("10.0.%d.%d", i/200, i%200+1))
+		if seen[fake] {
+			t.Fatalf("replacement %s handed out twice", fake)
+		}
+		seen[fake] = true
+	}
+	if s.err != nil {
+		t.Fatalf("unexpected error within the documentation ranges: %v", s.err)
+	}
+
+	c := New()
+	for i := 0; i <= maxMACs; i++ {
+		c.scrubber.mac(fmt.Sprintf("AABBCCDDEE%02X", i))
+	}
+	if err := c.Save(filepath.Join(t.TempDir(), "cassette.json")); err == nil {
+		t.Error("expected a cassette with too many MAC addresses to be refused")
+	}
+}
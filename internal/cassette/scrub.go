@@ -0,0 +1,154 @@
+package cassette
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	colonMACPattern = regexp.MustCompile(`\b[0-9A-Fa-f]{2}(?:[:-][0-9A-Fa-f]{2}){5}\b`)
+	// Protect writes MACs without separators in "mac" fields
+	macFieldPattern = regexp.MustCompile(`("mac"\s*:\s*")([0-9A-Fa-f]{12})(")`)
+	ipv4Pattern     = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+)
+
+// macPrefix is the documentation range 00:00:5E:00:53:00/24 (RFC 7042)
+const macPrefix = "00:00:5E:00:53:"
+
+// ipNetworks are TEST-NET-1, -2 and -3 (RFC 5737), handed out in order
+var ipNetworks = []string{"192.0.2.", "198.51.100.", "203.0.113."}
+
+// Number of distinct replacements available before scrubbing fails
+const (
+	maxMACs = 255
+	maxIPs  = 254 * 3
+)
+
+// scrubber replaces MAC and IP addresses with documentation values. The same
+// address always gets the same replacement within a cassette, so recorded
+// requests still line up with the responses they were built from. Different
+// addresses never share a replacement; once the documentation ranges run out
+// the scrubber fails instead.
+type scrubber struct {
+	macs    map[string]string
+	ips     map[string]string
+	taken   map[string]bool // replacements handed out or already in the input
+	nextMAC int
+	nextIP  int
+	err     error
+}
+
+func newScrubber() *scrubber {
+	return &scrubber{macs: map[string]string{}, ips: map[string]string{}, taken: map[string]bool{}, nextMAC: 1}
+}
+
+// scrubBody scrubs a textual body and leaves anything else, such as JPEG
+// snapshots and uploaded assets, untouched
+func (s *scrubber) scrubBody(contentType string, body []byte) []byte {
+	if !textual(contentType, body) {
+		return body
+	}
+	return []byte(s.scrub(string(body)))
+}
+
+// textual reports whether a body is JSON or text. Bodies without a content
+// type are treated as text when they are valid UTF-8.
+func textual(contentType string, body []byte) bool {
+	if contentType == "" {
+		return utf8.Valid(body)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json") || mediaType == "application/x-www-form-urlencoded"
+}
+
+func (s *scrubber) scrub(text string) string {
+	text = macFieldPattern.ReplaceAllStringFunc(text, func(m string) string {
+		parts := macFieldPattern.FindStringSubmatch(m)
+		return parts[1] + strings.ReplaceAll(s.mac(parts[2]), ":", "") + parts[3]
+	})
+	text = colonMACPattern.ReplaceAllStringFunc(text, func(m string) string {
+		fake := s.mac(m)
+		if strings.Contains(m, "-") {
+			fake = strings.ReplaceAll(fake, ":", "-")
+		}
+		return fake
+	})
+	return ipv4Pattern.ReplaceAllStringFunc(text, s.ip)
+}
+
+// mac maps a MAC to the documentation range 00:00:5E:00:53:01-FF
+func (s *scrubber) mac(mac string) string {
+	key := strings.ToUpper(strings.NewReplacer(":", "", "-", "").Replace(mac))
+	if strings.HasPrefix(key, "00005E0053") {
+		formatted := formatMAC(key)
+		s.taken[formatted] = true
+		return formatted
+	}
+	if fake, ok := s.macs[key]; ok {
+		return fake
+	}
+	for s.nextMAC <= maxMACs {
+		fake := fmt.Sprintf("%s%02X", macPrefix, s.nextMAC)
+		s.nextMAC++
+		if !s.taken[fake] {
+			s.taken[fake] = true
+			s.macs[key] = fake
+			return fake
+		}
+	}
+	s.fail(fmt.Errorf("more than %d distinct MAC addresses to scrub", maxMACs))
+	return macPrefix + "00"
+}
+
+func formatMAC(hex string) string {
+	parts := make([]string, 0, 6)
+	for i := 0; i < 12; i += 2 {
+		parts = append(parts, hex[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
+
+// ip maps an IPv4 address to the TEST-NET ranges (RFC 5737). Loopback,
+// unspecified and documentation addresses are kept, as are dotted strings
+// that are not addresses.
+func (s *scrubber) ip(text string) string {
+	ip := net.ParseIP(text)
+	if ip == nil || ip.IsLoopback() || ip.IsUnspecified() {
+		return text
+	}
+	for _, network := range ipNetworks {
+		if strings.HasPrefix(text, network) {
+			s.taken[text] = true
+			return text
+		}
+	}
+	if fake, ok := s.ips[text]; ok {
+		return fake
+	}
+	for s.nextIP < maxIPs {
+		fake := fmt.Sprintf("%s%d", ipNetworks[s.nextIP/254], s.nextIP%254+1)
+		s.nextIP++
+		if !s.taken[fake] {
+			s.taken[fake] = true
+			s.ips[text] = fake
+			return fake
+		}
+	}
+	s.fail(fmt.Errorf("more than %d distinct IP addresses to scrub", maxIPs))
+	return ipNetworks[0] + "0"
+}
+
+// fail records the first error; a cassette whose scrubber failed is not saved
+func (s *scrubber) fail(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
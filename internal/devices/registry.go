@@ -0,0 +1,262 @@
+// Package devices keeps a live inventory of every Protect device, combining the
+// REST list endpoints with the devices subscription
+package devices
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// Where a device's information came from
+const (
+	SourceAPI          = "api"
+	SourceSubscription = "subscription"
+)
+
+// Notification actions for devices going offline and coming back
+const (
+	ActionOffline = "device_offline"
+	ActionOnline  = "device_online"
+)
+
+// Client is the subset of the Protect client used to seed the registry
+type Client interface {
+	GetDevices(ctx context.Context) ([]unifi.ProtectDeviceSummary, error)
+}
+
+// Notifier delivers device state alerts to an external endpoint
+type Notifier interface {
+	Notify(endpointID, action string, data interface{}) error
+}
+
+// StateChange is the data of a device state alert
+type StateChange struct {
+	ID        string    `json:"id"`
+	ModelKey  string    `json:"model_key"`
+	Name      string    `json:"name"`
+	MAC       string    `json:"mac,omitempty"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Device is a registry entry
+type Device struct {
+	unifi.ProtectDeviceSummary
+	Source         string     `json:"source"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	StateChangedAt *time.Time `json:"state_changed_at,omitempty"`
+}
+
+// Online reports whether the device is connected
+func (d Device) Online() bool {
+	return d.State == unifi.DeviceStateConnected
+}
+
+// Registry tracks every device and its connection state
+type Registry struct {
+	client   Client
+	mu       sync.Mutex
+	devices  map[string]*Device
+	seeded   bool
+	notifier Notifier
+	endpoint string
+	pending  []alert
+	logger   *logrus.Entry
+}
+
+// alert is a state change waiting to be delivered once the lock is released
+type alert struct {
+	action string
+	change StateChange
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry(client Client) *Registry {
+	return &Registry{
+		client:  client,
+		devices: map[string]*Device{},
+		logger:  logrus.WithField("component", "Devices"),
+	}
+}
+
+// NotifyStateChanges delivers a device_offline notification to endpointID
+// whenever a device disconnects, including one first seen disconnected, and
+// device_online when it connects again
+func (r *Registry) NotifyStateChanges(notifier Notifier, endpointID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifier = notifier
+	r.endpoint = endpointID
+}
+
+// Refresh reloads the devices the API can list. Devices only known from the
+// subscription are kept.
+func (r *Registry) Refresh(ctx context.Context) error {
+	list, err := r.client.GetDevices(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.notify()
+	defer r.mu.Unlock()
+
+	seen := map[string]bool{}
+	for _, summary := range list {
+		seen[summary.ID] = true
+		r.upsertLocked(summary, SourceAPI, now)
+	}
+	for id, d := range r.devices {
+		if d.Source == SourceAPI && !seen[id] {
+			delete(r.devices, id)
+		}
+	}
+	r.seeded = true
+	return nil
+}
+
+// Run seeds the registry and then applies device messages until the channel
+// closes
+func (r *Registry) Run(ctx context.Context, messages <-chan unifi.ProtectDeviceMessage) {
+	if err := r.Refresh(ctx); err != nil {
+		r.logger.WithError(err).Warn("Failed to load device inventory")
+	}
+	for msg := range messages {
+		r.Apply(msg, time.Now())
+	}
+}
+
+// Apply updates the registry from one subscription message
+func (r *Registry) Apply(msg unifi.ProtectDeviceMessage, now time.Time) {
+	id := msg.ID()
+	if id == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.notify()
+	defer r.mu.Unlock()
+
+	if msg.Type == "remove" {
+		delete(r.devices, id)
+		return
+	}
+
+	summary := unifi.ProtectDeviceSummary{ID: id, ModelKey: msg.ModelKey()}
+	if existing, ok := r.devices[id]; ok {
+		summary = existing.ProtectDeviceSummary
+	}
+	if v, ok := msg.Item["state"].(string); ok {
+		summary.State = v
+	}
+	if v, ok := msg.Item["name"].(string); ok {
+		summary.Name = v
+	}
+	if v, ok := msg.Item["mac"].(string); ok {
+		summary.MAC = v
+	}
+	if summary.ModelKey == "" {
+		summary.ModelKey = msg.ModelKey()
+	}
+
+	source := SourceSubscription
+	if existing, ok := r.devices[id]; ok {
+		source = existing.Source
+	}
+	r.upsertLocked(summary, source, now)
+}
+
+func (r *Registry) upsertLocked(summary unifi.ProtectDeviceSummary, source string, now time.Time) {
+	d, ok := r.devices[summary.ID]
+	if !ok {
+		d = &Device{Source: source}
+		r.devices[summary.ID] = d
+		// Devices without a list endpoint are first seen through the
+		// subscription, often in the update that reports them offline
+		if r.seeded && summary.State == unifi.DeviceStateDisconnected {
+			r.stateChangedLocked(d, summary, now)
+		}
+	} else if d.State != summary.State && summary.State != "" {
+		changed := now
+		d.StateChangedAt = &changed
+		if r.seeded {
+			r.stateChangedLocked(d, summary, now)
+		}
+	}
+	d.ProtectDeviceSummary = summary
+	d.UpdatedAt = now
+}
+
+// stateChangedLocked logs a state change and queues an alert when the device
+// went offline or came online
+func (r *Registry) stateChangedLocked(d *Device, summary unifi.ProtectDeviceSummary, now time.Time) {
+	entry := r.logger.WithFields(logrus.Fields{
+		"device_id": summary.ID,
+		"model_key": summary.ModelKey,
+		"name":      summary.Name,
+		"from":      d.State,
+		"to":        summary.State,
+	})
+	action := ""
+	switch {
+	case summary.State == unifi.DeviceStateDisconnected:
+		entry.Warn("Device went offline")
+		action = ActionOffline
+	case summary.State == unifi.DeviceStateConnected:
+		entry.Info("Device came online")
+		action = ActionOnline
+	default:
+		entry.Info("Device state changed")
+	}
+	if action == "" || r.notifier == nil {
+		return
+	}
+	r.pending = append(r.pending, alert{action: action, change: StateChange{
+		ID:        summary.ID,
+		ModelKey:  summary.ModelKey,
+		Name:      summary.Name,
+		MAC:       summary.MAC,
+		From:      d.State,
+		To:        summary.State,
+		ChangedAt: now,
+	}})
+}
+
+// notify delivers the queued alerts. It must be called without the lock held.
+func (r *Registry) notify() {
+	r.mu.Lock()
+	pending, notifier, endpoint := r.pending, r.notifier, r.endpoint
+	r.pending = nil
+	r.mu.Unlock()
+
+	for _, a := range pending {
+		if err := notifier.Notify(endpoint, a.action, a.change); err != nil {
+			r.logger.WithError(err).WithField("device_id", a.change.ID).Error("Failed to queue device alert")
+		}
+	}
+}
+
+// List returns every known device ordered by model key and name
+func (r *Registry) List() []Device {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := make([]Device, 0, len(r.devices))
+	for _, d := range r.devices {
+		list = append(list, *d)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ModelKey != list[j].ModelKey {
+			return list[i].ModelKey < list[j].ModelKey
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
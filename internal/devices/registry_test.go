@@ -0,0 +1,116 @@
+package devices
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+type fakeClient struct {
+	devices []unifi.ProtectDeviceSummary
+}
+
+func (f *fakeClient) GetDevices(ctx context.Context) ([]unifi.ProtectDeviceSummary, error) {
+	return f.devices, nil
+}
+
+func TestRegistryMergesAPIAndSubscription(t *testing.T) {
+	client := &fakeClient{devices: []unifi.ProtectDeviceSummary{
+		{ID: "cam1", ModelKey: "camera", Name: "Porch", State: "CONNECTED"},
+	}}
+	r := NewRegistry(client)
+	if err := r.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	now := time.Now()
+	r.Apply(unifi.ProtectDeviceMessage{Type: "update", Item: map[string]interface{}{"id": "spk1", "modelKey": "speaker", "state": "CONNECTED"}}, now)
+	r.Apply(unifi.ProtectDeviceMessage{Type: "update", Item: map[string]interface{}{"id": "cam1", "modelKey": "camera", "state": "DISCONNECTED"}}, now)
+
+	list := r.List()
+	if len(list) != 2 {
+		t.Fatalf("expected 2 devices, got %+v", list)
+	}
+	camera, speaker := list[0], list[1]
+	if camera.ID != "cam1" || camera.Online() || camera.Name != "Porch" || camera.StateChangedAt == nil {
+		t.Errorf("unexpected camera entry: %+v", camera)
+	}
+	if speaker.ID != "spk1" || speaker.Source != SourceSubscription || !speaker.Online() {
+		t.Errorf("unexpected speaker entry: %+v", speaker)
+	}
+
+	// A refresh keeps subscription-only devices and drops removed API devices
+	client.devices = nil
+	if err := r.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if list := r.List(); len(list) != 1 || list[0].ID != "spk1" {
+		t.Errorf("expected only the speaker after refresh, got %+v", list)
+	}
+
+	r.Apply(unifi.ProtectDeviceMessage{Type: "remove", Item: map[string]interface{}{"id": "spk1", "modelKey": "speaker"}}, now)
+	if len(r.List()) != 0 {
+		t.Error("expected removed device to be dropped")
+	}
+}
+
+type fakeNotifier struct {
+	actions []string
+	changes []StateChange
+}
+
+func (n *fakeNotifier) Notify(endpointID, action string, data interface{}) error {
+	n.actions = append(n.actions, endpointID+" "+action)
+	n.changes = append(n.changes, data.(StateChange))
+	return nil
+}
+
+func TestRegistryNotifiesStateChanges(t *testing.T) {
+	client := &fakeClient{devices: []unifi.ProtectDeviceSummary{
+		{ID: "cam1", ModelKey: "camera", Name: "Porch", State: "CONNECTED"},
+	}}
+	r := NewRegistry(client)
+	notifier := &fakeNotifier{}
+	r.NotifyStateChanges(notifier, "ops")
+	if err := r.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	now := time.Now()
+	r.Apply(unifi.ProtectDeviceMessage{Type: "update", Item: map[string]interface{}{"id": "cam1", "modelKey": "camera", "state": "DISCONNECTED"}}, now)
+	r.Apply(unifi.ProtectDeviceMessage{Type: "update", Item: map[string]interface{}{"id": "cam1", "modelKey": "camera", "state": "CONNECTING"}}, now)
+	r.Apply(unifi.ProtectDeviceMessage{Type: "update", Item: map[string]interface{}{"id": "cam1", "modelKey": "camera", "state": "CONNECTED"}}, now)
+
+	if len(notifier.actions) != 2 || notifier.actions[0] != "ops device_offline" || notifier.actions[1] != "ops device_online" {
+		t.Fatalf("expected an offline and an online alert, got %v", notifier.actions)
+	}
+	if change := notifier.changes[0]; change.Name != "Porch" || change.From != "CONNECTED" || change.To != "DISCONNECTED" {
+		t.Errorf("unexpected offline alert %+v", change)
+	}
+	if change := notifier.changes[1]; change.From != "CONNECTING" || change.To != "CONNECTED" {
+		t.Errorf("unexpected online alert %+v", change)
+	}
+}
+
+func TestRegistryNotifiesUnlistedDeviceOffline(t *testing.T) {
+	r := NewRegistry(&fakeClient{})
+	notifier := &fakeNotifier{}
+	r.NotifyStateChanges(notifier, "ops")
+	if err := r.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	// Speakers have no list endpoint; the first message may be the disconnect
+	now := time.Now()
+	r.Apply(unifi.ProtectDeviceMessage{Type: "update", Item: map[string]interface{}{"id": "spk1", "modelKey": "speaker", "name": "Yard", "state": "DISCONNECTED"}}, now)
+	r.Apply(unifi.ProtectDeviceMessage{Type: "add", Item: map[string]interface{}{"id": "spk2", "modelKey": "speaker", "state": "CONNECTED"}}, now)
+
+	if len(notifier.actions) != 1 || notifier.actions[0] != "ops device_offline" {
+		t.Fatalf("expected one offline alert, got %v", notifier.actions)
+	}
+	if change := notifier.changes[0]; change.ID != "spk1" || change.ModelKey != "speaker" || change.Name != "Yard" || change.To != "DISCONNECTED" {
+		t.Errorf("unexpected offline alert %+v", change)
+	}
+}
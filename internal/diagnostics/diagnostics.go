@@ -0,0 +1,284 @@
+// Package diagnostics checks the path from this server to a Protect console,
+// from DNS up to the API subscriptions, and suggests a fix for each failure
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// Status is the outcome of one check
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Check groups that can be run on their own
+const (
+	GroupNetwork       = "network"
+	GroupAPI           = "api"
+	GroupEndpoints     = "endpoints"
+	GroupSubscriptions = "subscriptions"
+)
+
+// Groups lists every check group in the order they run
+var Groups = []string{GroupNetwork, GroupAPI, GroupEndpoints, GroupSubscriptions}
+
+// SlowLatency is the response time above which a check is reported as slow
+const SlowLatency = 2 * time.Second
+
+// DefaultTimeout bounds each network check
+const DefaultTimeout = 5 * time.Second
+
+// Check is the result of one diagnostic step
+type Check struct {
+	Name      string  `json:"name"`
+	Status    Status  `json:"status"`
+	Detail    string  `json:"detail,omitempty"`
+	LatencyMS float64 `json:"latency_ms"`
+	Hint      string  `json:"hint,omitempty"`
+}
+
+// Summary counts checks by status
+type Summary struct {
+	OK   int `json:"ok"`
+	Warn int `json:"warn"`
+	Fail int `json:"fail"`
+	Skip int `json:"skip"`
+}
+
+// Report is the result of a diagnostics run
+type Report struct {
+	BaseURL     string       `json:"base_url"`
+	Version     string       `json:"version,omitempty"`
+	Certificate *Certificate `json:"certificate,omitempty"`
+	Checks      []Check      `json:"checks"`
+	Summary     Summary      `json:"summary"`
+}
+
+// Failed reports whether any check failed
+func (r *Report) Failed() bool {
+	return r.Summary.Fail > 0
+}
+
+func (r *Report) add(c Check) {
+	r.Checks = append(r.Checks, c)
+	switch c.Status {
+	case StatusOK:
+		r.Summary.OK++
+	case StatusWarn:
+		r.Summary.Warn++
+	case StatusFail:
+		r.Summary.Fail++
+	case StatusSkip:
+		r.Summary.Skip++
+	}
+}
+
+// Remediation hints, one per kind of failure
+const (
+	hintBaseURL      = "Set UNIFI_BASE_URL to the console address, e.g. https://192.168.1.1"
+	hintDNS          = "Check the hostname in UNIFI_BASE_URL, or use the console's IP address"
+	hintTCP          = "Check the console is powered on and reachable from this host, and that UNIFI_BASE_URL uses the right port (443 on UniFi OS consoles)"
+	hintTLSHandshake = "Check UNIFI_BASE_URL uses https:// for the console's HTTPS port and that no proxy intercepts the connection"
+	hintUntrusted    = "Set UNIFI_SKIP_SSL_VERIFY=true for the console's self-signed certificate, or install a certificate from a trusted CA"
+	hintExpired      = "Renew the console's certificate; UniFi OS regenerates its self-signed certificate on restart"
+	hintExpiring     = "Renew the console's certificate before it expires"
+	hintAPIKey       = "Create an API key under Settings > Control Plane > Integrations in UniFi OS and set UNIFI_API_KEY"
+	hintNotProtect   = "Check UNIFI_BASE_URL points at the UniFi OS console itself and that the Protect application is installed and running"
+	hintServerError  = "The console reported an internal error; check the Protect application is running, or restart it"
+	hintNotServed    = "The console does not serve this endpoint; it is not part of every Protect release"
+	hintVersion      = "The console reported a version this server cannot parse"
+	hintSlow         = "The console is slow to respond; check the network path and the console's load"
+	hintWebSocket    = "Check that proxies between this host and the console allow WebSocket upgrades"
+)
+
+// endpoint is a read-only integration API path probed by the endpoints group
+type endpoint struct {
+	path       string
+	capability unifi.Capability
+}
+
+var endpoints = []endpoint{
+	{path: "nvrs"},
+	{path: "cameras"},
+	{path: "sensors"},
+	{path: "lights"},
+	{path: "chimes"},
+	{path: "viewers"},
+	{path: "liveviews"},
+	{path: "files/" + unifi.AssetFileTypeAnimations, capability: unifi.CapabilityFiles},
+}
+
+var subscriptions = []string{"events", "devices"}
+
+// Runner runs diagnostics against the console a client is configured for
+type Runner struct {
+	client  *unifi.ProtectClient
+	timeout time.Duration
+}
+
+// New creates a runner for the client's console
+func New(client *unifi.ProtectClient) *Runner {
+	return &Runner{client: client, timeout: DefaultTimeout}
+}
+
+// Run runs the given check groups, or every group when none are given
+func (r *Runner) Run(ctx context.Context, groups ...string) (*Report, error) {
+	if len(groups) == 0 {
+		groups = Groups
+	}
+	selected := map[string]bool{}
+	for _, group := range groups {
+		if !slices.Contains(Groups, group) {
+			return nil, fmt.Errorf("unknown check group %q, expected one of %v", group, Groups)
+		}
+		selected[group] = true
+	}
+
+	report := &Report{BaseURL: r.client.BaseURL()}
+
+	// Later groups are skipped once an earlier one shows they cannot pass
+	blocked := ""
+	if selected[GroupNetwork] && !r.checkNetwork(ctx, report) {
+		blocked = "console is not reachable"
+	}
+	if selected[GroupAPI] {
+		if blocked != "" {
+			report.add(Check{Name: "api_key", Status: StatusSkip, Detail: blocked})
+		} else if !r.checkAPI(ctx, report) {
+			blocked = "API key check failed"
+		}
+	}
+	if selected[GroupEndpoints] {
+		r.checkEndpoints(ctx, report, blocked)
+	}
+	if selected[GroupSubscriptions] {
+		r.checkSubscriptions(ctx, report, blocked)
+	}
+	return report, nil
+}
+
+// checkAPI validates the API key against meta/info and detects the version
+func (r *Runner) checkAPI(ctx context.Context, report *Report) bool {
+	check, status := r.probe(ctx, "api_key", "meta/info")
+	switch status {
+	case http.StatusOK:
+		check.Detail = "API key accepted"
+	case http.StatusUnauthorized, http.StatusForbidden:
+		check.Detail = fmt.Sprintf("console rejected the API key (status %d)", status)
+		check.Hint = hintAPIKey
+	case http.StatusNotFound:
+		check.Detail = "the Protect integration API was not found"
+		check.Hint = hintNotProtect
+	}
+	report.add(check)
+	if check.Status == StatusFail {
+		return false
+	}
+
+	version, err := r.client.DetectVersion(ctx)
+	if err != nil {
+		report.add(Check{Name: "version", Status: StatusWarn, Detail: err.Error(), Hint: hintVersion})
+		return true
+	}
+	report.Version = version.String()
+	report.add(Check{Name: "version", Status: StatusOK, Detail: "Protect " + version.String()})
+	return true
+}
+
+// checkEndpoints probes each read-only integration endpoint
+func (r *Runner) checkEndpoints(ctx context.Context, report *Report, blocked string) {
+	for _, ep := range endpoints {
+		name := "GET /v1/" + ep.path
+		if blocked != "" {
+			report.add(Check{Name: name, Status: StatusSkip, Detail: blocked})
+			continue
+		}
+		if ep.capability != "" {
+			if err := r.client.RequireCapability(ep.capability); err != nil {
+				report.add(Check{Name: name, Status: StatusSkip, Detail: err.Error()})
+				continue
+			}
+		}
+		check, status := r.probe(ctx, name, ep.path)
+		switch status {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			check.Hint = hintAPIKey
+		case http.StatusNotFound:
+			check.Hint = hintNotServed
+			// Optional parts of the API are not served by every console
+			if ep.capability != "" {
+				check.Status = StatusWarn
+			}
+		}
+		report.add(check)
+	}
+}
+
+// checkSubscriptions opens and closes each subscription socket
+func (r *Runner) checkSubscriptions(ctx context.Context, report *Report, blocked string) {
+	for _, topic := range subscriptions {
+		name := "subscribe " + topic
+		if blocked != "" {
+			report.add(Check{Name: name, Status: StatusSkip, Detail: blocked})
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, r.timeout)
+		start := time.Now()
+		err := r.client.ProbeSubscription(ctx, topic)
+		latency := time.Since(start)
+		cancel()
+
+		check := Check{Name: name, Status: StatusOK, Detail: "WebSocket upgrade accepted", LatencyMS: milliseconds(latency)}
+		var notServed *unifi.NotServedError
+		switch {
+		case errors.As(err, &notServed):
+			check.Status, check.Detail, check.Hint = StatusFail, err.Error(), hintNotServed
+		case err != nil:
+			check.Status, check.Detail, check.Hint = StatusFail, err.Error(), hintWebSocket
+		case latency > SlowLatency:
+			check.Status, check.Hint = StatusWarn, hintSlow
+		}
+		report.add(check)
+	}
+}
+
+// probe times a GET of an API path and classifies the status
+func (r *Runner) probe(ctx context.Context, name, path string) (Check, int) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	status, err := r.client.Probe(ctx, path)
+	latency := time.Since(start)
+
+	check := Check{Name: name, Status: StatusOK, Detail: fmt.Sprintf("status %d", status), LatencyMS: milliseconds(latency)}
+	switch {
+	case err != nil:
+		check.Status, check.Detail, check.Hint = StatusFail, err.Error(), hintTCP
+	case status >= 500:
+		check.Status, check.Hint = StatusFail, hintServerError
+	case status >= 400:
+		check.Status = StatusFail
+	case latency > SlowLatency:
+		check.Status, check.Hint = StatusWarn, hintSlow
+	}
+	return check, status
+}
+
+// milliseconds rounds a duration to tenths of a millisecond
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Round(100*time.Microsecond)) / float64(time.Millisecond)
+}
@@ -0,0 +1,138 @@
+package diagnostics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/protectmock"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// checks indexes a report's checks by name
+func checks(report *Report) map[string]Check {
+	byName := map[string]Check{}
+	for _, c := range report.Checks {
+		byName[c.Name] = c
+	}
+	return byName
+}
+
+func TestHealthyConsole(t *testing.T) {
+	srv := httptest.NewTLSServer(protectmock.New())
+	defer srv.Close()
+
+	client := unifi.NewProtectClient(srv.URL, protectmock.DefaultAPIKey, true)
+	report, err := New(client).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Failed() || report.Summary.Warn != 0 || report.Summary.Skip != 0 {
+		t.Errorf("expected every check to pass, got %+v", report.Checks)
+	}
+	if report.Version != protectmock.DefaultVersion {
+		t.Errorf("expected version %s, got %q", protectmock.DefaultVersion, report.Version)
+	}
+	if report.Certificate == nil || report.Certificate.Trusted || len(report.Certificate.IPAddresses) == 0 {
+		t.Errorf("expected the test server's untrusted certificate, got %+v", report.Certificate)
+	}
+	byName := checks(report)
+	for _, name := range []string{"dns", "tcp", "tls", "api_key", "version", "GET /v1/cameras", "GET /v1/files/animations", "subscribe events", "subscribe devices"} {
+		if byName[name].Status != StatusOK {
+			t.Errorf("expected %s to pass, got %+v", name, byName[name])
+		}
+	}
+}
+
+func TestUntrustedCertificate(t *testing.T) {
+	srv := httptest.NewTLSServer(protectmock.New())
+	defer srv.Close()
+
+	client := unifi.NewProtectClient(srv.URL, protectmock.DefaultAPIKey, false)
+	report, err := New(client).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := checks(report)
+	if tls := byName["tls"]; tls.Status != StatusFail || tls.Hint != hintUntrusted {
+		t.Errorf("expected the untrusted certificate to fail, got %+v", tls)
+	}
+	if byName["api_key"].Status != StatusSkip || byName["GET /v1/cameras"].Status != StatusSkip {
+		t.Errorf("expected API checks to be skipped, got %+v", report.Checks)
+	}
+}
+
+func TestRejectedAPIKey(t *testing.T) {
+	srv := httptest.NewServer(protectmock.New())
+	defer srv.Close()
+
+	client := unifi.NewProtectClient(srv.URL, "wrong-key", false)
+	report, err := New(client).Run(context.Background(), GroupAPI, GroupEndpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := checks(report)
+	if key := byName["api_key"]; key.Status != StatusFail || key.Hint != hintAPIKey {
+		t.Errorf("expected the API key to be rejected, got %+v", key)
+	}
+	if _, ran := byName["dns"]; ran {
+		t.Error("expected network checks not to run")
+	}
+	if byName["GET /v1/nvrs"].Status != StatusSkip {
+		t.Errorf("expected endpoints to be skipped, got %+v", byName["GET /v1/nvrs"])
+	}
+}
+
+func TestUnreachableConsole(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	client := unifi.NewProtectClient("https://"+addr, "key", false)
+	report, err := New(client).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := checks(report)
+	if tcp := byName["tcp"]; tcp.Status != StatusFail || tcp.Hint != hintTCP {
+		t.Errorf("expected the connection to fail, got %+v", tcp)
+	}
+	if byName["api_key"].Status != StatusSkip || byName["subscribe events"].Status != StatusSkip {
+		t.Errorf("expected later checks to be skipped, got %+v", report.Checks)
+	}
+
+	if _, err := New(client).Run(context.Background(), "firewall"); err == nil {
+		t.Error("expected an unknown group to be rejected")
+	}
+}
+
+func TestUnservedEndpoint(t *testing.T) {
+	mock := protectmock.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/v1/files/") {
+			http.NotFound(w, r)
+			return
+		}
+		mock.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	client := unifi.NewProtectClient(srv.URL, protectmock.DefaultAPIKey, false)
+	report, err := New(client).Run(context.Background(), GroupAPI, GroupEndpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := checks(report)
+	if files := byName["GET /v1/files/animations"]; files.Status != StatusWarn || files.Hint != hintNotServed {
+		t.Errorf("expected unserved asset files to be a warning, got %+v", files)
+	}
+	if report.Failed() {
+		t.Errorf("expected no failures, got %+v", report.Checks)
+	}
+}
@@ -0,0 +1,158 @@
+package diagnostics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CertificateExpiryWarning is how close to expiry a certificate is reported
+const CertificateExpiryWarning = 14 * 24 * time.Hour
+
+// Certificate describes the certificate the console presented
+type Certificate struct {
+	Subject       string    `json:"subject"`
+	Issuer        string    `json:"issuer"`
+	DNSNames      []string  `json:"dns_names,omitempty"`
+	IPAddresses   []string  `json:"ip_addresses,omitempty"`
+	NotBefore     time.Time `json:"not_before"`
+	NotAfter      time.Time `json:"not_after"`
+	DaysRemaining int       `json:"days_remaining"`
+	SelfSigned    bool      `json:"self_signed"`
+	Trusted       bool      `json:"trusted"`
+}
+
+// checkNetwork resolves, connects to and, for https, handshakes with the
+// console. It returns false when the console cannot be reached.
+func (r *Runner) checkNetwork(ctx context.Context, report *Report) bool {
+	u, err := url.Parse(report.BaseURL)
+	if err != nil || u.Hostname() == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		report.add(Check{Name: "base_url", Status: StatusFail, Detail: fmt.Sprintf("invalid console URL %q", report.BaseURL), Hint: hintBaseURL})
+		return false
+	}
+	host := u.Hostname()
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	if !r.checkDNS(ctx, report, host) {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	start := time.Now()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	latency := time.Since(start)
+	if err != nil {
+		report.add(Check{Name: "tcp", Status: StatusFail, Detail: err.Error(), LatencyMS: milliseconds(latency), Hint: hintTCP})
+		return false
+	}
+	defer conn.Close()
+	check := Check{Name: "tcp", Status: StatusOK, Detail: "connected to " + conn.RemoteAddr().String(), LatencyMS: milliseconds(latency)}
+	if latency > SlowLatency {
+		check.Status, check.Hint = StatusWarn, hintSlow
+	}
+	report.add(check)
+
+	if u.Scheme == "https" {
+		return r.checkTLS(ctx, report, conn, host)
+	}
+	return true
+}
+
+func (r *Runner) checkDNS(ctx context.Context, report *Report, host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		report.add(Check{Name: "dns", Status: StatusOK, Detail: "console address is an IP, no lookup needed"})
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	start := time.Now()
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	latency := time.Since(start)
+	if err != nil {
+		report.add(Check{Name: "dns", Status: StatusFail, Detail: err.Error(), LatencyMS: milliseconds(latency), Hint: hintDNS})
+		return false
+	}
+	check := Check{Name: "dns", Status: StatusOK, Detail: fmt.Sprintf("%s resolves to %s", host, strings.Join(addrs, ", ")), LatencyMS: milliseconds(latency)}
+	if latency > SlowLatency {
+		check.Status, check.Hint = StatusWarn, hintSlow
+	}
+	report.add(check)
+	return true
+}
+
+// checkTLS handshakes without verification so the certificate can be reported
+// even when it is untrusted, then verifies it separately
+func (r *Runner) checkTLS(ctx context.Context, report *Report, conn net.Conn, host string) bool {
+	config := &tls.Config{InsecureSkipVerify: true}
+	if net.ParseIP(host) == nil {
+		config.ServerName = host
+	}
+	tlsConn := tls.Client(conn, config)
+
+	start := time.Now()
+	err := tlsConn.HandshakeContext(ctx)
+	latency := time.Since(start)
+	if err != nil {
+		report.add(Check{Name: "tls", Status: StatusFail, Detail: err.Error(), LatencyMS: milliseconds(latency), Hint: hintTLSHandshake})
+		return false
+	}
+
+	state := tlsConn.ConnectionState()
+	leaf := state.PeerCertificates[0]
+	cert := describeCertificate(leaf)
+	intermediates := x509.NewCertPool()
+	for _, c := range state.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+	_, verifyErr := leaf.Verify(x509.VerifyOptions{DNSName: host, Intermediates: intermediates})
+	cert.Trusted = verifyErr == nil
+	report.Certificate = cert
+
+	check := Check{Name: "tls", Status: StatusOK, Detail: fmt.Sprintf("%s, certificate for %s", tls.VersionName(state.Version), cert.Subject), LatencyMS: milliseconds(latency)}
+	skipVerify := r.client.SkipsTLSVerify()
+	switch {
+	case !cert.Trusted && !skipVerify:
+		check.Status, check.Detail, check.Hint = StatusFail, "certificate is not trusted: "+verifyErr.Error(), hintUntrusted
+	case time.Now().After(leaf.NotAfter):
+		// Only reachable when verification is skipped; an expired certificate is never trusted
+		check.Status, check.Detail, check.Hint = StatusWarn, fmt.Sprintf("certificate expired on %s", leaf.NotAfter.Format("2006-01-02")), hintExpired
+	case time.Until(leaf.NotAfter) < CertificateExpiryWarning:
+		check.Status, check.Detail, check.Hint = StatusWarn, fmt.Sprintf("certificate expires in %d days", cert.DaysRemaining), hintExpiring
+	case !cert.Trusted:
+		check.Detail += " (untrusted, accepted because UNIFI_SKIP_SSL_VERIFY=true)"
+	}
+	if check.Status == StatusOK && latency > SlowLatency {
+		check.Status, check.Hint = StatusWarn, hintSlow
+	}
+	report.add(check)
+	return check.Status != StatusFail
+}
+
+func describeCertificate(c *x509.Certificate) *Certificate {
+	cert := &Certificate{
+		Subject:       c.Subject.String(),
+		Issuer:        c.Issuer.String(),
+		DNSNames:      c.DNSNames,
+		NotBefore:     c.NotBefore,
+		NotAfter:      c.NotAfter,
+		DaysRemaining: int(time.Until(c.NotAfter).Hours() / 24),
+		SelfSigned:    c.CheckSignatureFrom(c) == nil,
+	}
+	for _, ip := range c.IPAddresses {
+		cert.IPAddresses = append(cert.IPAddresses, ip.String())
+	}
+	return cert
+}
@@ -0,0 +1,196 @@
+package eventlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// DefaultDigestSchedule sends the digest at 07:00 server local time
+const DefaultDigestSchedule = "0 7 * * *"
+
+// DigestAction is the action of digest notifications
+const DigestAction = "digest"
+
+// Digest formats written to disk
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// digestWindow is how much history each digest covers
+const digestWindow = 24 * time.Hour
+
+// Client is the subset of the Protect client used to name devices
+type Client interface {
+	GetCameras(ctx context.Context) ([]unifi.ProtectCamera, error)
+	GetSensors(ctx context.Context) ([]unifi.ProtectSensor, error)
+}
+
+// Notifier delivers a digest to an external endpoint
+type Notifier interface {
+	Notify(endpointID, action string, data interface{}) error
+}
+
+// DigestConfig controls the scheduled digest
+type DigestConfig struct {
+	// Schedule is a five-field cron expression in server local time
+	Schedule string
+	// Dir receives one file per format and day when set
+	Dir     string
+	Formats []string
+	// Endpoint is the webhook endpoint the digest is delivered to when set
+	Endpoint string
+	Options  Options
+}
+
+// DigestReport is what a digest run produced
+type DigestReport struct {
+	Summary   Summary  `json:"summary"`
+	Files     []string `json:"files,omitempty"`
+	Delivered bool     `json:"delivered"`
+}
+
+// Digest summarises the previous day's events on a schedule and writes or
+// delivers the report
+type Digest struct {
+	recorder *Recorder
+	client   Client
+	notifier Notifier
+	config   DigestConfig
+	logger   *logrus.Entry
+}
+
+// NewDigest validates the configuration. notifier may be nil when no endpoint
+// is configured.
+func NewDigest(recorder *Recorder, client Client, notifier Notifier, config DigestConfig) (*Digest, error) {
+	if config.Schedule == "" {
+		config.Schedule = DefaultDigestSchedule
+	}
+	if _, err := cron.ParseStandard(config.Schedule); err != nil {
+		return nil, fmt.Errorf("invalid digest schedule %q: %w", config.Schedule, err)
+	}
+	if config.Dir == "" && config.Endpoint == "" {
+		return nil, errors.New("a digest directory or webhook endpoint is required")
+	}
+	if config.Endpoint != "" && notifier == nil {
+		return nil, fmt.Errorf("digest endpoint %s needs outbound webhooks to be configured", config.Endpoint)
+	}
+	if len(config.Formats) == 0 {
+		config.Formats = []string{FormatMarkdown, FormatHTML}
+	}
+	for _, format := range config.Formats {
+		if format != FormatMarkdown && format != FormatHTML {
+			return nil, fmt.Errorf("unknown digest format %q, expected %s or %s", format, FormatMarkdown, FormatHTML)
+		}
+	}
+	return &Digest{
+		recorder: recorder,
+		client:   client,
+		notifier: notifier,
+		config:   config,
+		logger:   logrus.WithField("component", "EventDigest"),
+	}, nil
+}
+
+// Start runs the digest on its schedule until ctx is cancelled
+func (d *Digest) Start(ctx context.Context) {
+	c := cron.New()
+	// The schedule was validated by NewDigest
+	_, _ = c.AddFunc(d.config.Schedule, func() {
+		if _, err := d.Run(ctx, time.Now()); err != nil {
+			d.logger.WithError(err).Error("Failed to produce event digest")
+		}
+	})
+	c.Start()
+	d.logger.WithField("schedule", d.config.Schedule).Info("Event digest scheduled")
+
+	go func() {
+		<-ctx.Done()
+		c.Stop()
+	}()
+}
+
+// Run summarises the 24 hours before now, writes the configured files and
+// queues delivery to the configured endpoint
+func (d *Digest) Run(ctx context.Context, now time.Time) (*DigestReport, error) {
+	opts := d.config.Options
+	names, err := DeviceNames(ctx, d.client)
+	if err != nil {
+		d.logger.WithError(err).Warn("Failed to load device names; the digest shows device IDs")
+	}
+	opts.Names = names
+
+	summary := d.recorder.Summarize(now.Add(-digestWindow), now, opts)
+	markdown := summary.Markdown()
+	html, err := summary.HTML()
+	if err != nil {
+		return nil, err
+	}
+	report := &DigestReport{Summary: summary}
+
+	if d.config.Dir != "" {
+		if err := os.MkdirAll(d.config.Dir, 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", d.config.Dir, err)
+		}
+		base := filepath.Join(d.config.Dir, "digest-"+summary.To.Format("2006-01-02"))
+		for _, format := range d.config.Formats {
+			path, content := base+".md", markdown
+			if format == FormatHTML {
+				path, content = base+".html", html
+			}
+			if err := os.WriteFile(path, []byte(content), 0o640); err != nil {
+				return nil, fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			report.Files = append(report.Files, path)
+		}
+	}
+
+	if d.config.Endpoint != "" {
+		err := d.notifier.Notify(d.config.Endpoint, DigestAction, map[string]interface{}{
+			"title":    summary.Title(),
+			"overview": summary.Overview(),
+			"markdown": markdown,
+			"html":     html,
+			"summary":  summary,
+		})
+		if err != nil {
+			return nil, err
+		}
+		report.Delivered = true
+	}
+
+	d.logger.WithFields(logrus.Fields{
+		"events":     summary.Events,
+		"highlights": len(summary.Highlights),
+		"files":      len(report.Files),
+	}).Info("Event digest produced")
+	return report, nil
+}
+
+// DeviceNames maps camera and sensor IDs to their names
+func DeviceNames(ctx context.Context, client Client) (map[string]string, error) {
+	names := map[string]string{}
+	cameras, err := client.GetCameras(ctx)
+	if err != nil {
+		return names, err
+	}
+	for _, c := range cameras {
+		names[c.ID] = c.Name
+	}
+	sensors, err := client.GetSensors(ctx)
+	if err != nil {
+		return names, err
+	}
+	for _, s := range sensors {
+		names[s.ID] = s.Name
+	}
+	return names, nil
+}
@@ -0,0 +1,233 @@
+package eventlog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func message(kind, id, eventType, device string, start time.Time, classes ...string) unifi.ProtectEventMessage {
+	return unifi.ProtectEventMessage{Type: kind, Item: unifi.ProtectEventItem{
+		ID:               id,
+		ModelKey:         "event",
+		Type:             eventType,
+		Start:            start.UnixMilli(),
+		Device:           device,
+		SmartDetectTypes: classes,
+	}}
+}
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	r, err := New(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().Truncate(time.Second)
+
+	r.Record(message("add", "e2", "smartDetectZone", "cam1", now.Add(-time.Minute), "person"))
+	r.Record(message("add", "e1", "motion", "cam1", now.Add(-2*time.Minute)))
+	r.Record(message("add", "old", "motion", "cam1", now.Add(-DefaultRetention-time.Hour)))
+	r.Record(message("update", "missing", "motion", "cam1", now))
+	r.Record(unifi.ProtectEventMessage{Type: "add", Item: unifi.ProtectEventItem{ID: "d1", ModelKey: "camera"}})
+
+	update := message("update", "e2", "smartDetectZone", "cam1", now.Add(-time.Minute), "vehicle")
+	end := now.UnixMilli()
+	update.Item.End = &end
+	r.Record(update)
+
+	if err := r.Save(); err != nil {
+		t.Fatal(err)
+	}
+	r, err = New(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := r.Events(now.Add(-time.Hour), now.Add(time.Second))
+	if len(events) != 2 || events[0].ID != "e1" || events[1].ID != "e2" {
+		t.Fatalf("expected e1 and e2 oldest first, got %+v", events)
+	}
+	if got := events[1].SmartDetectTypes; len(got) != 2 || got[0] != "person" || got[1] != "vehicle" {
+		t.Errorf("expected the update's classes to be merged, got %v", got)
+	}
+	if events[1].End == nil || !events[1].End.Equal(now) {
+		t.Errorf("expected the update's end to be recorded, got %v", events[1].End)
+	}
+	if since, _ := r.Since(); !since.Equal(now.Add(-2 * time.Minute)) {
+		t.Errorf("expected events past the retention to be dropped, oldest is %v", since)
+	}
+}
+
+// history builds a baseline of daytime person incidents on cam1, two a day
+// at 09:00 and 17:00, ending the day before day
+func history(day time.Time, days int) []Event {
+	var events []Event
+	for d := days; d >= 1; d-- {
+		for _, hour := range []int{9, 17} {
+			start := day.AddDate(0, 0, -d).Add(time.Duration(hour) * time.Hour)
+			events = append(events, Event{ID: start.String(), Type: "smartDetectZone", Device: "cam1", Start: start, SmartDetectTypes: []string{"person"}})
+		}
+	}
+	return events
+}
+
+func TestSummarize(t *testing.T) {
+	day := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	events := history(day, 14)
+	at := func(hour, minute, second int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second)
+	}
+	events = append(events,
+		// A burst of three events 30 seconds apart is one incident
+		Event{ID: "m1", Type: "motion", Device: "cam1", Start: at(9, 0, 0)},
+		Event{ID: "m2", Type: "smartDetectZone", Device: "cam1", Start: at(9, 0, 30), SmartDetectTypes: []string{"person"}, Score: 80},
+		Event{ID: "m3", Type: "motion", Device: "cam1", Start: at(9, 1, 0)},
+		// A vehicle at 03:10 is both at an unusual time and a rare class
+		Event{ID: "n1", Type: "smartDetectZone", Device: "cam1", Start: at(3, 10, 0), SmartDetectTypes: []string{"vehicle"}},
+		Event{ID: "r1", Type: "ring", Device: "door", Start: at(12, 0, 0)},
+		Event{ID: "r2", Type: "ring", Device: "door", Start: at(12, 10, 0)},
+	)
+	sort.Slice(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+
+	summary := Summarize(events, day, day.Add(24*time.Hour), Options{
+		Location:     time.UTC,
+		Names:        map[string]string{"cam1": "Driveway"},
+		MaxIncidents: 1,
+	})
+
+	if summary.Events != 6 || summary.Incidents != 4 {
+		t.Errorf("expected 6 events in 4 incidents, got %d in %d", summary.Events, summary.Incidents)
+	}
+	if summary.ByType["ring"] != 2 || summary.ByClass["person"] != 1 || summary.ByClass["vehicle"] != 1 {
+		t.Errorf("unexpected totals %v %v", summary.ByType, summary.ByClass)
+	}
+	if len(summary.Devices) != 2 || summary.Devices[0].Name != "Driveway" || summary.Devices[1].Name != "door" {
+		t.Fatalf("expected devices by activity with names, got %+v", summary.Devices)
+	}
+	cam := summary.Devices[0]
+	if cam.IncidentCount != 2 || len(cam.Incidents) != 1 {
+		t.Errorf("expected 2 incidents with 1 listed, got %d and %d", cam.IncidentCount, len(cam.Incidents))
+	}
+	if inc := cam.Incidents[0]; inc.Events != 1 || inc.Classes[0] != "vehicle" {
+		t.Errorf("expected the 03:10 incident first, got %+v", inc)
+	}
+
+	if len(summary.Highlights) != 2 {
+		t.Fatalf("expected 2 highlights, got %+v", summary.Highlights)
+	}
+	for _, h := range summary.Highlights {
+		if h.Device != "cam1" || h.Time.Hour() != 3 {
+			t.Errorf("expected only the 03:10 vehicle to stand out, got %+v", h)
+		}
+	}
+	if summary.Highlights[0].Kind != HighlightUnusualTime && summary.Highlights[1].Kind != HighlightUnusualTime {
+		t.Errorf("expected an unusual time highlight, got %+v", summary.Highlights)
+	}
+	// The doorbell has no baseline
+	if len(summary.Notes) != 1 || !strings.HasSuffix(summary.Notes[0], ": door") {
+		t.Errorf("expected a note about missing history, got %v", summary.Notes)
+	}
+
+	// A larger gap merges the rings
+	merged := Summarize(events, day, day.Add(24*time.Hour), Options{Location: time.UTC, Gap: 15 * time.Minute, Devices: []string{"door"}})
+	if merged.Incidents != 1 || len(merged.Devices) != 1 {
+		t.Errorf("expected the rings to merge into 1 incident, got %+v", merged)
+	}
+}
+
+func TestRender(t *testing.T) {
+	day := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	events := []Event{{ID: "e1", Type: "smartDetectZone", Device: "cam1", Start: day.Add(8 * time.Hour), SmartDetectTypes: []string{"person"}}}
+	summary := Summarize(events, day, day.Add(24*time.Hour), Options{Location: time.UTC, Names: map[string]string{"cam1": "<Front | Door>"}})
+
+	markdown := summary.Markdown()
+	for _, want := range []string{
+		"# Protect events 2026-10-17 00:00 to 2026-10-18 00:00 (UTC)",
+		"1 event in 1 incident across 1 device",
+		`| <Front \| Door> | 1 | 1 | smartDetectZone 1 | person 1 |`,
+		"- 08:00: 1 event, smartDetectZone (person)",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("expected %q in\n%s", want, markdown)
+		}
+	}
+
+	html, err := summary.HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, "&lt;Front | Door&gt;") || strings.Contains(html, "<Front") {
+		t.Errorf("expected device names to be escaped in\n%s", html)
+	}
+}
+
+type fakeNotifier struct {
+	endpoint string
+	data     interface{}
+}
+
+func (n *fakeNotifier) Notify(endpointID, action string, data interface{}) error {
+	n.endpoint = endpointID
+	n.data = data
+	return nil
+}
+
+type fakeClient struct{}
+
+func (fakeClient) GetCameras(ctx context.Context) ([]unifi.ProtectCamera, error) {
+	return []unifi.ProtectCamera{{ID: "cam1", Name: "Driveway"}}, nil
+}
+
+func (fakeClient) GetSensors(ctx context.Context) ([]unifi.ProtectSensor, error) {
+	return nil, nil
+}
+
+func TestDigest(t *testing.T) {
+	r, err := New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	r.Record(message("add", "e1", "smartDetectZone", "cam1", now.Add(-time.Hour), "person"))
+
+	if _, err := NewDigest(r, fakeClient{}, nil, DigestConfig{}); err == nil {
+		t.Error("expected a digest with no destination to be refused")
+	}
+	if _, err := NewDigest(r, fakeClient{}, nil, DigestConfig{Endpoint: "ops"}); err == nil {
+		t.Error("expected an endpoint without webhooks to be refused")
+	}
+	if _, err := NewDigest(r, fakeClient{}, nil, DigestConfig{Dir: "x", Schedule: "daily"}); err == nil {
+		t.Error("expected an invalid schedule to be refused")
+	}
+
+	dir := t.TempDir()
+	notifier := &fakeNotifier{}
+	digest, err := NewDigest(r, fakeClient{}, notifier, DigestConfig{Dir: dir, Endpoint: "ops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := digest.Run(context.Background(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Files) != 2 || !report.Delivered || notifier.endpoint != "ops" {
+		t.Fatalf("expected 2 files and a delivery, got %+v", report)
+	}
+	markdown, err := os.ReadFile(filepath.Join(dir, "digest-"+now.Format("2006-01-02")+".md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(markdown), "### Driveway") {
+		t.Errorf("expected the digest to name devices, got\n%s", markdown)
+	}
+	if data := notifier.data.(map[string]interface{}); data["markdown"] != string(markdown) {
+		t.Error("expected the delivered digest to match the written one")
+	}
+}
@@ -0,0 +1,217 @@
+// Package eventlog records Protect events from the events subscription and
+// summarises the recorded history into incidents, highlights and digests
+package eventlog
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/store"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// DefaultRetention is how long events are kept when no retention is given. It
+// covers the baseline that unusual activity is judged against.
+const DefaultRetention = 30 * 24 * time.Hour
+
+// saveInterval is how often new events are written to disk
+const saveInterval = time.Minute
+
+// Event is one recorded Protect event
+type Event struct {
+	ID               string     `json:"id"`
+	Type             string     `json:"type"`
+	Device           string     `json:"device"`
+	Start            time.Time  `json:"start"`
+	End              *time.Time `json:"end,omitempty"`
+	SmartDetectTypes []string   `json:"smart_detect_types,omitempty"`
+	Score            float64    `json:"score,omitempty"`
+}
+
+// end returns when the event finished, or its start while it is ongoing
+func (e Event) end() time.Time {
+	if e.End != nil && e.End.After(e.Start) {
+		return *e.End
+	}
+	return e.Start
+}
+
+// Recorder keeps a rolling history of Protect events in a local store
+type Recorder struct {
+	path      string
+	retention time.Duration
+	mu        sync.Mutex
+	events    []Event
+	index     map[string]int
+	dirty     bool
+	logger    *logrus.Entry
+}
+
+// New loads recorded history from dataDir
+func New(dataDir string, retention time.Duration) (*Recorder, error) {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	r := &Recorder{
+		path:      filepath.Join(dataDir, "events", "history.json"),
+		retention: retention,
+		logger:    logrus.WithField("component", "EventLog"),
+	}
+	if err := store.Load(r.path, &r.events); err != nil {
+		return nil, err
+	}
+	r.reindexLocked()
+	return r, nil
+}
+
+// Run records events until ctx is cancelled, saving new events periodically
+// and once more on shutdown
+func (r *Recorder) Run(ctx context.Context, events <-chan unifi.ProtectEventMessage) {
+	r.logger.WithField("retention", r.retention).Info("Event recorder started")
+	ticker := time.NewTicker(saveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.save()
+			return
+		case msg, ok := <-events:
+			if !ok {
+				r.save()
+				return
+			}
+			r.Record(msg)
+		case <-ticker.C:
+			r.save()
+		}
+	}
+}
+
+func (r *Recorder) save() {
+	if err := r.Save(); err != nil {
+		r.logger.WithError(err).Warn("Failed to save event history")
+	}
+}
+
+// Record adds an event from an add message, or merges an update into the
+// event it refers to
+func (r *Recorder) Record(msg unifi.ProtectEventMessage) {
+	item := msg.Item
+	if item.ID == "" || (item.ModelKey != "" && item.ModelKey != "event") {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i, ok := r.index[item.ID]; ok {
+		e := &r.events[i]
+		if item.End != nil {
+			end := time.UnixMilli(*item.End)
+			e.End = &end
+		}
+		for _, class := range item.SmartDetectTypes {
+			if !slices.Contains(e.SmartDetectTypes, class) {
+				e.SmartDetectTypes = append(e.SmartDetectTypes, class)
+			}
+		}
+		if item.Score > e.Score {
+			e.Score = item.Score
+		}
+		r.dirty = true
+		return
+	}
+	if msg.Type != "add" {
+		return
+	}
+
+	e := Event{
+		ID:               item.ID,
+		Type:             item.Type,
+		Device:           item.Device,
+		Start:            time.UnixMilli(item.Start),
+		SmartDetectTypes: append([]string(nil), item.SmartDetectTypes...),
+		Score:            item.Score,
+	}
+	if item.End != nil {
+		end := time.UnixMilli(*item.End)
+		e.End = &end
+	}
+	r.index[e.ID] = len(r.events)
+	r.events = append(r.events, e)
+	if n := len(r.events); n > 1 && e.Start.Before(r.events[n-2].Start) {
+		sort.SliceStable(r.events, func(i, j int) bool { return r.events[i].Start.Before(r.events[j].Start) })
+		r.reindexLocked()
+	}
+	r.dirty = true
+}
+
+// Save drops events older than the retention and persists the history if it
+// changed
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := time.Now().Add(-r.retention)
+	drop := sort.Search(len(r.events), func(i int) bool { return !r.events[i].Start.Before(cutoff) })
+	if drop > 0 {
+		r.events = append([]Event(nil), r.events[drop:]...)
+		r.reindexLocked()
+		r.dirty = true
+	}
+	if !r.dirty {
+		return nil
+	}
+	if err := store.Save(r.path, r.events); err != nil {
+		return err
+	}
+	r.dirty = false
+	return nil
+}
+
+// Events returns copies of the events that started in [from, to), oldest first
+func (r *Recorder) Events(from, to time.Time) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := sort.Search(len(r.events), func(i int) bool { return !r.events[i].Start.Before(from) })
+	var events []Event
+	for _, e := range r.events[start:] {
+		if !e.Start.Before(to) {
+			break
+		}
+		e.SmartDetectTypes = append([]string(nil), e.SmartDetectTypes...)
+		events = append(events, e)
+	}
+	return events
+}
+
+// Since returns when the oldest recorded event started
+func (r *Recorder) Since() (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.events) == 0 {
+		return time.Time{}, false
+	}
+	return r.events[0].Start, true
+}
+
+// Summarize summarises the events in [from, to) against the baseline before it
+func (r *Recorder) Summarize(from, to time.Time, opts Options) Summary {
+	opts = opts.withDefaults()
+	return Summarize(r.Events(from.Add(-opts.baseline()), to), from, to, opts)
+}
+
+func (r *Recorder) reindexLocked() {
+	r.index = make(map[string]int, len(r.events))
+	for i, e := range r.events {
+		r.index[e.ID] = i
+	}
+}
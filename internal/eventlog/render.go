@@ -0,0 +1,182 @@
+package eventlog
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Title describes the summary's window, e.g. "Protect events 2026-10-17 07:00
+// to 2026-10-18 07:00 (UTC)"
+func (s Summary) Title() string {
+	return fmt.Sprintf("Protect events %s to %s (%s)", s.From.Format("2006-01-02 15:04"), s.To.Format("2006-01-02 15:04"), s.Timezone)
+}
+
+// Markdown renders the summary as a Markdown report
+func (s Summary) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", s.Title())
+	fmt.Fprintf(&b, "%s.\n", s.Overview())
+	if len(s.ByClass) > 0 {
+		fmt.Fprintf(&b, "Smart detections: %s.\n", counts(s.ByClass))
+	}
+
+	if len(s.Highlights) > 0 {
+		b.WriteString("\n## Highlights\n\n")
+		for _, h := range s.Highlights {
+			fmt.Fprintf(&b, "- **%s** %s: %s\n", markdownEscape(h.Name), h.Time.Format("15:04"), markdownEscape(h.Reason))
+		}
+	}
+	for _, note := range s.Notes {
+		fmt.Fprintf(&b, "\n_%s._\n", markdownEscape(note))
+	}
+	if len(s.Devices) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\n## Devices\n\n")
+	b.WriteString("| Device | Events | Incidents | Types | Smart detections |\n")
+	b.WriteString("|--------|--------|-----------|-------|------------------|\n")
+	for _, d := range s.Devices {
+		fmt.Fprintf(&b, "| %s | %d | %d | %s | %s |\n", markdownEscape(d.Name), d.Events, d.IncidentCount, counts(d.ByType), counts(d.ByClass))
+	}
+
+	b.WriteString("\n## Incidents\n")
+	for _, d := range s.Devices {
+		fmt.Fprintf(&b, "\n### %s\n\n", markdownEscape(d.Name))
+		for _, inc := range d.Incidents {
+			fmt.Fprintf(&b, "- %s: %s\n", incidentTime(inc), markdownEscape(incidentText(inc)))
+		}
+		if more := d.IncidentCount - len(d.Incidents); more > 0 {
+			fmt.Fprintf(&b, "- %d more\n", more)
+		}
+	}
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"counts":       counts,
+	"incidentTime": incidentTime,
+	"incidentText": incidentText,
+	"clock":        func(t time.Time) string { return t.Format("15:04") },
+	"more":         func(d DeviceSummary) int { return d.IncidentCount - len(d.Incidents) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.highlight { color: #a40; }
+.note { color: #666; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Overview}}.{{if .ByClass}} Smart detections: {{counts .ByClass}}.{{end}}</p>
+{{- if .Highlights}}
+<h2>Highlights</h2>
+<ul>
+{{- range .Highlights}}
+<li class="highlight"><strong>{{.Name}}</strong> {{clock .Time}}: {{.Reason}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- range .Notes}}
+<p class="note">{{.}}.</p>
+{{- end}}
+{{- if .Devices}}
+<h2>Devices</h2>
+<table>
+<tr><th>Device</th><th>Events</th><th>Incidents</th><th>Types</th><th>Smart detections</th></tr>
+{{- range .Devices}}
+<tr><td>{{.Name}}</td><td>{{.Events}}</td><td>{{.IncidentCount}}</td><td>{{counts .ByType}}</td><td>{{counts .ByClass}}</td></tr>
+{{- end}}
+</table>
+<h2>Incidents</h2>
+{{- range .Devices}}
+<h3>{{.Name}}</h3>
+<ul>
+{{- range .Incidents}}
+<li>{{incidentTime .}}: {{incidentText .}}</li>
+{{- end}}
+{{- if gt (more .) 0}}
+<li>{{more .}} more</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML renders the summary as a standalone HTML page
+func (s Summary) HTML() (string, error) {
+	var b bytes.Buffer
+	if err := htmlTemplate.Execute(&b, s); err != nil {
+		return "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	return b.String(), nil
+}
+
+// Overview is the one line summary of the window, e.g. "42 events in 12
+// incidents across 3 devices" followed by the incident gap
+func (s Summary) Overview() string {
+	if s.Events == 0 {
+		return "No events"
+	}
+	return fmt.Sprintf("%s in %s across %s (events less than %s apart are one incident)",
+		plural(s.Events, "event"), plural(s.Incidents, "incident"), plural(len(s.Devices), "device"), s.Gap)
+}
+
+// counts formats a count map, largest first, e.g. "motion 12, ring 2"
+func counts(m map[string]int) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if m[keys[i]] != m[keys[j]] {
+			return m[keys[i]] > m[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s %d", k, m[k])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func incidentTime(inc Incident) string {
+	if inc.End.Sub(inc.Start) < time.Minute {
+		return inc.Start.Format("15:04")
+	}
+	return inc.Start.Format("15:04") + "-" + inc.End.Format("15:04")
+}
+
+func incidentText(inc Incident) string {
+	text := plural(inc.Events, "event") + ", " + describeIncident(inc)
+	if inc.MaxScore > 0 {
+		text += fmt.Sprintf(", score %.0f", inc.MaxScore)
+	}
+	return text
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+var markdownReplacer = strings.NewReplacer("|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`")
+
+func markdownEscape(s string) string {
+	return markdownReplacer.Replace(s)
+}
@@ -0,0 +1,314 @@
+package eventlog
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Defaults for summaries
+const (
+	DefaultGap          = 2 * time.Minute
+	DefaultBaselineDays = 14
+)
+
+// Highlight thresholds. A device needs minBaseline incidents in the baseline
+// before its activity is judged unusual; an hour of day or smart detect class
+// is unusual when it accounts for less than unusualShare of that history.
+const (
+	minBaseline  = 20
+	unusualShare = 0.01
+)
+
+// Highlight kinds
+const (
+	HighlightUnusualTime = "unusual_time"
+	HighlightRareClass   = "rare_class"
+)
+
+// Options control how events are summarised
+type Options struct {
+	// Gap is the longest quiet spell between events of one incident
+	Gap time.Duration
+	// BaselineDays is how many days before the window unusual activity is
+	// judged against
+	BaselineDays int
+	// Location is the time zone for hours of day; defaults to local time
+	Location *time.Location
+	// Names maps device IDs to display names
+	Names map[string]string
+	// Devices and Types limit the summary when set
+	Devices []string
+	Types   []string
+	// MaxIncidents caps the incidents listed per device; 0 lists all
+	MaxIncidents int
+}
+
+func (o Options) withDefaults() Options {
+	if o.Gap <= 0 {
+		o.Gap = DefaultGap
+	}
+	if o.BaselineDays <= 0 {
+		o.BaselineDays = DefaultBaselineDays
+	}
+	if o.Location == nil {
+		o.Location = time.Local
+	}
+	return o
+}
+
+func (o Options) baseline() time.Duration {
+	return time.Duration(o.BaselineDays) * 24 * time.Hour
+}
+
+func (o Options) includes(e Event) bool {
+	return (len(o.Devices) == 0 || slices.Contains(o.Devices, e.Device)) &&
+		(len(o.Types) == 0 || slices.Contains(o.Types, e.Type))
+}
+
+// Summary groups the events of a window by device, type and smart detect
+// class, with bursts collapsed into incidents
+type Summary struct {
+	From         time.Time       `json:"from"`
+	To           time.Time       `json:"to"`
+	Timezone     string          `json:"timezone"`
+	Gap          string          `json:"incident_gap"`
+	BaselineDays int             `json:"baseline_days"`
+	Events       int             `json:"events"`
+	Incidents    int             `json:"incidents"`
+	ByType       map[string]int  `json:"by_type"`
+	ByClass      map[string]int  `json:"by_class"`
+	Devices      []DeviceSummary `json:"devices"`
+	Highlights   []Highlight     `json:"highlights"`
+	Notes        []string        `json:"notes,omitempty"`
+	location     *time.Location
+	names        map[string]string
+}
+
+// DeviceSummary is the activity of one camera or sensor
+type DeviceSummary struct {
+	Device        string         `json:"device"`
+	Name          string         `json:"name"`
+	Events        int            `json:"events"`
+	ByType        map[string]int `json:"by_type"`
+	ByClass       map[string]int `json:"by_class,omitempty"`
+	IncidentCount int            `json:"incident_count"`
+	Incidents     []Incident     `json:"incidents"`
+}
+
+// Incident is a burst of events on one device with no gap longer than the
+// summary's incident gap
+type Incident struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Events   int       `json:"events"`
+	Types    []string  `json:"types"`
+	Classes  []string  `json:"classes,omitempty"`
+	MaxScore float64   `json:"max_score,omitempty"`
+}
+
+// Highlight is activity that stands out from the device's baseline
+type Highlight struct {
+	Kind   string    `json:"kind"`
+	Device string    `json:"device"`
+	Name   string    `json:"name"`
+	Time   time.Time `json:"time"`
+	Class  string    `json:"class,omitempty"`
+	Reason string    `json:"reason"`
+}
+
+// Summarize summarises the events, oldest first, that started in [from, to).
+// Earlier events within the baseline are used to judge unusual activity.
+func Summarize(events []Event, from, to time.Time, opts Options) Summary {
+	opts = opts.withDefaults()
+	summary := Summary{
+		From:         from.In(opts.Location),
+		To:           to.In(opts.Location),
+		Timezone:     opts.Location.String(),
+		Gap:          opts.Gap.String(),
+		BaselineDays: opts.BaselineDays,
+		ByType:       map[string]int{},
+		ByClass:      map[string]int{},
+		Devices:      []DeviceSummary{},
+		Highlights:   []Highlight{},
+		location:     opts.Location,
+		names:        opts.Names,
+	}
+
+	baselineFrom := from.Add(-opts.baseline())
+	window := map[string][]Event{}
+	baseline := map[string][]Event{}
+	for _, e := range events {
+		if !opts.includes(e) {
+			continue
+		}
+		switch {
+		case !e.Start.Before(from) && e.Start.Before(to):
+			window[e.Device] = append(window[e.Device], e)
+		case !e.Start.Before(baselineFrom) && e.Start.Before(from):
+			baseline[e.Device] = append(baseline[e.Device], e)
+		}
+	}
+
+	var short []string
+	for device, deviceEvents := range window {
+		ds := DeviceSummary{
+			Device: device,
+			Name:   summary.name(device),
+			Events: len(deviceEvents),
+			ByType: map[string]int{},
+		}
+		for _, e := range deviceEvents {
+			ds.ByType[e.Type]++
+			summary.ByType[e.Type]++
+			for _, class := range e.SmartDetectTypes {
+				if ds.ByClass == nil {
+					ds.ByClass = map[string]int{}
+				}
+				ds.ByClass[class]++
+				summary.ByClass[class]++
+			}
+		}
+		incidents := groupIncidents(deviceEvents, opts.Gap)
+		ds.IncidentCount = len(incidents)
+		ds.Incidents = incidents
+		if opts.MaxIncidents > 0 && len(incidents) > opts.MaxIncidents {
+			ds.Incidents = incidents[:opts.MaxIncidents]
+		}
+		for i := range ds.Incidents {
+			ds.Incidents[i].Start = ds.Incidents[i].Start.In(opts.Location)
+			ds.Incidents[i].End = ds.Incidents[i].End.In(opts.Location)
+		}
+
+		summary.Events += ds.Events
+		summary.Incidents += ds.IncidentCount
+		summary.Devices = append(summary.Devices, ds)
+
+		history := baseline[device]
+		historyIncidents := groupIncidents(history, opts.Gap)
+		if len(historyIncidents) < minBaseline {
+			short = append(short, ds.Name)
+			continue
+		}
+		summary.Highlights = append(summary.Highlights, summary.unusualTimes(ds, incidents, historyIncidents)...)
+		summary.Highlights = append(summary.Highlights, summary.rareClasses(ds, deviceEvents, history)...)
+	}
+
+	sort.Slice(summary.Devices, func(i, j int) bool {
+		a, b := summary.Devices[i], summary.Devices[j]
+		if a.Events != b.Events {
+			return a.Events > b.Events
+		}
+		return a.Name < b.Name
+	})
+	sort.SliceStable(summary.Highlights, func(i, j int) bool {
+		return summary.Highlights[i].Time.Before(summary.Highlights[j].Time)
+	})
+	if len(short) > 0 {
+		sort.Strings(short)
+		summary.Notes = append(summary.Notes, fmt.Sprintf("Not checked for unusual times or classes, with fewer than %d incidents in the previous %d days: %s", minBaseline, opts.BaselineDays, strings.Join(short, ", ")))
+	}
+	return summary
+}
+
+// groupIncidents collapses events, oldest first, into incidents
+func groupIncidents(events []Event, gap time.Duration) []Incident {
+	var incidents []Incident
+	for _, e := range events {
+		n := len(incidents)
+		if n == 0 || e.Start.Sub(incidents[n-1].End) > gap {
+			incidents = append(incidents, Incident{Start: e.Start, End: e.end()})
+			n++
+		}
+		inc := &incidents[n-1]
+		if end := e.end(); end.After(inc.End) {
+			inc.End = end
+		}
+		inc.Events++
+		if !slices.Contains(inc.Types, e.Type) {
+			inc.Types = append(inc.Types, e.Type)
+		}
+		for _, class := range e.SmartDetectTypes {
+			if !slices.Contains(inc.Classes, class) {
+				inc.Classes = append(inc.Classes, class)
+			}
+		}
+		if e.Score > inc.MaxScore {
+			inc.MaxScore = e.Score
+		}
+	}
+	return incidents
+}
+
+// unusualTimes flags incidents that start in an hour of day the device is
+// rarely active in
+func (s *Summary) unusualTimes(ds DeviceSummary, incidents, history []Incident) []Highlight {
+	var hours [24]int
+	for _, inc := range history {
+		hours[inc.Start.In(s.location).Hour()]++
+	}
+	var highlights []Highlight
+	for _, inc := range incidents {
+		start := inc.Start.In(s.location)
+		hour := start.Hour()
+		if float64(hours[hour]) >= unusualShare*float64(len(history)) {
+			continue
+		}
+		highlights = append(highlights, Highlight{
+			Kind:   HighlightUnusualTime,
+			Device: ds.Device,
+			Name:   ds.Name,
+			Time:   start,
+			Reason: fmt.Sprintf("%s at %s; %d of %s in the previous %d days started between %02d:00 and %02d:00", describeIncident(inc), start.Format("15:04"), hours[hour], plural(len(history), "incident"), s.BaselineDays, hour, (hour+1)%24),
+		})
+	}
+	return highlights
+}
+
+// rareClasses flags smart detect classes the device has rarely reported
+func (s *Summary) rareClasses(ds DeviceSummary, events, history []Event) []Highlight {
+	seen := map[string]int{}
+	for _, e := range history {
+		for _, class := range e.SmartDetectTypes {
+			seen[class]++
+		}
+	}
+	var highlights []Highlight
+	flagged := map[string]bool{}
+	for _, e := range events {
+		for _, class := range e.SmartDetectTypes {
+			if flagged[class] || float64(seen[class]) >= unusualShare*float64(len(history)) {
+				continue
+			}
+			flagged[class] = true
+			highlights = append(highlights, Highlight{
+				Kind:   HighlightRareClass,
+				Device: ds.Device,
+				Name:   ds.Name,
+				Time:   e.Start.In(s.location),
+				Class:  class,
+				Reason: fmt.Sprintf("%s detected %s; seen in %d of %s in the previous %d days", class, plural(ds.ByClass[class], "time"), seen[class], plural(len(history), "event"), s.BaselineDays),
+			})
+		}
+	}
+	return highlights
+}
+
+func (s *Summary) name(device string) string {
+	if name := s.names[device]; name != "" {
+		return name
+	}
+	return device
+}
+
+// describeIncident names an incident by its event types and classes, e.g.
+// "motion, smartDetectZone (person)"
+func describeIncident(inc Incident) string {
+	text := strings.Join(inc.Types, ", ")
+	if len(inc.Classes) > 0 {
+		text += " (" + strings.Join(inc.Classes, ", ") + ")"
+	}
+	return text
+}
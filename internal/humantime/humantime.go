@@ -0,0 +1,122 @@
+// Package humantime parses human friendly durations and deadlines such as
+// "for 2 hours" or "until 8am"
+package humantime
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	amountPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([a-z]+)$`)
+	clockPattern  = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+var units = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+}
+
+// Deadline resolves expr relative to now. It accepts durations ("for 2 hours",
+// "90 minutes", "1h30m") and clock times ("until 8am", "until 20:30"), which
+// resolve to their next occurrence. forever is true for "forever" or "never".
+func Deadline(expr string, now time.Time) (deadline time.Time, forever bool, err error) {
+	s := strings.ToLower(strings.TrimSpace(expr))
+	switch s {
+	case "":
+		return time.Time{}, false, fmt.Errorf("empty duration")
+	case "forever", "never", "indefinitely", "permanently":
+		return time.Time{}, true, nil
+	}
+
+	if rest, ok := strings.CutPrefix(s, "until "); ok {
+		t, err := clock(strings.TrimSpace(rest), now)
+		return t, false, err
+	}
+
+	d, err := Duration(s)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return now.Add(d), false, nil
+}
+
+// Duration parses "for 2 hours", "2 hours", "90m" or Go duration syntax
+func Duration(expr string) (time.Duration, error) {
+	s := strings.ToLower(strings.TrimSpace(expr))
+	s = strings.TrimPrefix(s, "for ")
+	s = strings.TrimPrefix(s, "an ")
+	s = strings.TrimPrefix(s, "a ")
+	if s == "hour" || s == "minute" || s == "day" {
+		return units[s], nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return 0, fmt.Errorf("duration must be positive")
+		}
+		return d, nil
+	}
+
+	m := amountPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("unrecognised duration %q", expr)
+	}
+	unit, ok := units[m[2]]
+	if !ok {
+		return 0, fmt.Errorf("unrecognised duration unit %q", m[2])
+	}
+	amount, err := strconv.ParseFloat(m[1], 64)
+	if err != nil || amount <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	return time.Duration(amount * float64(unit)), nil
+}
+
+// clock returns the next occurrence of a wall clock time after now
+func clock(expr string, now time.Time) (time.Time, error) {
+	switch expr {
+	case "noon":
+		expr = "12pm"
+	case "midnight":
+		expr = "12am"
+	}
+
+	m := clockPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("unrecognised time %q", expr)
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return time.Time{}, fmt.Errorf("invalid hour in %q", expr)
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return time.Time{}, fmt.Errorf("invalid hour in %q", expr)
+		}
+	}
+	if minute > 59 {
+		return time.Time{}, fmt.Errorf("invalid minute in %q", expr)
+	}
+
+	t := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
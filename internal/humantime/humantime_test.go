@@ -0,0 +1,43 @@
+package humantime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadline(t *testing.T) {
+	now := time.Date(2025, 6, 1, 21, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr    string
+		want    time.Time
+		forever bool
+	}{
+		{"for 2 hours", now.Add(2 * time.Hour), false},
+		{"90 minutes", now.Add(90 * time.Minute), false},
+		{"1h30m", now.Add(90 * time.Minute), false},
+		{"for an hour", now.Add(time.Hour), false},
+		{"until 8am", time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC), false},
+		{"until 10:30pm", time.Date(2025, 6, 1, 22, 30, 0, 0, time.UTC), false},
+		{"until 21:00", time.Date(2025, 6, 2, 21, 0, 0, 0, time.UTC), false},
+		{"until midnight", time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), false},
+		{"forever", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, forever, err := Deadline(tt.expr, now)
+		if err != nil {
+			t.Errorf("Deadline(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		if forever != tt.forever || !got.Equal(tt.want) {
+			t.Errorf("Deadline(%q) = %v, %v; want %v, %v", tt.expr, got, forever, tt.want, tt.forever)
+		}
+	}
+
+	for _, bad := range []string{"", "soon", "until 25:00", "for -2 hours", "3 fortnights"} {
+		if _, _, err := Deadline(bad, now); err == nil {
+			t.Errorf("Deadline(%q) expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package inventory
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// Output formats supported by Render
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+)
+
+// CSV renders items as CSV with a header row
+func CSV(items []Item) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(Columns); err != nil {
+		return "", err
+	}
+	for _, it := range items {
+		if err := w.Write(it.values()); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Markdown renders items as a Markdown table
+func Markdown(items []Item) string {
+	var sb strings.Builder
+	sb.WriteString("| " + strings.Join(Columns, " | ") + " |\n")
+	sb.WriteString("|" + strings.Repeat("---|", len(Columns)) + "\n")
+	for _, it := range items {
+		cells := it.values()
+		for i, c := range cells {
+			cells[i] = markdownEscaper.Replace(c)
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return sb.String()
+}
+
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\n", " ")
@@ -0,0 +1,159 @@
+// Package inventory builds a normalized list of every Protect device and
+// renders it for reporting
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// Client is the subset of the Protect client used to collect the inventory
+type Client interface {
+	ListDevicesRaw(ctx context.Context, modelKey string) ([]map[string]interface{}, error)
+}
+
+// Item is one device in the normalized inventory schema
+type Item struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Model    string `json:"model,omitempty"`
+	Name     string `json:"name"`
+	MAC      string `json:"mac,omitempty"`
+	State    string `json:"state,omitempty"`
+	Firmware string `json:"firmware,omitempty"`
+}
+
+// Columns is the field order used by the CSV and Markdown renderers
+var Columns = []string{"id", "type", "model", "name", "mac", "state", "firmware"}
+
+func (it Item) values() []string {
+	return []string{it.ID, it.Type, it.Model, it.Name, it.MAC, it.State, it.Firmware}
+}
+
+// Collect fetches every listable device class concurrently. Classes that fail
+// are reported in errs while the rest of the inventory is still returned.
+func Collect(ctx context.Context, client Client) (items []Item, errs map[string]string) {
+	keys := unifi.ListableModelKeys()
+	results := make([][]Item, len(keys))
+	failures := make([]error, len(keys))
+
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			raw, err := client.ListDevicesRaw(ctx, key)
+			if err != nil {
+				failures[i] = err
+				return
+			}
+			for _, obj := range raw {
+				results[i] = append(results[i], Normalize(key, obj))
+			}
+		}(i, key)
+	}
+	wg.Wait()
+
+	errs = map[string]string{}
+	for i, key := range keys {
+		if failures[i] != nil {
+			errs[key] = failures[i].Error()
+		}
+		items = append(items, results[i]...)
+	}
+	Sort(items)
+	return items, errs
+}
+
+// Normalize maps a raw API object to an inventory item. Model and firmware are
+// taken from whichever field the controller version reports.
+func Normalize(modelKey string, obj map[string]interface{}) Item {
+	it := Item{
+		ID:       str(obj, "id"),
+		Type:     str(obj, "modelKey"),
+		Name:     str(obj, "name"),
+		MAC:      str(obj, "mac"),
+		State:    str(obj, "state"),
+		Model:    first(obj, "marketName", "model", "type"),
+		Firmware: first(obj, "firmwareVersion", "firmware", "version"),
+	}
+	if it.Type == "" {
+		it.Type = modelKey
+	}
+	if it.Type == unifi.ModelKeyNVR && it.State == "" {
+		it.State = unifi.DeviceStateConnected
+	}
+	return it
+}
+
+// Filter selects inventory items; empty fields match everything. Name is a
+// case-insensitive glob and Model a case-insensitive substring.
+type Filter struct {
+	State string
+	Type  string
+	Model string
+	Name  string
+}
+
+// Validate checks the name glob is well formed
+func (f Filter) Validate() error {
+	if f.Name != "" {
+		if _, err := path.Match(f.Name, ""); err != nil {
+			return fmt.Errorf("invalid name pattern %q: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// Apply returns the items matching the filter
+func (f Filter) Apply(items []Item) []Item {
+	out := []Item{}
+	for _, it := range items {
+		if f.State != "" && !strings.EqualFold(it.State, f.State) {
+			continue
+		}
+		if f.Type != "" && !strings.EqualFold(it.Type, f.Type) {
+			continue
+		}
+		if f.Model != "" && !strings.Contains(strings.ToLower(it.Model), strings.ToLower(f.Model)) {
+			continue
+		}
+		if f.Name != "" {
+			if ok, _ := path.Match(strings.ToLower(f.Name), strings.ToLower(it.Name)); !ok {
+				continue
+			}
+		}
+		out = append(out, it)
+	}
+	return out
+}
+
+// Sort orders items by type then name
+func Sort(items []Item) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Type != items[j].Type {
+			return items[i].Type < items[j].Type
+		}
+		return items[i].Name < items[j].Name
+	})
+}
+
+func str(obj map[string]interface{}, key string) string {
+	v, _ := obj[key].(string)
+	return v
+}
+
+func first(obj map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if v := str(obj, key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
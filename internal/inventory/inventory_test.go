@@ -0,0 +1,60 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeClient map[string][]map[string]interface{}
+
+func (f fakeClient) ListDevicesRaw(ctx context.Context, modelKey string) ([]map[string]interface{}, error) {
+	if modelKey == "viewer" {
+		return nil, errors.New("boom")
+	}
+	return f[modelKey], nil
+}
+
+func TestCollectFilterAndRender(t *testing.T) {
+	client := fakeClient{
+		"nvr": {{"id": "nvr1", "modelKey": "nvr", "name": "UNVR"}},
+		"camera": {
+			{"id": "c1", "modelKey": "camera", "name": "Front Door", "state": "CONNECTED", "marketName": "G4 Doorbell Pro", "firmwareVersion": "4.69.55", "mac": "AA"},
+			{"id": "c2", "modelKey": "camera", "name": "Back|Yard", "state": "DISCONNECTED", "marketName": "G5 Bullet"},
+		},
+		"light": {{"id": "l1", "modelKey": "light", "name": "Drive", "state": "CONNECTED"}},
+	}
+
+	items, errs := Collect(context.Background(), client)
+	if len(items) != 4 {
+		t.Fatalf("expected 4 items, got %+v", items)
+	}
+	if errs["viewer"] == "" || len(errs) != 1 {
+		t.Errorf("expected the viewer failure to be reported, got %v", errs)
+	}
+	if items[0].ID != "c2" || items[3].Type != "nvr" || items[3].State != "CONNECTED" {
+		t.Errorf("unexpected order or NVR normalization: %+v", items)
+	}
+
+	if got := (Filter{State: "disconnected"}).Apply(items); len(got) != 1 || got[0].ID != "c2" {
+		t.Errorf("state filter: %+v", got)
+	}
+	if got := (Filter{Name: "front*"}).Apply(items); len(got) != 1 || got[0].Firmware != "4.69.55" {
+		t.Errorf("name filter: %+v", got)
+	}
+	if got := (Filter{Model: "g4"}).Apply(items); len(got) != 1 || got[0].Model != "G4 Doorbell Pro" {
+		t.Errorf("model filter: %+v", got)
+	}
+
+	csv, err := CSV(items[:1])
+	if err != nil {
+		t.Fatalf("CSV failed: %v", err)
+	}
+	if !strings.HasPrefix(csv, "id,type,model,name,mac,state,firmware\nc2,camera,G5 Bullet,Back|Yard,") {
+		t.Errorf("unexpected CSV:\n%s", csv)
+	}
+	if md := Markdown(items[:1]); !strings.Contains(md, "Back\\|Yard") {
+		t.Errorf("expected escaped pipe in Markdown:\n%s", md)
+	}
+}
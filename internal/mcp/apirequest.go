@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/docs"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/openapi"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/toolgen"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// protectSpec is the embedded integration API description
+var protectSpec = sync.OnceValues(func() (*openapi.Spec, error) {
+	return openapi.Parse(docs.ProtectIntegration)
+})
+
+// operationCapabilities lists the operations that need an optional part of
+// the API, by path template
+var operationCapabilities = map[string]unifi.Capability{
+	"/v1/cameras/{id}/ptz/goto/{slot}":         unifi.CapabilityPTZ,
+	"/v1/cameras/{id}/ptz/patrol/start/{slot}": unifi.CapabilityPTZ,
+	"/v1/cameras/{id}/ptz/patrol/stop":         unifi.CapabilityPTZ,
+	"/v1/cameras/{id}/talkback-session":        unifi.CapabilityTalkback,
+	"/v1/cameras/{id}/snapshot":                unifi.CapabilitySnapshot,
+	"/v1/files/{fileType}":                     unifi.CapabilityFiles,
+}
+
+// apiOperations returns the operations the policy lets protect_api_request
+// call, sorted by ID
+func (s *Server) apiOperations(spec *openapi.Spec) []openapi.Endpoint {
+	var list []openapi.Endpoint
+	for _, ep := range spec.Endpoints() {
+		if toolgen.Unsupported(&ep) == "" && s.policy.allowsOperation(&ep, spec) {
+			list = append(list, ep)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].OperationID < list[j].OperationID })
+	return list
+}
+
+// apiRequestDescription lists each callable operation so the assistant can
+// pick one without reading the spec
+func apiRequestDescription(operations []openapi.Endpoint) string {
+	var b strings.Builder
+	b.WriteString("Call any Protect integration API operation by its operation ID. Path parameters, query and body are checked against the API spec before anything is sent. Operations:")
+	for _, ep := range operations {
+		fmt.Fprintf(&b, "\n- %s: %s %s (%s)", ep.OperationID, ep.Method, ep.Path, ep.Summary)
+	}
+	return b.String()
+}
+
+func (s *Server) protectAPIRequest(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: protect_api_request")
+
+	operationID := request.GetString("operation_id", "")
+	if operationID == "" {
+		return mcp.NewToolResultError("Missing required parameter: operation_id"), nil
+	}
+	args := request.GetArguments()
+	pathParams, _ := args["path_params"].(map[string]interface{})
+	query, _ := args["query"].(map[string]interface{})
+	return s.callOperation(ctx, operationID, pathParams, query, args["body"])
+}
+
+// callOperation checks an operation call against the spec and the server's
+// policy, then sends it. It backs protect_api_request and the generated
+// operation tools.
+func (s *Server) callOperation(ctx context.Context, operationID string, pathParams, queryParams map[string]interface{}, bodyArg interface{}) (*mcp.CallToolResult, error) {
+	spec, err := protectSpec()
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to load the API spec", err), nil
+	}
+	ep, ok := spec.Operation(operationID)
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("unknown operation %s; see the protect_api_request description for the operation IDs", operationID)), nil
+	}
+	if reason := toolgen.Unsupported(ep); reason != "" {
+		return mcp.NewToolResultError(fmt.Sprintf("%s cannot be called as a tool: %s", operationID, reason)), nil
+	}
+	if !s.policy.allowsOperation(ep, spec) {
+		return mcp.NewToolResultError(fmt.Sprintf("%s (%s %s) is not allowed by the server's tool policy", operationID, ep.Method, ep.Path)), nil
+	}
+	if capability, ok := operationCapabilities[ep.Path]; ok {
+		if err := s.protectClient.RequireCapability(capability); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	params := map[string]string{}
+	for name, v := range pathParams {
+		params[name] = paramString(v)
+	}
+	path, err := ep.Expand(params)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	query := url.Values{}
+	for name, v := range queryParams {
+		if items, ok := v.([]interface{}); ok {
+			for _, item := range items {
+				query.Add(name, paramString(item))
+			}
+			continue
+		}
+		query.Set(name, paramString(v))
+	}
+	var body []byte
+	if bodyArg != nil {
+		if body, err = json.Marshal(bodyArg); err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to encode body", err), nil
+		}
+	}
+
+	_, errs := openapi.NewChecker(spec, openapi.ProtectQuirks...).CheckRequest(ep.Method, path, query, "application/json", body)
+	if len(errs) > 0 {
+		problems := make([]string, len(errs))
+		for i, err := range errs {
+			problems[i] = err.Error()
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("request does not match the %s operation: %s", operationID, strings.Join(problems, "; "))), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+	resp, err := s.protectClient.Request(ctx, ep.Method, path, query, body)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to call "+operationID, err), nil
+	}
+
+	result := map[string]interface{}{
+		"operation_id": operationID,
+		"method":       ep.Method,
+		"path":         path,
+		"status":       resp.Status,
+	}
+	if len(resp.Body) > 0 {
+		mediaType, _, _ := mime.ParseMediaType(resp.ContentType)
+		var decoded interface{}
+		if mediaType == "application/json" && json.Unmarshal(resp.Body, &decoded) == nil {
+			result["response"] = decoded
+		} else {
+			result["content_type"] = resp.ContentType
+			result["response_base64"] = base64.StdEncoding.EncodeToString(resp.Body)
+		}
+	}
+	return mcp.NewToolResultJSON(result)
+}
+
+// paramString formats a JSON argument as a path or query parameter value
+func paramString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// selectCameras resolves a camera selection from explicit IDs and/or a case
+// insensitive name glob such as "*outdoor*". With neither set, every camera is
+// selected.
+func (s *Server) selectCameras(ctx context.Context, ids []string, namePattern string) ([]unifi.ProtectCamera, error) {
+	if namePattern != "" {
+		if _, err := path.Match(namePattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid name pattern %q: %w", namePattern, err)
+		}
+	}
+
+	cameras, err := s.protectClient.GetCameras(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := map[string]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var selected []unifi.ProtectCamera
+	for _, camera := range cameras {
+		if len(ids) > 0 && !wanted[camera.ID] {
+			continue
+		}
+		if namePattern != "" {
+			if ok, _ := path.Match(strings.ToLower(namePattern), strings.ToLower(camera.Name)); !ok {
+				continue
+			}
+		}
+		delete(wanted, camera.ID)
+		selected = append(selected, camera)
+	}
+
+	if namePattern == "" {
+		for id := range wanted {
+			return nil, fmt.Errorf("camera %s not found", id)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no cameras match the selection")
+	}
+	return selected, nil
+}
+
+// cameraResolver maps camera names or IDs to camera IDs
+type cameraResolver struct {
+	byID   map[string]bool
+	byName map[string][]string
+}
+
+func (s *Server) newCameraResolver(ctx context.Context) (*cameraResolver, error) {
+	cameras, err := s.protectClient.GetCameras(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r := &cameraResolver{byID: map[string]bool{}, byName: map[string][]string{}}
+	for _, camera := range cameras {
+		r.byID[camera.ID] = true
+		name := strings.ToLower(camera.Name)
+		r.byName[name] = append(r.byName[name], camera.ID)
+	}
+	return r, nil
+}
+
+// resolve returns the ID of the camera with the given ID or case-insensitive name
+func (r *cameraResolver) resolve(ref string) (string, error) {
+	if r.byID[ref] {
+		return ref, nil
+	}
+	ids := r.byName[strings.ToLower(ref)]
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("camera %q not found", ref)
+	case 1:
+		return ids[0], nil
+	}
+	return "", fmt.Errorf("camera name %q is ambiguous, use one of the IDs %v", ref, ids)
+}
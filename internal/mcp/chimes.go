@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/humantime"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/scheduler"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func (s *Server) pairChimeDoorbell(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: pair_chime_doorbell")
+
+	chimeID := request.GetString("chime_id", "")
+	cameraID := request.GetString("camera_id", "")
+	if chimeID == "" || cameraID == "" {
+		return mcp.NewToolResultError("chime_id and camera_id are required"), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	chime, err := s.protectClient.PairChimeDoorbell(ctx, chimeID, cameraID)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to pair doorbell", err), nil
+	}
+	return mcp.NewToolResultJSON(chime)
+}
+
+func (s *Server) unpairChimeDoorbell(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: unpair_chime_doorbell")
+
+	chimeID := request.GetString("chime_id", "")
+	cameraID := request.GetString("camera_id", "")
+	if chimeID == "" || cameraID == "" {
+		return mcp.NewToolResultError("chime_id and camera_id are required"), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	chime, err := s.protectClient.UnpairChimeDoorbell(ctx, chimeID, cameraID)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to unpair doorbell", err), nil
+	}
+	return mcp.NewToolResultJSON(chime)
+}
+
+func (s *Server) setChimeRingSettings(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: set_chime_ring_settings")
+
+	chimeID := request.GetString("chime_id", "")
+	cameraID := request.GetString("camera_id", "")
+	if chimeID == "" || cameraID == "" {
+		return mcp.NewToolResultError("chime_id and camera_id are required"), nil
+	}
+
+	args := request.GetArguments()
+	update := unifi.RingUpdate{RingtoneID: request.GetString("ringtone_id", "")}
+	if _, ok := args["volume"]; ok {
+		volume := request.GetInt("volume", 0)
+		update.Volume = &volume
+	}
+	if _, ok := args["repeat_times"]; ok {
+		repeat := request.GetInt("repeat_times", 0)
+		update.RepeatTimes = &repeat
+	}
+	if err := update.Validate(); err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid ring settings", err), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	previous, err := s.protectClient.SetChimeRingSettings(ctx, chimeID, cameraID, update)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to update ring settings", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"chime_id":  chimeID,
+		"camera_id": cameraID,
+		"previous":  previous,
+	})
+}
+
+// quietHoursKey identifies the pending restore job for a chime
+func quietHoursKey(chimeID string) string {
+	return "chime_quiet_hours:" + chimeID
+}
+
+func (s *Server) startChimeQuietHours(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: start_chime_quiet_hours")
+
+	until := request.GetString("until", "")
+	if until == "" {
+		return mcp.NewToolResultError("until is required"), nil
+	}
+	restoreAt, forever, err := humantime.Deadline(until, time.Now())
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid until", err), nil
+	}
+	if forever {
+		return mcp.NewToolResultError("quiet hours need an end time, e.g. \"until 7am\" or \"for 8 hours\""), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	chimes, err := s.selectChimes(ctx, request.GetStringSlice("chime_ids", nil))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to get chimes", err), nil
+	}
+
+	type chimeResult struct {
+		ChimeID string `json:"chime_id"`
+		Name    string `json:"name"`
+		Status  string `json:"status"`
+		JobID   string `json:"restore_job_id,omitempty"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	results := make([]chimeResult, 0, len(chimes))
+	for _, chime := range chimes {
+		result := chimeResult{ChimeID: chime.ID, Name: chime.Name}
+		if len(chime.RingSettings) == 0 {
+			result.Status = "no_ring_settings"
+			results = append(results, result)
+			continue
+		}
+
+		// A chime already in quiet hours keeps its original settings so the
+		// extended restore does not bring back the muted state
+		key := quietHoursKey(chime.ID)
+		restore := map[string]interface{}{"ringSettings": chime.RingSettings}
+		if job, ok := s.scheduler.Pending(key); ok {
+			if settings, ok := job.Params["settings"].(map[string]interface{}); ok {
+				restore = settings
+			}
+		}
+
+		if _, err := s.protectClient.PatchChime(ctx, chime.ID, map[string]interface{}{"ringSettings": chime.Muted()}); err != nil {
+			result.Status = "failed"
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		job, err := s.scheduler.Add(scheduler.Job{
+			Name:   fmt.Sprintf("End quiet hours for %s", chime.Name),
+			Key:    key,
+			RunAt:  &restoreAt,
+			Action: "patch_chime",
+			Params: map[string]interface{}{"chime_id": chime.ID, "settings": restore},
+		})
+		if err != nil {
+			result.Status = "muted_without_restore"
+			result.Error = err.Error()
+		} else {
+			result.Status = "muted"
+			result.JobID = job.ID
+		}
+		results = append(results, result)
+	}
+
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"restore_at": restoreAt.Format(time.RFC3339),
+		"chimes":     results,
+	})
+}
+
+func (s *Server) endChimeQuietHours(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: end_chime_quiet_hours")
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	chimes, err := s.selectChimes(ctx, request.GetStringSlice("chime_ids", nil))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to get chimes", err), nil
+	}
+
+	restored := []string{}
+	var failures []string
+	for _, chime := range chimes {
+		key := quietHoursKey(chime.ID)
+		job, ok := s.scheduler.Pending(key)
+		if !ok {
+			continue
+		}
+		settings, _ := job.Params["settings"].(map[string]interface{})
+		if _, err := s.protectClient.PatchChime(ctx, chime.ID, settings); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", chime.ID, err))
+			continue
+		}
+		if _, err := s.scheduler.CancelKey(key); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", chime.ID, err))
+			continue
+		}
+		restored = append(restored, chime.ID)
+	}
+
+	result := map[string]interface{}{"restored": restored}
+	if len(failures) > 0 {
+		result["errors"] = failures
+	}
+	return mcp.NewToolResultJSON(result)
+}
+
+// selectChimes returns the chimes with the given IDs, or every chime when ids is empty
+func (s *Server) selectChimes(ctx context.Context, ids []string) ([]unifi.ProtectChime, error) {
+	if len(ids) == 0 {
+		return s.protectClient.GetChimes(ctx)
+	}
+	chimes := make([]unifi.ProtectChime, 0, len(ids))
+	for _, id := range ids {
+		chime, err := s.protectClient.GetChime(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("chime %s: %w", id, err)
+		}
+		chimes = append(chimes, *chime)
+	}
+	return chimes, nil
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/devices"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func (s *Server) listAllDevices(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: list_all_devices")
+
+	modelKey := request.GetString("model_key", "")
+	if modelKey != "" && !slices.Contains(unifi.ModelKeys, modelKey) {
+		return mcp.NewToolResultError(fmt.Sprintf("unknown model_key %q, expected one of %v", modelKey, unifi.ModelKeys)), nil
+	}
+	state := request.GetString("state", "")
+
+	if request.GetBool("refresh", false) {
+		if err := s.protectClient.Authenticate(ctx); err != nil {
+			return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+		}
+		if err := s.devices.Refresh(ctx); err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to refresh device inventory", err), nil
+		}
+	}
+
+	counts := map[string]int{}
+	list := []devices.Device{}
+	offline := []devices.Device{}
+	for _, d := range s.devices.List() {
+		if modelKey != "" && d.ModelKey != modelKey {
+			continue
+		}
+		if state != "" && d.State != state {
+			continue
+		}
+		counts[d.ModelKey]++
+		list = append(list, d)
+		if d.State == unifi.DeviceStateDisconnected {
+			offline = append(offline, d)
+		}
+	}
+
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"devices":  list,
+		"count":    len(list),
+		"by_type":  counts,
+		"offline":  offline,
+		"coverage": "Speakers, bridges, AI processors, AI ports and link stations have no list endpoint and appear once the devices subscription reports them",
+	})
+}
@@ -0,0 +1,18 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/diagnostics"
+)
+
+func (s *Server) runDiagnostics(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: run_diagnostics")
+
+	report, err := diagnostics.New(s.protectClient).Run(ctx, request.GetStringSlice("checks", nil)...)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultJSON(report)
+}
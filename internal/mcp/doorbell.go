@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/humantime"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func (s *Server) setDoorbellMessage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: set_doorbell_message")
+
+	cameraID := request.GetString("camera_id", "")
+	if cameraID == "" {
+		return mcp.NewToolResultError("camera_id is required"), nil
+	}
+	messageType := request.GetString("type", "")
+	text := request.GetString("text", "")
+	if err := unifi.ValidateLCDMessage(messageType, text); err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid doorbell message", err), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	if messageType == unifi.LCDMessageImage {
+		if errResult := s.checkAssetFile(ctx, text); errResult != nil {
+			return errResult, nil
+		}
+	}
+
+	result, errResult := s.showDoorbellMessage(ctx, cameraID, messageType, text, request.GetString("duration", ""))
+	if errResult != nil {
+		return errResult, nil
+	}
+	return mcp.NewToolResultJSON(result)
+}
+
+// showDoorbellMessage sets a validated doorbell message until duration, or
+// the NVR default timeout when duration is empty. On failure it returns the
+// tool error result instead.
+func (s *Server) showDoorbellMessage(ctx context.Context, cameraID, messageType, text, duration string) (map[string]interface{}, *mcp.CallToolResult) {
+	now := time.Now()
+	var resetAt *time.Time
+	source := "duration"
+	if duration != "" {
+		deadline, forever, err := humantime.Deadline(duration, now)
+		if err != nil {
+			return nil, mcp.NewToolResultErrorFromErr("Invalid duration", err)
+		}
+		if forever {
+			source = "forever"
+		} else {
+			resetAt = &deadline
+		}
+	} else {
+		timeout, err := s.protectClient.GetDoorbellDefaultResetTimeout(ctx)
+		if err != nil {
+			return nil, mcp.NewToolResultErrorFromErr("Failed to get NVR default message timeout", err)
+		}
+		if timeout > 0 {
+			deadline := now.Add(timeout)
+			resetAt = &deadline
+			source = "nvr_default"
+		} else {
+			source = "forever"
+		}
+	}
+
+	camera, err := s.protectClient.SetDoorbellMessage(ctx, cameraID, messageType, text, resetAt)
+	if err != nil {
+		return nil, mcp.NewToolResultErrorFromErr("Failed to set doorbell message", err)
+	}
+
+	result := map[string]interface{}{
+		"camera_id":    cameraID,
+		"type":         messageType,
+		"text":         text,
+		"reset_source": source,
+		"camera":       camera,
+	}
+	if resetAt != nil {
+		result["reset_at"] = resetAt.Format(time.RFC3339)
+	}
+	return result, nil
+}
+
+func (s *Server) clearDoorbellMessage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: clear_doorbell_message")
+
+	cameraID := request.GetString("camera_id", "")
+	if cameraID == "" {
+		return mcp.NewToolResultError("camera_id is required"), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	camera, err := s.protectClient.GetCameraDetailed(ctx, cameraID)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to get camera details", err), nil
+	}
+	current, _ := camera["lcdMessage"].(map[string]interface{})
+	messageType, _ := current["type"].(string)
+	if messageType == "" {
+		return mcp.NewToolResultJSON(map[string]interface{}{
+			"camera_id": cameraID,
+			"cleared":   false,
+			"message":   "No doorbell message is set",
+		})
+	}
+
+	// Expiring the current message immediately returns the doorbell to its default display
+	text, _ := current["text"].(string)
+	now := time.Now()
+	if _, err := s.protectClient.SetDoorbellMessage(ctx, cameraID, messageType, text, &now); err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to clear doorbell message", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"camera_id":        cameraID,
+		"cleared":          true,
+		"previous_message": current,
+	})
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/eventlog"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/humantime"
+)
+
+func (s *Server) summarizeEvents(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: summarize_events")
+
+	format := request.GetString("format", "json")
+	if format != "json" && format != eventlog.FormatMarkdown && format != eventlog.FormatHTML {
+		return mcp.NewToolResultError(fmt.Sprintf("unknown format %q, expected json, %s or %s", format, eventlog.FormatMarkdown, eventlog.FormatHTML)), nil
+	}
+	from, to, err := historyWindow(request)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid window", err), nil
+	}
+	opts := eventlog.Options{
+		BaselineDays: request.GetInt("baseline_days", eventlog.DefaultBaselineDays),
+		MaxIncidents: request.GetInt("max_incidents", 10),
+		Types:        request.GetStringSlice("types", nil),
+	}
+	if opts.BaselineDays < 1 || opts.BaselineDays > 30 {
+		return mcp.NewToolResultError("baseline_days must be between 1 and 30"), nil
+	}
+	if opts.MaxIncidents < 0 {
+		return mcp.NewToolResultError("max_incidents must not be negative"), nil
+	}
+	if v := request.GetString("gap", ""); v != "" {
+		if opts.Gap, err = humantime.Duration(v); err != nil {
+			return mcp.NewToolResultErrorFromErr("Invalid gap", err), nil
+		}
+	}
+	if v := request.GetString("timezone", ""); v != "" {
+		if opts.Location, err = time.LoadLocation(v); err != nil {
+			return mcp.NewToolResultErrorFromErr("Invalid timezone", err), nil
+		}
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+	names, err := eventlog.DeviceNames(ctx, s.protectClient)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to load device names for the event summary")
+	}
+	opts.Names = names
+	for _, device := range request.GetStringSlice("devices", nil) {
+		opts.Devices = append(opts.Devices, deviceID(device, names))
+	}
+
+	summary := s.events.Summarize(from, to, opts)
+	switch format {
+	case eventlog.FormatMarkdown:
+		return mcp.NewToolResultText(summary.Markdown()), nil
+	case eventlog.FormatHTML:
+		html, err := summary.HTML()
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to render summary", err), nil
+		}
+		return mcp.NewToolResultText(html), nil
+	}
+
+	result := map[string]interface{}{"summary": summary}
+	if since, ok := s.events.Since(); ok {
+		result["recorded_since"] = since.Format(time.RFC3339)
+	}
+	return mcp.NewToolResultJSON(result)
+}
+
+// deviceID resolves a device name to its ID, case-insensitively; anything
+// else is taken as an ID
+func deviceID(device string, names map[string]string) string {
+	for id, name := range names {
+		if strings.EqualFold(name, device) {
+			return id
+		}
+	}
+	return device
+}
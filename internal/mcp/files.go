@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func (s *Server) listAssetFiles(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: list_asset_files")
+
+	fileType := request.GetString("file_type", unifi.AssetFileTypeAnimations)
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	files, err := s.protectClient.ListAssetFiles(ctx, fileType)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to list asset files", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"file_type": fileType,
+		"files":     files,
+		"count":     len(files),
+	})
+}
+
+func (s *Server) uploadAssetFile(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: upload_asset_file")
+
+	fileType := request.GetString("file_type", unifi.AssetFileTypeAnimations)
+	localPath := request.GetString("path", "")
+	content := request.GetString("content_base64", "")
+	filename := request.GetString("filename", "")
+	if (localPath == "") == (content == "") {
+		return mcp.NewToolResultError("exactly one of path or content_base64 is required"), nil
+	}
+
+	var data []byte
+	if localPath != "" {
+		info, err := os.Stat(localPath)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to read file", err), nil
+		}
+		if info.Size() > unifi.MaxAssetFileSize {
+			return mcp.NewToolResultError(fmt.Sprintf("file is %d bytes, maximum is %d", info.Size(), unifi.MaxAssetFileSize)), nil
+		}
+		if data, err = os.ReadFile(localPath); err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to read file", err), nil
+		}
+		if filename == "" {
+			filename = filepath.Base(localPath)
+		}
+	} else {
+		var err error
+		if data, err = base64.StdEncoding.DecodeString(content); err != nil {
+			return mcp.NewToolResultErrorFromErr("Invalid content_base64", err), nil
+		}
+	}
+	mimeType, err := unifi.ValidateAssetFile(data)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid asset file", err), nil
+	}
+
+	cameraID := request.GetString("camera_id", "")
+	if cameraID != "" && (!strings.HasPrefix(mimeType, "image/")) {
+		return mcp.NewToolResultError(fmt.Sprintf("only images can be shown on a doorbell, got %s", mimeType)), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	file, err := s.protectClient.UploadAssetFile(ctx, fileType, filename, data)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to upload asset file", err), nil
+	}
+
+	result := map[string]interface{}{
+		"file":      file,
+		"mime_type": mimeType,
+		"size":      len(data),
+	}
+	if cameraID != "" {
+		doorbell, errResult := s.showDoorbellMessage(ctx, cameraID, unifi.LCDMessageImage, file.Name, request.GetString("duration", ""))
+		if errResult != nil {
+			return errResult, nil
+		}
+		result["doorbell"] = doorbell
+	}
+	return mcp.NewToolResultJSON(result)
+}
+
+// checkAssetFile returns a tool error if no uploaded animation has this name
+func (s *Server) checkAssetFile(ctx context.Context, name string) *mcp.CallToolResult {
+	files, err := s.protectClient.ListAssetFiles(ctx, unifi.AssetFileTypeAnimations)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to list asset files", err)
+	}
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if f.Name == name {
+			return nil
+		}
+		names = append(names, f.Name)
+	}
+	return mcp.NewToolResultError(fmt.Sprintf("asset file %q not found, upload it with upload_asset_file first; available: %v", name, names))
+}
@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func (s *Server) setCameraVideoMode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: set_camera_video_mode")
+
+	cameraID := request.GetString("camera_id", "")
+	mode := request.GetString("video_mode", "")
+	if cameraID == "" || mode == "" {
+		return mcp.NewToolResultError("camera_id and video_mode are required"), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	previous, err := s.protectClient.SetCameraVideoMode(ctx, cameraID, mode)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to set video mode", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"camera_id":  cameraID,
+		"video_mode": mode,
+		"previous":   previous,
+		"changed":    previous != mode,
+	})
+}
+
+func (s *Server) setCameraHDR(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: set_camera_hdr")
+
+	cameraID := request.GetString("camera_id", "")
+	hdrType := request.GetString("hdr_type", "")
+	if cameraID == "" || hdrType == "" {
+		return mcp.NewToolResultError("camera_id and hdr_type are required"), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	previous, err := s.protectClient.SetCameraHDRType(ctx, cameraID, hdrType)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to set HDR mode", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"camera_id": cameraID,
+		"hdr_type":  hdrType,
+		"previous":  previous,
+		"changed":   previous != hdrType,
+	})
+}
+
+func (s *Server) setCameraOSD(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: set_camera_osd")
+
+	cameraID := request.GetString("camera_id", "")
+	if cameraID == "" {
+		return mcp.NewToolResultError("camera_id is required"), nil
+	}
+	update := osdUpdateFromArgs(request.GetArguments())
+	if update == nil {
+		return mcp.NewToolResultError("at least one of show_name, show_date, show_logo, show_debug or overlay_location is required"), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	previous, err := s.protectClient.SetCameraOSD(ctx, cameraID, *update)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to update OSD settings", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"camera_id":    cameraID,
+		"osd_settings": update,
+		"previous":     previous,
+	})
+}
+
+func (s *Server) applyCameraImagingProfile(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: apply_camera_imaging_profile")
+
+	args := request.GetArguments()
+	profile := unifi.ImagingProfile{
+		VideoMode: request.GetString("video_mode", ""),
+		HdrType:   request.GetString("hdr_type", ""),
+	}
+	if osd, ok := args["osd"].(map[string]interface{}); ok {
+		profile.OSD = osdUpdateFromArgs(osd)
+	}
+	if profile.VideoMode == "" && profile.HdrType == "" && profile.OSD == nil {
+		return mcp.NewToolResultError("at least one of video_mode, hdr_type or osd is required"), nil
+	}
+	cameraIDs := request.GetStringSlice("camera_ids", nil)
+	namePattern := request.GetString("name_pattern", "")
+	if len(cameraIDs) == 0 && namePattern == "" && !request.GetBool("all_cameras", false) {
+		return mcp.NewToolResultError("camera_ids, name_pattern or all_cameras is required"), nil
+	}
+	skipUnsupported := request.GetBool("skip_unsupported", false)
+	dryRun := request.GetBool("dry_run", false)
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	cameras, err := s.selectCameras(ctx, cameraIDs, namePattern)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to select cameras", err), nil
+	}
+
+	type cameraResult struct {
+		CameraID string                 `json:"camera_id"`
+		Name     string                 `json:"name"`
+		Status   string                 `json:"status"`
+		Changes  map[string]interface{} `json:"changes,omitempty"`
+		Previous map[string]interface{} `json:"previous,omitempty"`
+		Error    string                 `json:"error,omitempty"`
+	}
+
+	results := make([]cameraResult, 0, len(cameras))
+	var rejected, failed int
+	for i := range cameras {
+		camera := &cameras[i]
+		result := cameraResult{CameraID: camera.ID, Name: camera.Name}
+
+		if err := profile.Validate(camera); err != nil {
+			result.Error = err.Error()
+			if skipUnsupported {
+				result.Status = "skipped"
+			} else {
+				result.Status = "rejected"
+				rejected++
+			}
+			results = append(results, result)
+			continue
+		}
+
+		result.Changes, result.Previous = profile.Diff(camera)
+		switch {
+		case len(result.Changes) == 0:
+			result.Status = "unchanged"
+		case dryRun:
+			result.Status = "would_apply"
+		default:
+			if _, err := s.protectClient.ApplyImagingProfile(ctx, camera, profile); err != nil {
+				result.Status = "failed"
+				result.Error = err.Error()
+				failed++
+			} else {
+				result.Status = "applied"
+			}
+		}
+		results = append(results, result)
+	}
+
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"profile":  profile,
+		"dry_run":  dryRun,
+		"success":  rejected == 0 && failed == 0,
+		"rejected": rejected,
+		"failed":   failed,
+		"cameras":  results,
+	})
+}
+
+// osdUpdateFromArgs builds an OSD update from tool arguments, returning nil
+// when no OSD field is present
+func osdUpdateFromArgs(args map[string]interface{}) *unifi.OSDUpdate {
+	update := &unifi.OSDUpdate{
+		IsNameEnabled:  optionalBool(args, "show_name"),
+		IsDateEnabled:  optionalBool(args, "show_date"),
+		IsLogoEnabled:  optionalBool(args, "show_logo"),
+		IsDebugEnabled: optionalBool(args, "show_debug"),
+	}
+	update.OverlayLocation, _ = args["overlay_location"].(string)
+	if update.IsNameEnabled == nil && update.IsDateEnabled == nil && update.IsLogoEnabled == nil &&
+		update.IsDebugEnabled == nil && update.OverlayLocation == "" {
+		return nil
+	}
+	return update
+}
+
+func optionalBool(args map[string]interface{}, key string) *bool {
+	if v, ok := args[key].(bool); ok {
+		return &v
+	}
+	return nil
+}
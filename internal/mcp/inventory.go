@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/devices"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/inventory"
+)
+
+var inventoryFormats = []string{inventory.FormatJSON, inventory.FormatCSV, inventory.FormatMarkdown}
+
+func (s *Server) getProtectInventory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: get_protect_inventory")
+
+	format := request.GetString("format", inventory.FormatJSON)
+	if !slices.Contains(inventoryFormats, format) {
+		return mcp.NewToolResultError(fmt.Sprintf("unknown format %q, expected one of %v", format, inventoryFormats)), nil
+	}
+	filter := inventory.Filter{
+		State: request.GetString("state", ""),
+		Type:  request.GetString("type", ""),
+		Model: request.GetString("model", ""),
+		Name:  request.GetString("name_pattern", ""),
+	}
+	if err := filter.Validate(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	items, errs := inventory.Collect(ctx, s.protectClient)
+	if s.devices != nil {
+		items = mergeSubscriptionDevices(items, s.devices.List())
+	}
+	items = filter.Apply(items)
+
+	switch format {
+	case inventory.FormatCSV:
+		table, err := inventory.CSV(items)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to render inventory", err), nil
+		}
+		return inventoryTextResult(table, errs), nil
+	case inventory.FormatMarkdown:
+		return inventoryTextResult(inventory.Markdown(items), errs), nil
+	}
+
+	result := map[string]interface{}{
+		"devices": items,
+		"count":   len(items),
+	}
+	if len(errs) > 0 {
+		result["errors"] = errs
+	}
+	return mcp.NewToolResultJSON(result)
+}
+
+// mergeSubscriptionDevices adds devices only known from the devices
+// subscription, such as speakers and bridges, to the API inventory
+func mergeSubscriptionDevices(items []inventory.Item, known []devices.Device) []inventory.Item {
+	seen := make(map[string]bool, len(items))
+	for _, it := range items {
+		seen[it.ID] = true
+	}
+	for _, d := range known {
+		if seen[d.ID] {
+			continue
+		}
+		items = append(items, inventory.Item{ID: d.ID, Type: d.ModelKey, Name: d.Name, MAC: d.MAC, State: d.State})
+	}
+	inventory.Sort(items)
+	return items
+}
+
+// inventoryTextResult returns a rendered table, noting any device classes
+// that could not be fetched in a separate content block
+func inventoryTextResult(table string, errs map[string]string) *mcp.CallToolResult {
+	result := mcp.NewToolResultText(table)
+	if len(errs) > 0 {
+		keys := make([]string, 0, len(errs))
+		for key := range errs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		lines := make([]string, 0, len(keys))
+		for _, key := range keys {
+			lines = append(lines, fmt.Sprintf("%s: %s", key, errs[key]))
+		}
+		result.Content = append(result.Content, mcp.NewTextContent("Incomplete inventory, failed to fetch:\n"+strings.Join(lines, "\n")))
+	}
+	return result
+}
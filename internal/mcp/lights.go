@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/scheduler"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func (s *Server) setLightMode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: set_light_mode")
+
+	lightID := request.GetString("light_id", "")
+	mode := request.GetString("mode", "")
+	if lightID == "" || mode == "" {
+		return mcp.NewToolResultError("light_id and mode are required"), nil
+	}
+	enableAt := request.GetString("enable_at", "")
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	previous, err := s.protectClient.SetLightMode(ctx, lightID, mode, enableAt)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to set light mode", err), nil
+	}
+	if enableAt == "" {
+		enableAt = previous.EnableAt
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"light_id":  lightID,
+		"mode":      mode,
+		"enable_at": enableAt,
+		"previous":  previous,
+	})
+}
+
+func (s *Server) setLightBrightnessAndDuration(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: set_light_brightness_and_duration")
+
+	lightID := request.GetString("light_id", "")
+	if lightID == "" {
+		return mcp.NewToolResultError("light_id is required"), nil
+	}
+
+	args := request.GetArguments()
+	var update unifi.LightDeviceUpdate
+	if _, ok := args["brightness"]; ok {
+		level := request.GetInt("brightness", 0)
+		update.LEDLevel = &level
+	}
+	if _, ok := args["duration_seconds"]; ok {
+		d := time.Duration(request.GetInt("duration_seconds", 0)) * time.Second
+		update.PIRDuration = &d
+	}
+	if _, ok := args["motion_sensitivity"]; ok {
+		sensitivity := request.GetInt("motion_sensitivity", 0)
+		update.PIRSensitivity = &sensitivity
+	}
+	update.IsIndicatorEnabled = optionalBool(args, "status_led")
+	if err := update.Validate(); err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid light settings", err), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	previous, err := s.protectClient.SetLightDeviceSettings(ctx, lightID, update)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to update light settings", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"light_id": lightID,
+		"previous": map[string]interface{}{
+			"brightness":         previous.LEDLevel,
+			"duration_seconds":   previous.PIRDuration / 1000,
+			"motion_sensitivity": previous.PIRSensitivity,
+			"status_led":         previous.IsIndicatorEnabled,
+		},
+	})
+}
+
+func (s *Server) forceLightOn(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: force_light_on")
+
+	lightID := request.GetString("light_id", "")
+	if lightID == "" {
+		return mcp.NewToolResultError("light_id is required"), nil
+	}
+	enabled := request.GetBool("enabled", true)
+	minutes := request.GetInt("minutes", 0)
+	if minutes < 0 {
+		return mcp.NewToolResultError("minutes must not be negative"), nil
+	}
+	if enabled && minutes > 0 && s.scheduler == nil {
+		return mcp.NewToolResultError("auto-revert requires the scheduler"), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	key := "force_light_on:" + lightID
+
+	// When re-forcing a light that already has a pending revert, keep the
+	// original state so the new revert restores what was there before
+	var pending *scheduler.Job
+	if s.scheduler != nil {
+		if job, ok := s.scheduler.Pending(key); ok {
+			pending = &job
+		}
+	}
+
+	previous, err := s.protectClient.SetLightForced(ctx, lightID, enabled)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to set light override", err), nil
+	}
+
+	result := map[string]interface{}{
+		"light_id":              lightID,
+		"force_enabled":         enabled,
+		"previously_forced_on":  previous,
+		"auto_revert_scheduled": false,
+	}
+
+	if !enabled || minutes == 0 {
+		if s.scheduler != nil {
+			if _, err := s.scheduler.CancelKey(key); err != nil {
+				return mcp.NewToolResultErrorFromErr("Light updated but failed to cancel the pending revert", err), nil
+			}
+		}
+		if pending != nil {
+			result["cancelled_revert"] = pending.ID
+		}
+		return mcp.NewToolResultJSON(result)
+	}
+
+	revertTo := previous
+	if pending != nil {
+		if settings, ok := pending.Params["settings"].(map[string]interface{}); ok {
+			if v, ok := settings["isLightForceEnabled"].(bool); ok {
+				revertTo = v
+			}
+		}
+	}
+	revertAt := time.Now().Add(time.Duration(minutes) * time.Minute)
+	job, err := s.scheduler.Add(scheduler.Job{
+		Name:   fmt.Sprintf("Revert force_light_on for %s", lightID),
+		Key:    key,
+		RunAt:  &revertAt,
+		Action: "patch_light",
+		Params: map[string]interface{}{
+			"light_id": lightID,
+			"settings": map[string]interface{}{"isLightForceEnabled": revertTo},
+		},
+	})
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Light forced on but failed to schedule the revert", err), nil
+	}
+
+	result["auto_revert_scheduled"] = true
+	result["revert_at"] = revertAt.Format(time.RFC3339)
+	result["revert_job_id"] = job.ID
+	return mcp.NewToolResultJSON(result)
+}
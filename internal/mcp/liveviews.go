@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// liveviewSlotArg is a slot as given to build_liveview
+type liveviewSlotArg struct {
+	Cameras       []string `json:"cameras"`
+	CycleMode     string   `json:"cycle_mode"`
+	CycleInterval int      `json:"cycle_interval"`
+}
+
+func (s *Server) buildLiveview(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: build_liveview")
+
+	name := request.GetString("name", "")
+	if name == "" {
+		return mcp.NewToolResultError("name is required"), nil
+	}
+
+	args := request.GetArguments()
+	var slots []liveviewSlotArg
+	if raw, ok := args["slots"]; ok {
+		if err := decodeArgument(raw, &slots); err != nil {
+			return mcp.NewToolResultErrorFromErr("Invalid slots", err), nil
+		}
+	}
+	for _, camera := range request.GetStringSlice("cameras", nil) {
+		slots = append(slots, liveviewSlotArg{Cameras: []string{camera}})
+	}
+	if len(slots) == 0 {
+		return mcp.NewToolResultError("cameras or slots is required"), nil
+	}
+	layout := request.GetInt("layout", len(slots))
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	resolver, err := s.newCameraResolver(ctx)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to get cameras", err), nil
+	}
+	lv := unifi.ProtectLiveview{
+		Name:      name,
+		Layout:    layout,
+		IsGlobal:  request.GetBool("is_global", false),
+		IsDefault: request.GetBool("is_default", false),
+	}
+	for i, slot := range slots {
+		ids := make([]string, 0, len(slot.Cameras))
+		for _, ref := range slot.Cameras {
+			id, err := resolver.resolve(ref)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr(fmt.Sprintf("Slot %d", i+1), err), nil
+			}
+			ids = append(ids, id)
+		}
+		lv.Slots = append(lv.Slots, unifi.ProtectLiveviewSlot{Cameras: ids, CycleMode: slot.CycleMode, CycleInterval: slot.CycleInterval})
+	}
+	if err := lv.Validate(); err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid liveview", err), nil
+	}
+
+	// An existing liveview with the same name is updated in place
+	existing, err := s.protectClient.ListLiveviews(ctx)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to get liveviews", err), nil
+	}
+	for _, current := range existing {
+		if strings.EqualFold(current.Name, name) {
+			lv.ID = current.ID
+			break
+		}
+	}
+
+	saved, err := s.protectClient.SaveLiveview(ctx, lv)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to save liveview", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"created":  lv.ID == "",
+		"liveview": saved,
+	})
+}
+
+func (s *Server) assignLiveviewToViewer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: assign_liveview_to_viewer")
+
+	viewerRef := request.GetString("viewer", "")
+	liveviewRef := request.GetString("liveview", "")
+	if viewerRef == "" || liveviewRef == "" {
+		return mcp.NewToolResultError("viewer and liveview are required"), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	viewers, err := s.protectClient.GetViewers(ctx)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to get viewers", err), nil
+	}
+	viewerID := ""
+	for _, viewer := range viewers {
+		id, _ := viewer["id"].(string)
+		name, _ := viewer["name"].(string)
+		if id == viewerRef || strings.EqualFold(name, viewerRef) {
+			viewerID = id
+			break
+		}
+	}
+	if viewerID == "" {
+		return mcp.NewToolResultError(fmt.Sprintf("viewer %q not found", viewerRef)), nil
+	}
+
+	liveviews, err := s.protectClient.ListLiveviews(ctx)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to get liveviews", err), nil
+	}
+	liveviewID := ""
+	for _, lv := range liveviews {
+		if lv.ID == liveviewRef || strings.EqualFold(lv.Name, liveviewRef) {
+			liveviewID = lv.ID
+			break
+		}
+	}
+	if liveviewID == "" {
+		return mcp.NewToolResultError(fmt.Sprintf("liveview %q not found", liveviewRef)), nil
+	}
+
+	viewer, err := s.protectClient.AssignLiveviewToViewer(ctx, viewerID, liveviewID)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to assign liveview", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"viewer_id":   viewerID,
+		"liveview_id": liveviewID,
+		"viewer":      viewer,
+	})
+}
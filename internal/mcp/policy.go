@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"slices"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/openapi"
+)
+
+// Policy limits the tools the server offers. Tools the policy excludes are
+// not registered, so clients never see them.
+type Policy struct {
+	// ReadOnly offers only tools that never change console or server state
+	ReadOnly bool
+	// Allow, when not empty, names the only tools offered. It also limits
+	// protect_api_request to the operations it names and those covered by
+	// the tools it names, or to GET operations when it names no operations.
+	Allow []string
+}
+
+// readOnlyTools lists the hand-written tools that only read state. Tools
+// missing from this list are treated as changing state, so new tools are
+// hidden in read-only mode until they are added here. Generated operation
+// tools are read-only when their operation is a GET.
+var readOnlyTools = map[string]bool{
+	"get_protect_cameras":         true,
+	"get_protect_sensors":         true,
+	"get_protect_lights":          true,
+	"get_protect_chimes":          true,
+	"get_protect_liveviews":       true,
+	"list_all_devices":            true,
+	"get_protect_inventory":       true,
+	"get_camera_detailed":         true,
+	"get_sensor_detailed":         true,
+	"get_light_detailed":          true,
+	"get_chime_detailed":          true,
+	"get_liveview_detailed":       true,
+	"get_protect_info":            true,
+	"get_protect_nvr":             true,
+	"run_diagnostics":             true,
+	"get_protect_viewers":         true,
+	"get_protect_viewer_detailed": true,
+	"list_asset_files":            true,
+	"get_camera_smart_detection":  true,
+	"get_sensor_readings":         true,
+	"get_sensor_history_summary":  true,
+	"get_sensor_history_series":   true,
+	"summarize_events":            true,
+	"get_protect_events":          true,
+	"get_webhook_deliveries":      true,
+	"list_rules":                  true,
+	"dry_run_rule":                true,
+	"list_scheduled_actions":      true,
+	"list_scenes":                 true,
+	// Write operations are refused per call in read-only mode
+	"protect_api_request": true,
+}
+
+// WithPolicy limits the tools the server offers
+func WithPolicy(policy Policy) Option {
+	return func(s *Server) {
+		s.policy = policy
+	}
+}
+
+// allowsTool reports whether a tool is offered
+func (p Policy) allowsTool(name string) bool {
+	if p.ReadOnly && !readOnlyTools[name] && !isReadOnlySpecTool(name) {
+		return false
+	}
+	return len(p.Allow) == 0 || slices.Contains(p.Allow, name)
+}
+
+// allowsOperation reports whether protect_api_request may call an
+// operation. Operations other than GET change state and are refused in
+// read-only mode. With an allowlist, an operation is allowed when it or the
+// tool covering it is listed; when the allowlist names no operations, GET
+// operations are allowed as well.
+func (p Policy) allowsOperation(ep *openapi.Endpoint, spec *openapi.Spec) bool {
+	if p.ReadOnly && ep.Method != "GET" {
+		return false
+	}
+	if len(p.Allow) == 0 || slices.Contains(p.Allow, ep.OperationID) || slices.Contains(p.Allow, operationTool(ep.OperationID)) {
+		return true
+	}
+	for _, name := range p.Allow {
+		if _, ok := spec.Operation(name); ok {
+			return false
+		}
+	}
+	return ep.Method == "GET"
+}
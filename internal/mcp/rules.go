@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/rules"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+	"gopkg.in/yaml.v3"
+)
+
+func (s *Server) listRules(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: list_rules")
+
+	list := s.rules.Rules()
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"rules": list,
+		"count": len(list),
+	})
+}
+
+func (s *Server) createRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: create_rule")
+
+	var rule rules.Rule
+	args := request.GetArguments()
+	if ruleYAML := request.GetString("rule_yaml", ""); ruleYAML != "" {
+		if err := yaml.Unmarshal([]byte(ruleYAML), &rule); err != nil {
+			return mcp.NewToolResultErrorFromErr("Invalid rule_yaml", err), nil
+		}
+	} else if obj, ok := args["rule"].(map[string]interface{}); ok {
+		if err := decodeArgument(obj, &rule); err != nil {
+			return mcp.NewToolResultErrorFromErr("Invalid rule", err), nil
+		}
+	} else {
+		return mcp.NewToolResultError("One of rule or rule_yaml is required"), nil
+	}
+
+	if err := s.rules.Create(rule); err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to create rule", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"rule":    rule,
+		"created": true,
+	})
+}
+
+func (s *Server) setRuleEnabled(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: set_rule_enabled")
+
+	ruleID := request.GetString("rule_id", "")
+	if ruleID == "" {
+		return mcp.NewToolResultError("rule_id is required"), nil
+	}
+	if _, ok := request.GetArguments()["enabled"]; !ok {
+		return mcp.NewToolResultError("enabled is required"), nil
+	}
+
+	rule, err := s.rules.SetEnabled(ruleID, request.GetBool("enabled", false))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to update rule", err), nil
+	}
+	return mcp.NewToolResultJSON(rule)
+}
+
+func (s *Server) dryRunRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: dry_run_rule")
+
+	ruleID := request.GetString("rule_id", "")
+	if ruleID == "" {
+		return mcp.NewToolResultError("rule_id is required"), nil
+	}
+
+	var event *unifi.ProtectEventMessage
+	if obj, ok := request.GetArguments()["event"].(map[string]interface{}); ok {
+		var item unifi.ProtectEventItem
+		if err := decodeArgument(obj, &item); err != nil {
+			return mcp.NewToolResultErrorFromErr("Invalid event", err), nil
+		}
+		event = &unifi.ProtectEventMessage{Type: "add", Item: item}
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	result, err := s.rules.DryRun(ctx, ruleID, event)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to evaluate rule", err), nil
+	}
+	return mcp.NewToolResultJSON(result)
+}
+
+// decodeArgument converts a tool object or array argument into a typed value via JSON
+func decodeArgument(arg interface{}, v interface{}) error {
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return fmt.Errorf("failed to encode argument: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode argument: %w", err)
+	}
+	return nil
+}
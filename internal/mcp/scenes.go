@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/scenes"
+)
+
+func (s *Server) captureScene(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: capture_scene")
+
+	name := request.GetString("name", "")
+	if name == "" {
+		return mcp.NewToolResultError("name is required"), nil
+	}
+	sel := scenes.Selection{
+		Cameras: request.GetStringSlice("camera_ids", nil),
+		Lights:  request.GetStringSlice("light_ids", nil),
+		Chimes:  request.GetStringSlice("chime_ids", nil),
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	scene, err := s.scenes.Capture(ctx, name, request.GetString("description", ""), sel)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to capture scene", err), nil
+	}
+	return mcp.NewToolResultJSON(scene)
+}
+
+func (s *Server) listScenes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: list_scenes")
+
+	list := s.scenes.List()
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"scenes": list,
+		"count":  len(list),
+	})
+}
+
+func (s *Server) applyScene(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: apply_scene")
+
+	name := request.GetString("name", "")
+	if name == "" {
+		return mcp.NewToolResultError("name is required"), nil
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	result, err := s.scenes.Apply(ctx, name, request.GetBool("dry_run", false))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to apply scene", err), nil
+	}
+	return mcp.NewToolResultJSON(result)
+}
+
+func (s *Server) deleteScene(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: delete_scene")
+
+	name := request.GetString("name", "")
+	if name == "" {
+		return mcp.NewToolResultError("name is required"), nil
+	}
+
+	if err := s.scenes.Delete(name); err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to delete scene", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"name":    name,
+		"deleted": true,
+	})
+}
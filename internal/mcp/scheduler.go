@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/scheduler"
+)
+
+func (s *Server) scheduleAction(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: schedule_action")
+
+	action := request.GetString("action", "")
+	if action == "" {
+		return mcp.NewToolResultError("action is required"), nil
+	}
+
+	job := scheduler.Job{
+		Name:     request.GetString("name", ""),
+		Schedule: request.GetString("schedule", ""),
+		Action:   action,
+	}
+	if runAt := request.GetString("run_at", ""); runAt != "" {
+		t, err := time.Parse(time.RFC3339, runAt)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("run_at must be an RFC 3339 timestamp", err), nil
+		}
+		job.RunAt = &t
+	}
+	if params, ok := request.GetArguments()["params"].(map[string]interface{}); ok {
+		job.Params = params
+	}
+
+	created, err := s.scheduler.Add(job)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to schedule action", err), nil
+	}
+	return mcp.NewToolResultJSON(created)
+}
+
+func (s *Server) listScheduledActions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: list_scheduled_actions")
+
+	jobs := s.scheduler.List()
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"jobs":              jobs,
+		"count":             len(jobs),
+		"available_actions": scheduler.ActionNames(),
+	})
+}
+
+func (s *Server) cancelScheduledAction(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: cancel_scheduled_action")
+
+	jobID := request.GetString("job_id", "")
+	if jobID == "" {
+		return mcp.NewToolResultError("job_id is required"), nil
+	}
+
+	job, err := s.scheduler.Cancel(jobID)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to cancel scheduled action", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"job":       job,
+		"cancelled": true,
+	})
+}
@@ -0,0 +1,146 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func (s *Server) getSensorReadings(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: get_sensor_readings")
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	var sensors []unifi.ProtectSensor
+	if sensorID := request.GetString("sensor_id", ""); sensorID != "" {
+		sensor, err := s.protectClient.GetSensor(ctx, sensorID)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to get sensor", err), nil
+		}
+		sensors = append(sensors, *sensor)
+	} else {
+		all, err := s.protectClient.GetSensors(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to get sensors", err), nil
+		}
+		sensors = all
+	}
+
+	results := make([]map[string]interface{}, 0, len(sensors))
+	for i := range sensors {
+		sensor := &sensors[i]
+		result := map[string]interface{}{
+			"sensor_id":  sensor.ID,
+			"name":       sensor.Name,
+			"state":      sensor.State,
+			"mount_type": sensor.MountType,
+			"readings":   sensor.Readings(),
+			"contact": map[string]interface{}{
+				"is_opened":  sensor.IsOpened,
+				"changed_at": formatMillis(sensor.OpenStatusChangedAt),
+			},
+			"motion": map[string]interface{}{
+				"detected":    sensor.IsMotionDetected,
+				"detected_at": formatMillis(sensor.MotionDetectedAt),
+				"settings":    sensor.MotionSettings,
+			},
+			"alarm": map[string]interface{}{
+				"triggered_at": formatMillis(sensor.AlarmTriggeredAt),
+				"settings":     sensor.AlarmSettings,
+			},
+			"leak": map[string]interface{}{
+				"detected_at":          formatMillis(sensor.LeakDetectedAt),
+				"external_detected_at": formatMillis(sensor.ExternalLeakDetectedAt),
+				"settings":             sensor.LeakSettings,
+			},
+			"tampering_detected_at": formatMillis(sensor.TamperingDetectedAt),
+		}
+		if sensor.BatteryStatus != nil {
+			result["battery"] = map[string]interface{}{
+				"percentage": sensor.BatteryStatus.Percentage,
+				"is_low":     sensor.BatteryStatus.IsLow,
+			}
+		}
+		results = append(results, result)
+	}
+
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"sensors": results,
+		"count":   len(results),
+	})
+}
+
+func (s *Server) configureSensorThresholds(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: configure_sensor_thresholds")
+
+	sensorID := request.GetString("sensor_id", "")
+	if sensorID == "" {
+		return mcp.NewToolResultError("sensor_id is required"), nil
+	}
+
+	args := request.GetArguments()
+	if _, ok := args["leak"]; ok {
+		return mcp.NewToolResultError("leak detection settings are read-only in the Protect API"), nil
+	}
+
+	update := unifi.SensorConfigUpdate{Thresholds: map[string]unifi.ThresholdUpdate{}}
+	for _, metric := range unifi.SensorMetrics {
+		arg, ok := args[metric.Name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		update.Thresholds[metric.Name] = unifi.ThresholdUpdate{
+			IsEnabled:     optionalBool(arg, "enabled"),
+			LowThreshold:  optionalNumber(arg, "low"),
+			HighThreshold: optionalNumber(arg, "high"),
+		}
+	}
+	if motion, ok := args["motion"].(map[string]interface{}); ok {
+		update.MotionEnabled = optionalBool(motion, "enabled")
+		if v := optionalNumber(motion, "sensitivity"); v != nil {
+			sensitivity := int(*v)
+			update.MotionSensitivity = &sensitivity
+		}
+	}
+	update.AlarmEnabled = optionalBool(args, "alarm_enabled")
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	previous, err := s.protectClient.ConfigureSensor(ctx, sensorID, update)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to configure sensor", err), nil
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"sensor_id": sensorID,
+		"previous": map[string]interface{}{
+			"readings": previous.Readings(),
+			"motion":   previous.MotionSettings,
+			"alarm":    previous.AlarmSettings,
+		},
+	})
+}
+
+// formatMillis renders a nullable Unix millisecond timestamp as RFC 3339
+func formatMillis(ms *int64) interface{} {
+	if ms == nil || *ms == 0 {
+		return nil
+	}
+	return time.UnixMilli(*ms).Format(time.RFC3339)
+}
+
+func optionalNumber(args map[string]interface{}, key string) *float64 {
+	switch v := args[key].(type) {
+	case float64:
+		return &v
+	case int:
+		f := float64(v)
+		return &f
+	}
+	return nil
+}
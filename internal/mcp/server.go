@@ -3,39 +3,172 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/devices"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/diagnostics"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/eventlog"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/rules"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/scenes"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/scheduler"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/talkback"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/telemetry"
 	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/webhooks"
 )
 
 // Server represents the MCP server
 type Server struct {
 	protectClient *unifi.ProtectClient
+	webhooks      *webhooks.Dispatcher
+	rules         *rules.Engine
+	scheduler     *scheduler.Scheduler
+	scenes        *scenes.Manager
+	telemetry     *telemetry.Sampler
+	events        *eventlog.Recorder
+	devices       *devices.Registry
+	talkback      *talkback.Player
+	policy        Policy
 	server        *server.MCPServer
 	logger        *logrus.Entry
 }
 
+// toolCapabilities lists the tools that need an optional part of the Protect
+// API. When the console is known not to serve it the tool stays listed, with
+// the reason in its description, and calls fail without sending a request.
+// Tools are re-announced with the reason when a capability is lost later.
+var toolCapabilities = map[string]unifi.Capability{
+	"get_protect_events":             unifi.CapabilityEvents,
+	"camera_start_ptz_patrol":        unifi.CapabilityPTZ,
+	"camera_stop_ptz_patrol":         unifi.CapabilityPTZ,
+	"camera_goto_ptz_preset":         unifi.CapabilityPTZ,
+	"camera_create_talkback_session": unifi.CapabilityTalkback,
+	"camera_play_audio":              unifi.CapabilityTalkback,
+	"list_asset_files":               unifi.CapabilityFiles,
+	"upload_asset_file":              unifi.CapabilityFiles,
+}
+
+// Option configures optional server subsystems
+type Option func(*Server)
+
+// WithWebhooks enables the outbound webhook status tools
+func WithWebhooks(dispatcher *webhooks.Dispatcher) Option {
+	return func(s *Server) {
+		s.webhooks = dispatcher
+	}
+}
+
+// WithRules enables the rule management tools
+func WithRules(engine *rules.Engine) Option {
+	return func(s *Server) {
+		s.rules = engine
+	}
+}
+
+// WithScheduler enables the scheduled action tools
+func WithScheduler(sched *scheduler.Scheduler) Option {
+	return func(s *Server) {
+		s.scheduler = sched
+	}
+}
+
+// WithScenes enables the scene tools
+func WithScenes(manager *scenes.Manager) Option {
+	return func(s *Server) {
+		s.scenes = manager
+	}
+}
+
+// WithTelemetry enables the sensor history tools
+func WithTelemetry(sampler *telemetry.Sampler) Option {
+	return func(s *Server) {
+		s.telemetry = sampler
+	}
+}
+
+// WithEvents enables the event summary tool
+func WithEvents(recorder *eventlog.Recorder) Option {
+	return func(s *Server) {
+		s.events = recorder
+	}
+}
+
+// WithDevices enables the all-devices inventory tool
+func WithDevices(registry *devices.Registry) Option {
+	return func(s *Server) {
+		s.devices = registry
+	}
+}
+
+// WithTalkback enables the camera audio playback tool
+func WithTalkback(player *talkback.Player) Option {
+	return func(s *Server) {
+		s.talkback = player
+	}
+}
+
 // NewServer creates a new MCP server
-func NewServer(protectClient *unifi.ProtectClient) *Server {
+func NewServer(protectClient *unifi.ProtectClient, opts ...Option) *Server {
 	s := &Server{
 		protectClient: protectClient,
 		server:        server.NewMCPServer("unifi-protect-mcp", "0.1.0"),
 		logger:        logrus.WithField("component", "MCPServer"),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
 
+	protectClient.OnUnavailable(s.capabilityUnavailable)
 	s.registerTools()
 	return s
 }
 
+// capabilityUnavailable re-announces the tools that need a capability the
+// console turned out not to serve, with the reason in their description
+func (s *Server) capabilityUnavailable(c unifi.Capability) {
+	err := s.protectClient.RequireCapability(c)
+	if err == nil {
+		return
+	}
+	var updated []server.ServerTool
+	for name, capability := range toolCapabilities {
+		if capability != c {
+			continue
+		}
+		if tool := s.server.GetTool(name); tool != nil {
+			tool.Tool.Description += unavailableNote(err)
+			updated = append(updated, *tool)
+		}
+	}
+	if len(updated) > 0 {
+		s.server.AddTools(updated...)
+	}
+}
+
+// unavailableNote is appended to the description of a tool whose capability
+// the console does not serve
+func unavailableNote(err error) string {
+	return ". Unavailable: " + err.Error()
+}
+
 func (s *Server) registerTools() {
 	tools := []server.ServerTool{}
 
 	// Helper to create tool definitions
-	addTool := func(name, desc string, handler server.ToolHandlerFunc, properties map[string]any) {
+	addTool := func(name, desc string, handler server.ToolHandlerFunc, properties map[string]any, required ...string) {
+		if !s.policy.allowsTool(name) {
+			return
+		}
+		if capability, ok := toolCapabilities[name]; ok {
+			if err := s.protectClient.RequireCapability(capability); err != nil {
+				desc += unavailableNote(err)
+			}
+		}
 		tools = append(tools, server.ServerTool{
 			Tool: mcp.Tool{
 				Name:        name,
@@ -43,12 +176,20 @@ func (s *Server) registerTools() {
 				InputSchema: mcp.ToolInputSchema{
 					Type:       "object",
 					Properties: properties,
+					Required:   required,
 				},
 			},
 			Handler: handler,
 		})
 	}
 
+	// Helper for hand-written tools that pass their arguments to one
+	// operation, taking the argument schemas from the spec
+	addSpecTool := func(name, desc string, handler server.ToolHandlerFunc, operationID string, names map[string]string) {
+		properties, required := specInput(operationID, names)
+		addTool(name, desc, handler, properties, required...)
+	}
+
 	// Device and System queries
 	addTool("get_protect_cameras", "Get all cameras from Unifi Protect", s.getProtectCameras, map[string]any{})
 	addTool("get_protect_sensors", "Get all sensors from Unifi Protect", s.getProtectSensors, map[string]any{})
@@ -56,70 +197,354 @@ func (s *Server) registerTools() {
 	addTool("get_protect_chimes", "Get all chimes from Unifi Protect", s.getProtectChimes, map[string]any{})
 	addTool("get_protect_liveviews", "Get all live views from Unifi Protect", s.getProtectLiveviews, map[string]any{})
 
-	// Detailed resource information
-	addTool("get_camera_detailed", "Get detailed information about a specific camera", s.getCameraDetailed, map[string]any{
-		"camera_id": map[string]any{"type": "string", "description": "Camera ID (required)"},
-	})
-	addTool("get_sensor_detailed", "Get detailed information about a specific sensor", s.getSensorDetailed, map[string]any{
-		"sensor_id": map[string]any{"type": "string", "description": "Sensor ID (required)"},
-	})
-	addTool("get_light_detailed", "Get detailed information about a specific light", s.getLightDetailed, map[string]any{
-		"light_id": map[string]any{"type": "string", "description": "Light ID (required)"},
-	})
-	addTool("get_chime_detailed", "Get detailed information about a specific chime", s.getChimeDetailed, map[string]any{
-		"chime_id": map[string]any{"type": "string", "description": "Chime ID (required)"},
-	})
-	addTool("get_liveview_detailed", "Get detailed information about a specific live view", s.getLiveviewDetailed, map[string]any{
-		"liveview_id": map[string]any{"type": "string", "description": "Live view ID (required)"},
+	if s.devices != nil {
+		addTool("list_all_devices", "List every Protect device (NVR, cameras, sensors, lights, chimes, viewers, speakers, bridges, AI processors, AI ports, link stations) with its connection state", s.listAllDevices, map[string]any{
+			"model_key": map[string]any{"type": "string", "enum": unifi.ModelKeys, "description": "Only list this device class (optional)"},
+			"state":     map[string]any{"type": "string", "enum": []string{unifi.DeviceStateConnected, unifi.DeviceStateConnecting, unifi.DeviceStateDisconnected}, "description": "Only list devices in this state (optional)"},
+			"refresh":   map[string]any{"type": "boolean", "description": "Reload listable devices from the API first (optional, default false)"},
+		})
+	}
+
+	addTool("get_protect_inventory", "Get every Protect device in one normalized table (id, type, model, name, MAC, state, firmware) as JSON, CSV or Markdown", s.getProtectInventory, map[string]any{
+		"state":        map[string]any{"type": "string", "description": "Only include devices in this state, e.g. CONNECTED or DISCONNECTED (optional)"},
+		"type":         map[string]any{"type": "string", "enum": unifi.ModelKeys, "description": "Only include this device type (optional)"},
+		"model":        map[string]any{"type": "string", "description": "Only include models containing this text, case-insensitive (optional)"},
+		"name_pattern": map[string]any{"type": "string", "description": "Only include devices whose name matches this glob, case-insensitive (optional)"},
+		"format":       map[string]any{"type": "string", "enum": inventoryFormats, "description": "Output format (optional, default json)"},
 	})
 
+	// Detailed resource information
+	addSpecTool("get_camera_detailed", "Get detailed information about a specific camera", s.getCameraDetailed, "getCameraDetails", map[string]string{"id": "camera_id"})
+	addSpecTool("get_sensor_detailed", "Get detailed information about a specific sensor", s.getSensorDetailed, "getSensorDetails", map[string]string{"id": "sensor_id"})
+	addSpecTool("get_light_detailed", "Get detailed information about a specific light", s.getLightDetailed, "getLightDetails", map[string]string{"id": "light_id"})
+	addSpecTool("get_chime_detailed", "Get detailed information about a specific chime", s.getChimeDetailed, "getChimeDetails", map[string]string{"id": "chime_id"})
+	addSpecTool("get_liveview_detailed", "Get detailed information about a specific live view", s.getLiveviewDetailed, "getLiveViewDetails", map[string]string{"id": "liveview_id"})
+
 	// System and Configuration
 	addTool("get_protect_info", "Get system information from Unifi Protect", s.getProtectInfo, map[string]any{})
 	addTool("get_protect_nvr", "Get NVR information from Unifi Protect", s.getProtectNVR, map[string]any{})
-	addTool("get_protect_viewers", "Get all viewers from Unifi Protect", s.getProtectViewers, map[string]any{})
-	addTool("get_protect_viewer_detailed", "Get detailed information about a specific viewer", s.getProtectViewerDetailed, map[string]any{
-		"id": map[string]any{"type": "string", "description": "Viewer ID"},
+	addTool("run_diagnostics", "Check DNS, TCP and TLS to the console, the API key, each integration endpoint and the subscription sockets, with latency and a fix for each failure", s.runDiagnostics, map[string]any{
+		"checks": map[string]any{"type": "array", "items": map[string]any{"type": "string", "enum": diagnostics.Groups}, "description": "Check groups to run (optional, default all)"},
 	})
+	addTool("get_protect_viewers", "Get all viewers from Unifi Protect", s.getProtectViewers, map[string]any{})
+	addSpecTool("get_protect_viewer_detailed", "Get detailed information about a specific viewer", s.getProtectViewerDetailed, "getViewerDetails", map[string]string{"id": "id"})
 
 	// Modify Resources
-	addTool("patch_protect_viewer", "Update viewer settings", s.patchProtectViewer, map[string]any{
-		"id":       map[string]any{"type": "string", "description": "Viewer ID"},
-		"settings": map[string]any{"type": "object", "description": "Viewer settings to update"},
+	addSpecTool("patch_protect_viewer", "Update viewer settings", s.patchProtectViewer, "patchViewerSettings", map[string]string{"id": "id", "body": "settings"})
+
+	// Liveviews
+	addTool("build_liveview", "Create or update a liveview from camera names, a layout and per-slot cycling", s.buildLiveview, map[string]any{
+		"name":       map[string]any{"type": "string", "description": "Liveview name; an existing liveview with this name is updated"},
+		"layout":     map[string]any{"type": "integer", "minimum": 1, "maximum": 26, "description": "Number of slots (optional, defaults to the number of slots given)"},
+		"cameras":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Camera names or IDs, one per slot in order"},
+		"slots":      map[string]any{"type": "array", "items": map[string]any{"type": "object", "properties": map[string]any{"cameras": map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, "cycle_mode": map[string]any{"type": "string", "enum": unifi.LiveviewCycleModes}, "cycle_interval": map[string]any{"type": "integer", "description": "Seconds per camera"}}}, "description": "Slots with several cameras and cycling settings (use instead of or before cameras)"},
+		"is_global":  map[string]any{"type": "boolean", "description": "Make the liveview available to all users (optional, default false)"},
+		"is_default": map[string]any{"type": "boolean", "description": "Make the liveview the default for all viewers (optional, default false)"},
+	})
+	addTool("assign_liveview_to_viewer", "Show a liveview on a Protect viewer", s.assignLiveviewToViewer, map[string]any{
+		"viewer":   map[string]any{"type": "string", "description": "Viewer ID or name"},
+		"liveview": map[string]any{"type": "string", "description": "Liveview ID or name"},
 	})
 
 	// Camera Controls
-	addTool("camera_start_ptz_patrol", "Start a PTZ patrol on a camera", s.cameraStartPTZPatrol, map[string]any{
+	// The spec types PTZ slots as strings; these tools take integers
+	patrolProperties, patrolRequired := specInput("startACameraPtzPatrol", map[string]string{"id": "camera_id"})
+	patrolProperties["slot"] = map[string]any{"type": "integer", "minimum": 0, "maximum": 4, "description": "Patrol slot number (0-4)"}
+	addTool("camera_start_ptz_patrol", "Start a PTZ patrol on a camera", s.cameraStartPTZPatrol, patrolProperties, append(patrolRequired, "slot")...)
+	addSpecTool("camera_stop_ptz_patrol", "Stop a PTZ patrol on a camera", s.cameraStopPTZPatrol, "stopActiveCameraPtzPatrol", map[string]string{"id": "camera_id"})
+	presetProperties, presetRequired := specInput("movePtzCameraToPreset", map[string]string{"id": "camera_id"})
+	presetProperties["slot"] = map[string]any{"type": "integer", "minimum": 0, "maximum": 4, "description": "Preset slot number (0-4)"}
+	addTool("camera_goto_ptz_preset", "Move camera to a PTZ preset position", s.cameraGotoPTZPreset, presetProperties, append(presetRequired, "slot")...)
+	rtspsProperties, rtspsRequired := specInput("createRtspsStreamsForCamera", map[string]string{"id": "camera_id", "body": "config"})
+	rtspsProperties["config"] = describe(rtspsProperties["config"], "RTSPS stream configuration, e.g. {\"qualities\": [\"high\", \"low\"]}; defaults to high quality")
+	addTool("camera_create_rtsps_stream", "Create an RTSPS stream for a camera", s.cameraCreateRTSPSStream, rtspsProperties, optional(rtspsRequired, "config")...)
+	addTool("camera_create_talkback_session", "Create a talkback session with a camera", s.cameraCreateTalkbackSession, map[string]any{
 		"camera_id": map[string]any{"type": "string", "description": "Camera ID"},
-		"slot":      map[string]any{"type": "integer", "description": "Patrol slot number"},
+		"config":    map[string]any{"type": "object", "description": "Talkback session configuration"},
 	})
-	addTool("camera_stop_ptz_patrol", "Stop a PTZ patrol on a camera", s.cameraStopPTZPatrol, map[string]any{
-		"camera_id": map[string]any{"type": "string", "description": "Camera ID"},
+	if s.talkback != nil {
+		addTool("camera_play_audio", "Play a WAV file, raw PCM or spoken text through a camera speaker using a talkback session. Cameras that negotiate Opus, as most do, need ffmpeg with libopus on the server (TALKBACK_FFMPEG or PATH)", s.cameraPlayAudio, map[string]any{
+			"camera_id":       map[string]any{"type": "string", "description": "Camera ID (required)"},
+			"path":            map[string]any{"type": "string", "description": "Path of a local WAV file on the server (set one of path, content_base64 or text)"},
+			"content_base64":  map[string]any{"type": "string", "description": "Base64 encoded WAV, or raw 16-bit little-endian mono PCM when pcm_sample_rate is set"},
+			"pcm_sample_rate": map[string]any{"type": "number", "description": "Sample rate of raw PCM input; treats the input as headerless PCM (optional)"},
+			"text":            map[string]any{"type": "string", "description": "Text to speak using the configured text to speech command"},
+		})
+	}
+	addSpecTool("camera_disable_mic_permanently", "Disable microphone permanently on a camera", s.cameraDisableMicPermanently, "permanentlyDisableCameraMicrophone", map[string]string{"id": "camera_id"})
+	alarmProperties, alarmRequired := specInput("sendAWebhookToTheAlarmManager", map[string]string{"id": "webhook_id"})
+	alarmProperties["payload"] = map[string]any{"type": "object", "description": "Alarm trigger payload (optional)"}
+	addTool("trigger_webhook_alarm", "Trigger a configured alarm webhook", s.triggerWebhookAlarm, alarmProperties, alarmRequired...)
+
+	// Doorbell
+	addTool("set_doorbell_message", "Set the LCD message on a doorbell camera", s.setDoorbellMessage, map[string]any{
+		"camera_id": map[string]any{"type": "string", "description": "Doorbell camera ID"},
+		"type":      map[string]any{"type": "string", "enum": []string{"LEAVE_PACKAGE_AT_DOOR", "DO_NOT_DISTURB", "CUSTOM_MESSAGE", "IMAGE"}, "description": "Message type"},
+		"text":      map[string]any{"type": "string", "description": "Text for CUSTOM_MESSAGE (max 30 characters) or asset file name for IMAGE (see list_asset_files)"},
+		"duration":  map[string]any{"type": "string", "description": "How long to show the message, e.g. \"for 2 hours\", \"until 8am\" or \"forever\" (optional, defaults to the NVR default timeout)"},
 	})
-	addTool("camera_goto_ptz_preset", "Move camera to a PTZ preset position", s.cameraGotoPTZPreset, map[string]any{
-		"camera_id": map[string]any{"type": "string", "description": "Camera ID"},
-		"slot":      map[string]any{"type": "integer", "description": "Preset slot number"},
+	addTool("clear_doorbell_message", "Clear the LCD message on a doorbell camera", s.clearDoorbellMessage, map[string]any{
+		"camera_id": map[string]any{"type": "string", "description": "Doorbell camera ID"},
 	})
-	addTool("camera_create_rtsps_stream", "Create an RTSPS stream for a camera", s.cameraCreateRTSPSStream, map[string]any{
-		"camera_id": map[string]any{"type": "string", "description": "Camera ID"},
-		"config":    map[string]any{"type": "object", "description": "RTSPS stream configuration"},
+
+	// Asset files
+	assetProperties, assetRequired := specInput("getDeviceAssetFiles", map[string]string{"fileType": "file_type"})
+	assetProperties["file_type"] = describe(assetProperties["file_type"], "Asset file type (optional, default animations)")
+	addTool("list_asset_files", "List uploaded device asset files such as doorbell images", s.listAssetFiles, assetProperties, optional(assetRequired, "file_type")...)
+	addTool("upload_asset_file", "Upload a GIF, JPEG, PNG or audio asset from a local path or base64 content, optionally showing an image on a doorbell", s.uploadAssetFile, map[string]any{
+		"path":           map[string]any{"type": "string", "description": "Path of a local file on the server (set this or content_base64)"},
+		"content_base64": map[string]any{"type": "string", "description": "Base64 encoded file content (set this or path)"},
+		"filename":       map[string]any{"type": "string", "description": "Original file name (optional, defaults to the base name of path)"},
+		"file_type":      map[string]any{"type": "string", "enum": unifi.AssetFileTypes, "description": "Asset file type (optional, default animations)"},
+		"camera_id":      map[string]any{"type": "string", "description": "Doorbell camera ID to show the uploaded image on as an IMAGE message (optional)"},
+		"duration":       map[string]any{"type": "string", "description": "How long to show the image when camera_id is set, e.g. \"for 2 hours\" or \"forever\" (optional, defaults to the NVR default timeout)"},
 	})
-	addTool("camera_create_talkback_session", "Create a talkback session with a camera", s.cameraCreateTalkbackSession, map[string]any{
+
+	// Smart detection
+	addTool("get_camera_smart_detection", "Show the smart detection classes each camera supports and has enabled", s.getCameraSmartDetection, map[string]any{
+		"camera_id":    map[string]any{"type": "string", "description": "Camera ID (optional, defaults to all cameras)"},
+		"name_pattern": map[string]any{"type": "string", "description": "Case-insensitive camera name glob, e.g. \"*outdoor*\" (optional)"},
+	})
+	addTool("set_camera_smart_detection", "Change the smart detection classes on one or more cameras, rejecting classes the hardware does not support", s.setCameraSmartDetection, map[string]any{
+		"camera_ids":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Camera IDs to change"},
+		"name_pattern":     map[string]any{"type": "string", "description": "Case-insensitive camera name glob, e.g. \"*outdoor*\""},
+		"all_cameras":      map[string]any{"type": "boolean", "description": "Change every camera (use instead of camera_ids or name_pattern)"},
+		"enable":           map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Object or audio classes to turn on, e.g. [\"vehicle\"] or [\"alrmSmoke\"]"},
+		"disable":          map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Object or audio classes to turn off"},
+		"object_types":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Replace the enabled object classes (person, vehicle, package, licensePlate, face, animal)"},
+		"audio_types":      map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Replace the enabled audio classes"},
+		"skip_unsupported": map[string]any{"type": "boolean", "description": "Skip cameras that cannot detect a requested class instead of reporting them as rejected (optional, default false)"},
+		"dry_run":          map[string]any{"type": "boolean", "description": "Report the changes without applying them (optional, default false)"},
+	})
+
+	// Imaging
+	osdProperties := map[string]any{
+		"show_name":        map[string]any{"type": "boolean", "description": "Show the camera name overlay (optional)"},
+		"show_date":        map[string]any{"type": "boolean", "description": "Show the date overlay (optional)"},
+		"show_logo":        map[string]any{"type": "boolean", "description": "Show the logo overlay (optional)"},
+		"show_debug":       map[string]any{"type": "boolean", "description": "Show the debug overlay (optional)"},
+		"overlay_location": map[string]any{"type": "string", "enum": unifi.OverlayLocations, "description": "Overlay position (optional)"},
+	}
+	addTool("set_camera_video_mode", "Set a camera's video mode, checked against the modes it supports", s.setCameraVideoMode, map[string]any{
+		"camera_id":  map[string]any{"type": "string", "description": "Camera ID"},
+		"video_mode": map[string]any{"type": "string", "enum": unifi.VideoModes, "description": "Video mode"},
+	})
+	addTool("set_camera_hdr", "Set a camera's HDR mode", s.setCameraHDR, map[string]any{
 		"camera_id": map[string]any{"type": "string", "description": "Camera ID"},
-		"config":    map[string]any{"type": "object", "description": "Talkback session configuration"},
+		"hdr_type":  map[string]any{"type": "string", "enum": unifi.HDRTypes, "description": "HDR mode: auto (recommended), on or off"},
 	})
-	addTool("camera_disable_mic_permanently", "Disable microphone permanently on a camera", s.cameraDisableMicPermanently, map[string]any{
+	setOSDProperties := map[string]any{
 		"camera_id": map[string]any{"type": "string", "description": "Camera ID"},
+	}
+	for k, v := range osdProperties {
+		setOSDProperties[k] = v
+	}
+	addTool("set_camera_osd", "Update a camera's on screen display overlays", s.setCameraOSD, setOSDProperties)
+	addTool("apply_camera_imaging_profile", "Apply the same video mode, HDR and OSD settings to a group of cameras", s.applyCameraImagingProfile, map[string]any{
+		"camera_ids":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Camera IDs to change"},
+		"name_pattern":     map[string]any{"type": "string", "description": "Case-insensitive camera name glob, e.g. \"*outdoor*\""},
+		"all_cameras":      map[string]any{"type": "boolean", "description": "Change every camera (use instead of camera_ids or name_pattern)"},
+		"video_mode":       map[string]any{"type": "string", "enum": unifi.VideoModes, "description": "Video mode (optional)"},
+		"hdr_type":         map[string]any{"type": "string", "enum": unifi.HDRTypes, "description": "HDR mode (optional)"},
+		"osd":              map[string]any{"type": "object", "properties": osdProperties, "description": "OSD changes (optional)"},
+		"skip_unsupported": map[string]any{"type": "boolean", "description": "Skip cameras that do not support the profile instead of reporting them as rejected (optional, default false)"},
+		"dry_run":          map[string]any{"type": "boolean", "description": "Report the changes without applying them (optional, default false)"},
+	})
+
+	// Sensors
+	thresholdProperties := func(unit string) map[string]any {
+		return map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"enabled": map[string]any{"type": "boolean"},
+				"low":     map[string]any{"type": "number"},
+				"high":    map[string]any{"type": "number"},
+			},
+			"description": "Enable the capability and set low/high alert thresholds in " + unit + " (optional)",
+		}
+	}
+	addTool("get_sensor_readings", "Get temperature, humidity and light readings with units and statuses, plus contact, motion, leak, alarm and battery state", s.getSensorReadings, map[string]any{
+		"sensor_id": map[string]any{"type": "string", "description": "Sensor ID (optional, defaults to all sensors)"},
 	})
-	addTool("trigger_webhook_alarm", "Trigger a configured alarm webhook", s.triggerWebhookAlarm, map[string]any{
-		"webhook_id": map[string]any{"type": "string", "description": "Webhook ID"},
-		"payload":    map[string]any{"type": "object", "description": "Alarm trigger payload (optional)"},
+	addTool("configure_sensor_thresholds", "Set sensor alert thresholds and enable or disable sensing capabilities", s.configureSensorThresholds, map[string]any{
+		"sensor_id":     map[string]any{"type": "string", "description": "Sensor ID"},
+		"temperature":   thresholdProperties("°C, -39 to 124"),
+		"humidity":      thresholdProperties("%, 1 to 99"),
+		"light":         thresholdProperties("lux, 1 to 503192"),
+		"motion":        map[string]any{"type": "object", "properties": map[string]any{"enabled": map[string]any{"type": "boolean"}, "sensitivity": map[string]any{"type": "integer", "minimum": 0, "maximum": 100}}, "description": "Motion detection settings (optional)"},
+		"alarm_enabled": map[string]any{"type": "boolean", "description": "Enable smoke and carbon monoxide alarm detection (optional)"},
 	})
 
+	if s.telemetry != nil {
+		windowProperties := map[string]any{
+			"window": map[string]any{"type": "string", "description": "Window length ending now, e.g. \"12 hours\" or \"7d\" (optional, default 24 hours)"},
+			"from":   map[string]any{"type": "string", "description": "RFC 3339 window start (optional, use instead of window)"},
+			"to":     map[string]any{"type": "string", "description": "RFC 3339 window end (optional, default now)"},
+		}
+		summaryProperties := map[string]any{
+			"sensor": map[string]any{"type": "string", "description": "Sensor ID or name"},
+			"metric": map[string]any{"type": "string", "enum": telemetry.Metrics, "description": "Metric (optional, defaults to all)"},
+		}
+		seriesProperties := map[string]any{
+			"sensor": map[string]any{"type": "string", "description": "Sensor ID or name"},
+			"metric": map[string]any{"type": "string", "enum": telemetry.Metrics, "description": "Metric"},
+			"points": map[string]any{"type": "integer", "minimum": 1, "maximum": 500, "description": "Number of evenly spaced points (optional, default 24)"},
+		}
+		for k, v := range windowProperties {
+			summaryProperties[k] = v
+			seriesProperties[k] = v
+		}
+		addTool("get_sensor_history_summary", "Get min, max and average of recorded sensor temperature, humidity, light and battery over a time window", s.getSensorHistorySummary, summaryProperties)
+		addTool("get_sensor_history_series", "Get a recorded sensor metric resampled to evenly spaced points with a sparkline", s.getSensorHistorySeries, seriesProperties)
+	}
+
+	// Lights
+	addTool("set_light_mode", "Set when a floodlight turns on", s.setLightMode, map[string]any{
+		"light_id":  map[string]any{"type": "string", "description": "Light ID"},
+		"mode":      map[string]any{"type": "string", "enum": unifi.LightModes, "description": "always, motion (on when motion is detected) or off"},
+		"enable_at": map[string]any{"type": "string", "enum": unifi.LightEnableAts, "description": "fulltime or dark (only after sunset) (optional, keeps the current value)"},
+	})
+	addTool("set_light_brightness_and_duration", "Set a floodlight's brightness, on duration after motion, motion sensitivity or status LED", s.setLightBrightnessAndDuration, map[string]any{
+		"light_id":           map[string]any{"type": "string", "description": "Light ID"},
+		"brightness":         map[string]any{"type": "integer", "minimum": 1, "maximum": 6, "description": "LED brightness level 1-6 (optional)"},
+		"duration_seconds":   map[string]any{"type": "integer", "minimum": 0, "description": "How long the light stays on after motion, in seconds (optional)"},
+		"motion_sensitivity": map[string]any{"type": "integer", "minimum": 0, "maximum": 100, "description": "PIR motion sensitivity 0-100 (optional)"},
+		"status_led":         map[string]any{"type": "boolean", "description": "Enable the status LED indicator (optional)"},
+	})
+	addTool("force_light_on", "Force a floodlight on, optionally reverting automatically after a number of minutes", s.forceLightOn, map[string]any{
+		"light_id": map[string]any{"type": "string", "description": "Light ID"},
+		"minutes":  map[string]any{"type": "integer", "minimum": 0, "description": "Revert the override after this many minutes; persists across restarts (optional, 0 keeps the light on until released)"},
+		"enabled":  map[string]any{"type": "boolean", "description": "Set to false to release the override and cancel any pending revert (optional, default true)"},
+	})
+
+	// Chimes
+	addTool("pair_chime_doorbell", "Pair a doorbell camera with a chime", s.pairChimeDoorbell, map[string]any{
+		"chime_id":  map[string]any{"type": "string", "description": "Chime ID"},
+		"camera_id": map[string]any{"type": "string", "description": "Doorbell camera ID"},
+	})
+	addTool("unpair_chime_doorbell", "Unpair a doorbell camera from a chime", s.unpairChimeDoorbell, map[string]any{
+		"chime_id":  map[string]any{"type": "string", "description": "Chime ID"},
+		"camera_id": map[string]any{"type": "string", "description": "Doorbell camera ID"},
+	})
+	addTool("set_chime_ring_settings", "Set a chime's volume, repeat count or ringtone for one paired doorbell", s.setChimeRingSettings, map[string]any{
+		"chime_id":     map[string]any{"type": "string", "description": "Chime ID"},
+		"camera_id":    map[string]any{"type": "string", "description": "Paired doorbell camera ID"},
+		"volume":       map[string]any{"type": "integer", "minimum": 0, "maximum": 100, "description": "Volume 0-100 (optional)"},
+		"repeat_times": map[string]any{"type": "integer", "minimum": 1, "maximum": 10, "description": "Times to repeat the ringtone, 1-10 (optional)"},
+		"ringtone_id":  map[string]any{"type": "string", "description": "Ringtone ID (optional)"},
+	})
+	if s.scheduler != nil {
+		addTool("start_chime_quiet_hours", "Mute chimes now and restore their ring settings later", s.startChimeQuietHours, map[string]any{
+			"until":     map[string]any{"type": "string", "description": "When to restore, e.g. \"until 7am\" or \"for 8 hours\""},
+			"chime_ids": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Chime IDs (optional, defaults to all chimes)"},
+		})
+		addTool("end_chime_quiet_hours", "Restore chimes muted by start_chime_quiet_hours now", s.endChimeQuietHours, map[string]any{
+			"chime_ids": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Chime IDs (optional, defaults to all chimes)"},
+		})
+	}
+
 	// Events
 	addTool("get_protect_events", "Get events from Unifi Protect", s.getProtectEvents, map[string]any{
 		"limit":  map[string]any{"type": "integer", "description": "Number of events to retrieve (optional, default 50)"},
 		"offset": map[string]any{"type": "integer", "description": "Offset for pagination (optional, default 0)"},
 	})
+	if s.events != nil {
+		addTool("summarize_events", "Summarise recorded events by device, type and smart detection class, collapsing bursts into incidents and highlighting activity at unusual times or of rare classes", s.summarizeEvents, map[string]any{
+			"window":        map[string]any{"type": "string", "description": "Window length ending now, e.g. \"12 hours\" or \"7d\" (optional, default 24 hours)"},
+			"from":          map[string]any{"type": "string", "description": "RFC 3339 window start (optional, use instead of window)"},
+			"to":            map[string]any{"type": "string", "description": "RFC 3339 window end (optional, default now)"},
+			"devices":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Only include these camera or sensor IDs or names (optional)"},
+			"types":         map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Only include these event types, e.g. [\"smartDetectZone\", \"ring\"] (optional)"},
+			"gap":           map[string]any{"type": "string", "description": "Longest quiet spell within one incident, e.g. \"5m\" (optional, default 2m)"},
+			"baseline_days": map[string]any{"type": "integer", "minimum": 1, "maximum": 30, "description": "Days before the window that unusual activity is judged against (optional, default 14)"},
+			"max_incidents": map[string]any{"type": "integer", "minimum": 0, "description": "Incidents listed per device; 0 lists all (optional, default 10)"},
+			"timezone":      map[string]any{"type": "string", "description": "IANA time zone for hours of day, e.g. \"Europe/London\" (optional, default server local time)"},
+			"format":        map[string]any{"type": "string", "enum": []string{"json", eventlog.FormatMarkdown, eventlog.FormatHTML}, "description": "Output format (optional, default json)"},
+		})
+	}
+
+	// Outbound webhooks
+	if s.webhooks != nil {
+		addTool("get_webhook_deliveries", "Get outbound webhook endpoints and recent delivery status", s.getWebhookDeliveries, map[string]any{
+			"status":      map[string]any{"type": "string", "description": "Filter by delivery status: pending, delivered or failed (optional)"},
+			"endpoint_id": map[string]any{"type": "string", "description": "Filter by endpoint ID (optional)"},
+			"limit":       map[string]any{"type": "integer", "description": "Number of deliveries to return (optional, default 50)"},
+		})
+	}
+
+	// Rules
+	if s.rules != nil {
+		addTool("list_rules", "List automation rules with their last run result", s.listRules, map[string]any{})
+		addTool("create_rule", "Create an automation rule that runs actions when an event or schedule fires and all conditions hold", s.createRule, map[string]any{
+			"rule":      map[string]any{"type": "object", "description": "Rule definition with id, name, enabled, cooldown_seconds, trigger (event or schedule), conditions and actions"},
+			"rule_yaml": map[string]any{"type": "string", "description": "Rule definition as YAML (alternative to rule)"},
+		})
+		addTool("set_rule_enabled", "Enable or disable an automation rule", s.setRuleEnabled, map[string]any{
+			"rule_id": map[string]any{"type": "string", "description": "Rule ID"},
+			"enabled": map[string]any{"type": "boolean", "description": "Whether the rule should run"},
+		})
+		addTool("dry_run_rule", "Evaluate a rule's conditions against live device state without running its actions", s.dryRunRule, map[string]any{
+			"rule_id": map[string]any{"type": "string", "description": "Rule ID"},
+			"event":   map[string]any{"type": "object", "description": "Sample event to test the trigger against, e.g. {\"type\": \"smartDetectZone\", \"device\": \"<camera id>\", \"smartDetectTypes\": [\"person\"]} (optional)"},
+		})
+	}
+
+	// Scheduled actions
+	if s.scheduler != nil {
+		addTool("schedule_action", "Schedule a Protect action on a cron schedule or at a specific time", s.scheduleAction, map[string]any{
+			"name":     map[string]any{"type": "string", "description": "Human readable job name (optional)"},
+			"schedule": map[string]any{"type": "string", "description": "Five-field cron expression in server local time, e.g. \"0 22 * * *\" (use this or run_at)"},
+			"run_at":   map[string]any{"type": "string", "description": "RFC 3339 timestamp for a one-off run (use this or schedule)"},
+			"action":   map[string]any{"type": "string", "description": "Action to run: ptz_patrol_start, ptz_patrol_stop, ptz_goto_preset, doorbell_message, patch_camera, patch_light, patch_chime, patch_viewer, trigger_webhook_alarm"},
+			"params":   map[string]any{"type": "object", "description": "Action parameters, e.g. {\"camera_id\": \"...\", \"slot\": 2}"},
+		})
+		addTool("list_scheduled_actions", "List scheduled actions with their next run and recent outcomes", s.listScheduledActions, map[string]any{})
+		addTool("cancel_scheduled_action", "Cancel a scheduled action", s.cancelScheduledAction, map[string]any{
+			"job_id": map[string]any{"type": "string", "description": "Scheduled job ID"},
+		})
+	}
+
+	// Scenes
+	if s.scenes != nil {
+		addTool("capture_scene", "Capture the current settings of selected cameras, lights and chimes as a named scene", s.captureScene, map[string]any{
+			"name":        map[string]any{"type": "string", "description": "Scene name, e.g. Away, Home or Night (replaces an existing scene with the same name)"},
+			"description": map[string]any{"type": "string", "description": "Scene description (optional)"},
+			"camera_ids":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Camera IDs to capture (OSD, LED, smart detection and mic volume)"},
+			"light_ids":   map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Light IDs to capture (mode and device settings)"},
+			"chime_ids":   map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Chime IDs to capture (ring settings)"},
+		})
+		addTool("list_scenes", "List saved scenes", s.listScenes, map[string]any{})
+		addTool("apply_scene", "Apply a saved scene, patching only settings that differ and rolling back if any device fails", s.applyScene, map[string]any{
+			"name":    map[string]any{"type": "string", "description": "Scene name"},
+			"dry_run": map[string]any{"type": "boolean", "description": "Report the changes without applying them (optional, default false)"},
+		})
+		addTool("delete_scene", "Delete a saved scene", s.deleteScene, map[string]any{
+			"name": map[string]any{"type": "string", "description": "Scene name"},
+		})
+	}
+
+	// Generic access to the rest of the integration API
+	if spec, err := protectSpec(); err != nil {
+		s.logger.WithError(err).Error("Failed to load the API spec; protect_api_request is disabled")
+	} else if operations := s.apiOperations(spec); len(operations) > 0 {
+		ids := make([]string, len(operations))
+		for i, ep := range operations {
+			ids[i] = ep.OperationID
+		}
+		addTool("protect_api_request", apiRequestDescription(operations), s.protectAPIRequest, map[string]any{
+			"operation_id": map[string]any{"type": "string", "enum": ids, "description": "Operation ID"},
+			"path_params":  map[string]any{"type": "object", "description": "Path parameter values by name, e.g. {\"id\": \"<camera id>\"} (required when the path has parameters)"},
+			"query":        map[string]any{"type": "object", "description": "Query parameter values by name; arrays repeat the parameter (optional)"},
+			"body":         map[string]any{"description": "JSON request body (required when the operation takes one)"},
+		})
+	}
+
+	// Operations without a hand-written tool get one generated from the spec
+	for _, t := range specTools {
+		if _, ok := operationTools[t.OperationID]; !ok {
+			addTool(t.Name, t.Description, s.operationTool(t), t.Properties, t.Required...)
+		}
+	}
 
 	s.server.AddTools(tools...)
 }
@@ -374,6 +799,7 @@ func (s *Server) getProtectInfo(ctx context.Context, request mcp.CallToolRequest
 		"unique_id":           info.UniqueID,
 		"system_type":         info.SystemType,
 	}
+	result["capabilities"] = s.protectClient.CapabilityMatrix()
 
 	return mcp.NewToolResultJSON(result)
 }
@@ -578,6 +1004,19 @@ func (s *Server) triggerWebhookAlarm(ctx context.Context, request mcp.CallToolRe
 	return mcp.NewToolResultJSON(result)
 }
 
+// CallTool runs a registered tool's handler directly, without a transport, so
+// the command line can share the tools' validation and output
+func (s *Server) CallTool(ctx context.Context, name string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	tool := s.server.GetTool(name)
+	if tool == nil {
+		return nil, fmt.Errorf("unknown tool %s", name)
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	return tool.Handler(ctx, request)
+}
+
 // ServeStdio starts the MCP server with stdio transport
 func (s *Server) ServeStdio(ctx context.Context) error {
 	s.logger.Info("Starting UniFi Protect MCP Server")
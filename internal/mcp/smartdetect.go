@@ -0,0 +1,226 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// smartDetectView summarises what a camera can detect and what is enabled
+type smartDetectView struct {
+	CameraID            string   `json:"camera_id"`
+	Name                string   `json:"name"`
+	SupportedObjects    []string `json:"supported_object_types"`
+	SupportedAudio      []string `json:"supported_audio_types"`
+	EnabledObjects      []string `json:"enabled_object_types"`
+	EnabledAudio        []string `json:"enabled_audio_types"`
+	SupportsSmartDetect bool     `json:"supports_smart_detect"`
+}
+
+func newSmartDetectView(camera unifi.ProtectCamera) smartDetectView {
+	view := smartDetectView{
+		CameraID:         camera.ID,
+		Name:             camera.Name,
+		SupportedObjects: []string{},
+		SupportedAudio:   []string{},
+		EnabledObjects:   []string{},
+		EnabledAudio:     []string{},
+	}
+	if ff := camera.FeatureFlags; ff != nil {
+		view.SupportedObjects = append(view.SupportedObjects, ff.SmartDetectTypes...)
+		view.SupportedAudio = append(view.SupportedAudio, ff.SmartDetectAudioTypes...)
+	}
+	if sd := camera.SmartDetectSettings; sd != nil {
+		view.EnabledObjects = append(view.EnabledObjects, sd.ObjectTypes...)
+		view.EnabledAudio = append(view.EnabledAudio, sd.AudioTypes...)
+	}
+	view.SupportsSmartDetect = len(view.SupportedObjects)+len(view.SupportedAudio) > 0
+	return view
+}
+
+func (s *Server) getCameraSmartDetection(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: get_camera_smart_detection")
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	var ids []string
+	if cameraID := request.GetString("camera_id", ""); cameraID != "" {
+		ids = []string{cameraID}
+	}
+	cameras, err := s.selectCameras(ctx, ids, request.GetString("name_pattern", ""))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to select cameras", err), nil
+	}
+
+	views := make([]smartDetectView, 0, len(cameras))
+	for _, camera := range cameras {
+		views = append(views, newSmartDetectView(camera))
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"cameras": views,
+		"count":   len(views),
+	})
+}
+
+func (s *Server) setCameraSmartDetection(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: set_camera_smart_detection")
+
+	args := request.GetArguments()
+	_, replaceObjects := args["object_types"]
+	_, replaceAudio := args["audio_types"]
+	objectTypes := request.GetStringSlice("object_types", nil)
+	audioTypes := request.GetStringSlice("audio_types", nil)
+	enable := request.GetStringSlice("enable", nil)
+	disable := request.GetStringSlice("disable", nil)
+	if !replaceObjects && !replaceAudio && len(enable) == 0 && len(disable) == 0 {
+		return mcp.NewToolResultError("one of object_types, audio_types, enable or disable is required"), nil
+	}
+	cameraIDs := request.GetStringSlice("camera_ids", nil)
+	namePattern := request.GetString("name_pattern", "")
+	if len(cameraIDs) == 0 && namePattern == "" && !request.GetBool("all_cameras", false) {
+		return mcp.NewToolResultError("camera_ids, name_pattern or all_cameras is required"), nil
+	}
+	skipUnsupported := request.GetBool("skip_unsupported", false)
+	dryRun := request.GetBool("dry_run", false)
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	cameras, err := s.selectCameras(ctx, cameraIDs, namePattern)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to select cameras", err), nil
+	}
+
+	type cameraResult struct {
+		smartDetectView
+		Status          string   `json:"status"`
+		PreviousObjects []string `json:"previous_object_types,omitempty"`
+		PreviousAudio   []string `json:"previous_audio_types,omitempty"`
+		Unsupported     []string `json:"unsupported,omitempty"`
+		Error           string   `json:"error,omitempty"`
+	}
+
+	var results []cameraResult
+	var rejected, failed int
+	for _, camera := range cameras {
+		view := newSmartDetectView(camera)
+		result := cameraResult{
+			smartDetectView: view,
+			PreviousObjects: view.EnabledObjects,
+			PreviousAudio:   view.EnabledAudio,
+		}
+
+		objects, audio := view.EnabledObjects, view.EnabledAudio
+		if replaceObjects {
+			objects = objectTypes
+		}
+		if replaceAudio {
+			audio = audioTypes
+		}
+		// enable and disable take class names of either kind; each is routed
+		// to the list the camera reports it under
+		var unsupported []string
+		for _, t := range enable {
+			switch {
+			case slices.Contains(view.SupportedObjects, t):
+				objects = addClass(objects, t)
+			case slices.Contains(view.SupportedAudio, t):
+				audio = addClass(audio, t)
+			default:
+				unsupported = append(unsupported, t)
+			}
+		}
+		for _, t := range disable {
+			objects = removeClass(objects, t)
+			audio = removeClass(audio, t)
+		}
+		if camera.FeatureFlags != nil {
+			unsupported = append(unsupported, camera.FeatureFlags.UnsupportedSmartDetectTypes(objects, audio)...)
+		} else {
+			unsupported = append(unsupported, objects...)
+			unsupported = append(unsupported, audio...)
+		}
+
+		switch {
+		case len(unsupported) > 0:
+			result.Unsupported = dedupe(unsupported)
+			if skipUnsupported {
+				result.Status = "skipped"
+			} else {
+				result.Status = "rejected"
+				result.Error = "camera does not support the requested smart detection classes"
+				rejected++
+			}
+		case sameClasses(objects, view.EnabledObjects) && sameClasses(audio, view.EnabledAudio):
+			result.Status = "unchanged"
+		case dryRun:
+			result.Status = "would_apply"
+			result.EnabledObjects, result.EnabledAudio = objects, audio
+		default:
+			cam := camera
+			if _, err := s.protectClient.SetCameraSmartDetection(ctx, &cam, objects, audio); err != nil {
+				result.Status = "failed"
+				result.Error = err.Error()
+				failed++
+			} else {
+				result.Status = "applied"
+				result.EnabledObjects, result.EnabledAudio = objects, audio
+			}
+		}
+		results = append(results, result)
+	}
+
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"dry_run":  dryRun,
+		"success":  rejected == 0 && failed == 0,
+		"rejected": rejected,
+		"failed":   failed,
+		"cameras":  results,
+	})
+}
+
+func addClass(list []string, value string) []string {
+	if slices.Contains(list, value) {
+		return list
+	}
+	return append(append([]string{}, list...), value)
+}
+
+func removeClass(list []string, value string) []string {
+	out := []string{}
+	for _, v := range list {
+		if v != value {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func sameClasses(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x, y := append([]string{}, a...), append([]string{}, b...)
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func dedupe(list []string) []string {
+	out := []string{}
+	for _, v := range list {
+		out = addClass(out, v)
+	}
+	return out
+}
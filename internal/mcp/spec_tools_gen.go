@@ -0,0 +1,1002 @@
+// Code generated by toolgen from the Protect integration API spec 6.2.72. DO NOT EDIT.
+
+package mcp
+
+// specTools are the tool definitions derived from each operation in
+// docs/protect_integration.json, sorted by operation ID
+var specTools = []specTool{
+	{
+		OperationID: "createLiveView",
+		Name:        "create_live_view",
+		Method:      "POST",
+		Path:        "/v1/liveviews",
+		Description: "Create a new live view",
+		Properties: map[string]any{
+			"body": map[string]any{
+				"additionalProperties": false,
+				"description":          "Request body",
+				"properties": map[string]any{
+					"id": map[string]any{
+						"description": "The primary key of liveview",
+						"type":        "string",
+					},
+					"isDefault": map[string]any{
+						"description": "Whether this live view is the default one for all viewers.",
+						"type":        "boolean",
+					},
+					"isGlobal": map[string]any{
+						"description": "Whether this live view is global and available system-wide to all users",
+						"type":        "boolean",
+					},
+					"layout": map[string]any{
+						"description": "The number of slots this live view contains. Which as a consequence also affects the layout of the live view.",
+						"maximum":     26,
+						"minimum":     1,
+						"type":        "number",
+					},
+					"modelKey": map[string]any{
+						"const":       "liveview",
+						"description": "The model key of the liveview",
+						"type":        "string",
+					},
+					"name": map[string]any{
+						"description": "The name of this live view.",
+						"type":        "string",
+					},
+					"owner": map[string]any{
+						"description": "The primary key of user",
+						"type":        "string",
+					},
+					"slots": map[string]any{
+						"description": "List of cameras visible in each given slot. And cycling settings for each slot if it has multiple cameras listed.",
+						"items": map[string]any{
+							"description": "Which cameras will be visible in a given slot and how will they be cycled through",
+							"properties": map[string]any{
+								"cameras": map[string]any{
+									"items": map[string]any{
+										"description": "The primary key of camera",
+										"type":        "string",
+									},
+									"type": "array",
+								},
+								"cycleInterval": map[string]any{
+									"description": "How long should each camera stream be shown for in seconds until we cycle to the next camera",
+									"type":        "number",
+								},
+								"cycleMode": map[string]any{
+									"description": "Whether to switch to next camera in slot based on motion events or a strict time interval",
+									"enum":        []any{"motion", "time"},
+									"type":        "string",
+								},
+							},
+							"required": []any{"cameras", "cycleMode", "cycleInterval"},
+							"type":     "object",
+						},
+						"type": "array",
+					},
+				},
+				"required": []any{"name", "isDefault", "isGlobal", "layout", "slots"},
+				"type":     "object",
+			},
+		},
+		Required: []string{"body"},
+	},
+	{
+		OperationID: "createRtspsStreamsForCamera",
+		Name:        "create_rtsps_streams_for_camera",
+		Method:      "POST",
+		Path:        "/v1/cameras/{id}/rtsps-stream",
+		Description: "Returns RTSPS stream URLs for specified quality levels",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"body": map[string]any{
+				"additionalProperties": false,
+				"description":          "Request body",
+				"properties": map[string]any{
+					"qualities": map[string]any{
+						"description": "Array of quality levels of RTSPS streams",
+						"items": map[string]any{
+							"enum": []any{"high", "medium", "low", "package"},
+							"type": "string",
+						},
+						"minItems": 1,
+						"type":     "array",
+					},
+				},
+				"required": []any{"qualities"},
+				"type":     "object",
+			},
+			"id": map[string]any{
+				"description": "The primary key of camera",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id", "body"},
+	},
+	{
+		OperationID: "createTalkbackSessionForCamera",
+		Name:        "create_talkback_session_for_camera",
+		Method:      "POST",
+		Path:        "/v1/cameras/{id}/talkback-session",
+		Description: "Returns the talkback stream URL and audio configuration for a specific camera",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of camera",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+	{
+		OperationID: "deleteCameraRtspsStream",
+		Name:        "delete_camera_rtsps_stream",
+		Method:      "DELETE",
+		Path:        "/v1/cameras/{id}/rtsps-stream",
+		Description: "Remove the RTSPS stream for a specified camera",
+		PathParams:  []string{"id"},
+		QueryParams: []string{"qualities"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of camera",
+				"type":        "string",
+			},
+			"qualities": map[string]any{
+				"anyOf": []any{map[string]any{
+					"items": map[string]any{
+						"enum": []any{"high", "medium", "low", "package"},
+						"type": "string",
+					},
+					"minItems": 1,
+					"type":     "array",
+				}, map[string]any{
+					"enum": []any{"high", "medium", "low", "package"},
+					"type": "string",
+				}},
+				"description": "The array of quality levels for the RTSPS streams to be removed.",
+			},
+		},
+		Required: []string{"id", "qualities"},
+	},
+	{
+		OperationID: "getAllCameras",
+		Name:        "get_all_cameras",
+		Method:      "GET",
+		Path:        "/v1/cameras",
+		Description: "Get detailed information about all cameras",
+		Properties:  map[string]any{},
+	},
+	{
+		OperationID: "getAllChimes",
+		Name:        "get_all_chimes",
+		Method:      "GET",
+		Path:        "/v1/chimes",
+		Description: "Get detailed information about all chimes",
+		Properties:  map[string]any{},
+	},
+	{
+		OperationID: "getAllLights",
+		Name:        "get_all_lights",
+		Method:      "GET",
+		Path:        "/v1/lights",
+		Description: "Get detailed information about all lights",
+		Properties:  map[string]any{},
+	},
+	{
+		OperationID: "getAllLiveViews",
+		Name:        "get_all_live_views",
+		Method:      "GET",
+		Path:        "/v1/liveviews",
+		Description: "Get detailed information about all live views",
+		Properties:  map[string]any{},
+	},
+	{
+		OperationID: "getAllSensors",
+		Name:        "get_all_sensors",
+		Method:      "GET",
+		Path:        "/v1/sensors",
+		Description: "Get detailed information about all sensors",
+		Properties:  map[string]any{},
+	},
+	{
+		OperationID: "getAllViewers",
+		Name:        "get_all_viewers",
+		Method:      "GET",
+		Path:        "/v1/viewers",
+		Description: "Get detailed information about all viewers",
+		Properties:  map[string]any{},
+	},
+	{
+		OperationID: "getApplicationInformation",
+		Name:        "get_application_information",
+		Method:      "GET",
+		Path:        "/v1/meta/info",
+		Description: "Get generic information about the Protect application",
+		Properties:  map[string]any{},
+	},
+	{
+		OperationID: "getCameraDetails",
+		Name:        "get_camera_details",
+		Method:      "GET",
+		Path:        "/v1/cameras/{id}",
+		Description: "Get detailed information about a specific camera",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of camera",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+	{
+		OperationID: "getCameraSnapshot",
+		Name:        "get_camera_snapshot",
+		Method:      "GET",
+		Path:        "/v1/cameras/{id}/snapshot",
+		Description: "Get a snapshot image from a specific camera",
+		PathParams:  []string{"id"},
+		QueryParams: []string{"highQuality"},
+		Properties: map[string]any{
+			"highQuality": map[string]any{
+				"description": "Whether to force 1080P or higher resolution snapshot",
+				"enum":        []any{"true", "false"},
+				"type":        "string",
+			},
+			"id": map[string]any{
+				"description": "The primary key of camera",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+	{
+		OperationID: "getChimeDetails",
+		Name:        "get_chime_details",
+		Method:      "GET",
+		Path:        "/v1/chimes/{id}",
+		Description: "Get detailed information about a specific chime",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of chime",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+	{
+		OperationID: "getDeviceAssetFiles",
+		Name:        "get_device_asset_files",
+		Method:      "GET",
+		Path:        "/v1/files/{fileType}",
+		Description: "Get a list of all device asset files",
+		PathParams:  []string{"fileType"},
+		Properties: map[string]any{
+			"fileType": map[string]any{
+				"description": "Device asset file type",
+				"enum":        []any{"animations"},
+				"type":        "string",
+			},
+		},
+		Required: []string{"fileType"},
+	},
+	{
+		OperationID: "getLightDetails",
+		Name:        "get_light_details",
+		Method:      "GET",
+		Path:        "/v1/lights/{id}",
+		Description: "Get detailed information about a specific light",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of light",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+	{
+		OperationID: "getLiveViewDetails",
+		Name:        "get_live_view_details",
+		Method:      "GET",
+		Path:        "/v1/liveviews/{id}",
+		Description: "Get detailed information about a specific live view",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of liveview",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+	{
+		OperationID: "getNvrDetails",
+		Name:        "get_nvr_details",
+		Method:      "GET",
+		Path:        "/v1/nvrs",
+		Description: "Get detailed information about the NVR",
+		Properties:  map[string]any{},
+	},
+	{
+		OperationID: "getRtspsStreamsForCamera",
+		Name:        "get_rtsps_streams_for_camera",
+		Method:      "GET",
+		Path:        "/v1/cameras/{id}/rtsps-stream",
+		Description: "Returns existing RTSPS stream URLs for camera",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of camera",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+	{
+		OperationID: "getSensorDetails",
+		Name:        "get_sensor_details",
+		Method:      "GET",
+		Path:        "/v1/sensors/{id}",
+		Description: "Get detailed information about a specific sensor",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of sensor",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+	{
+		OperationID: "getViewerDetails",
+		Name:        "get_viewer_details",
+		Method:      "GET",
+		Path:        "/v1/viewers/{id}",
+		Description: "Get detailed information about a specific viewer",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of viewer",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+	{
+		OperationID: "movePtzCameraToPreset",
+		Name:        "move_ptz_camera_to_preset",
+		Method:      "POST",
+		Path:        "/v1/cameras/{id}/ptz/goto/{slot}",
+		Description: "Adjust the PTZ camera position to a specified preset",
+		PathParams:  []string{"id", "slot"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of camera",
+				"type":        "string",
+			},
+			"slot": map[string]any{
+				"description": "The slot number (0-4) of the preset to move the camera to",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id", "slot"},
+	},
+	{
+		OperationID: "patchCameraSettings",
+		Name:        "patch_camera_settings",
+		Method:      "PATCH",
+		Path:        "/v1/cameras/{id}",
+		Description: "Patch the settings for a specific camera",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"body": map[string]any{
+				"additionalProperties": false,
+				"description":          "Request body",
+				"properties": map[string]any{
+					"hdrType": map[string]any{
+						"description": "High Dynamic Range (HDR) mode setting.",
+						"enum":        []any{"auto", "on", "off"},
+						"type":        "string",
+					},
+					"lcdMessage": map[string]any{
+						"oneOf": []any{map[string]any{
+							"description": "Show a pre-defined \"do not disturb\" message on the doorbell",
+							"properties": map[string]any{
+								"resetAt": map[string]any{
+									"description": "UNIX timestamp when doorbell message should be removed (if not set then `nvr.doorbellSettings.defaultMessageResetTimeoutMs` is used, if set to `null` then interpreted as \"forever\")",
+									"type":        []any{"number", "null"},
+								},
+								"type": map[string]any{
+									"const": "DO_NOT_DISTURB",
+									"type":  "string",
+								},
+							},
+							"required": []any{"type"},
+							"type":     "object",
+						}, map[string]any{
+							"description": "Show a pre-defined \"leave package at door\" message on the doorbell",
+							"properties": map[string]any{
+								"resetAt": map[string]any{
+									"description": "UNIX timestamp when doorbell message should be removed (if not set then `nvr.doorbellSettings.defaultMessageResetTimeoutMs` is used, if set to `null` then interpreted as \"forever\")",
+									"type":        []any{"number", "null"},
+								},
+								"type": map[string]any{
+									"const": "LEAVE_PACKAGE_AT_DOOR",
+									"type":  "string",
+								},
+							},
+							"required": []any{"type"},
+							"type":     "object",
+						}, map[string]any{
+							"description": "Show a custom text message on the doorbell",
+							"properties": map[string]any{
+								"resetAt": map[string]any{
+									"description": "UNIX timestamp when doorbell message should be removed (if not set then `nvr.doorbellSettings.defaultMessageResetTimeoutMs` is used, if set to `null` then interpreted as \"forever\")",
+									"type":        []any{"number", "null"},
+								},
+								"text": map[string]any{
+									"description": "The custom text message to show on the doorbell",
+									"type":        "string",
+								},
+								"type": map[string]any{
+									"const": "CUSTOM_MESSAGE",
+									"type":  "string",
+								},
+							},
+							"required": []any{"type", "text"},
+							"type":     "object",
+						}, map[string]any{
+							"description": "Show a custom image on the doorbell",
+							"properties": map[string]any{
+								"resetAt": map[string]any{
+									"description": "UNIX timestamp when doorbell message should be removed (if not set then `nvr.doorbellSettings.defaultMessageResetTimeoutMs` is used, if set to `null` then interpreted as \"forever\")",
+									"type":        []any{"number", "null"},
+								},
+								"text": map[string]any{
+									"description": "The ID of the custom image to show on the doorbell",
+									"type":        "string",
+								},
+								"type": map[string]any{
+									"const": "IMAGE",
+									"type":  "string",
+								},
+							},
+							"required": []any{"type", "text"},
+							"type":     "object",
+						}},
+					},
+					"ledSettings": map[string]any{
+						"additionalProperties": false,
+						"description":          "LED settings.",
+						"properties": map[string]any{
+							"floodLed": map[string]any{
+								"description": "Indicates whether the flood LED is enabled.",
+								"type":        "boolean",
+							},
+							"isEnabled": map[string]any{
+								"description": "Indicates whether the status LED is enabled.",
+								"type":        "boolean",
+							},
+							"welcomeLed": map[string]any{
+								"description": "Indicates whether the welcome LED is enabled.",
+								"type":        "boolean",
+							},
+						},
+						"type": "object",
+					},
+					"micVolume": map[string]any{
+						"description": "Mic volume: a number from 0-100.",
+						"maximum":     100,
+						"minimum":     1,
+						"type":        "number",
+					},
+					"name": map[string]any{
+						"description": "The name of the camera",
+						"type":        "string",
+					},
+					"osdSettings": map[string]any{
+						"additionalProperties": false,
+						"description":          "On Screen Display settings.",
+						"properties": map[string]any{
+							"isDateEnabled": map[string]any{
+								"description": "Whether to show the date in the OSD.",
+								"type":        "boolean",
+							},
+							"isDebugEnabled": map[string]any{
+								"description": "Whether debug info is enabled.",
+								"type":        "boolean",
+							},
+							"isLogoEnabled": map[string]any{
+								"description": "Whether to show the logo in the bottom right corner.",
+								"type":        "boolean",
+							},
+							"isNameEnabled": map[string]any{
+								"description": "Whether to show the name in the OSD.",
+								"type":        "boolean",
+							},
+							"overlayLocation": map[string]any{
+								"description": "The location of the overlay on the screen.",
+								"enum":        []any{"topLeft", "topMiddle", "topRight", "bottomLeft", "bottomMiddle", "bottomRight"},
+								"type":        "string",
+							},
+						},
+						"type": "object",
+					},
+					"smartDetectSettings": map[string]any{
+						"additionalProperties": false,
+						"description":          "Smart detection settings for the camera.",
+						"properties": map[string]any{
+							"audioTypes": map[string]any{
+								"items": map[string]any{
+									"enum": []any{"alrmSmoke", "alrmCmonx", "alrmSiren", "alrmBabyCry", "alrmSpeak", "alrmBark", "alrmBurglar", "alrmCarHorn", "alrmGlassBreak"},
+									"type": "string",
+								},
+								"type": "array",
+							},
+							"objectTypes": map[string]any{
+								"items": map[string]any{
+									"enum": []any{"person", "vehicle", "package", "licensePlate", "face", "animal"},
+									"type": "string",
+								},
+								"type": "array",
+							},
+						},
+						"type": "object",
+					},
+					"videoMode": map[string]any{
+						"description": "Current video mode of the camera",
+						"enum":        []any{"default", "highFps", "sport", "slowShutter", "lprReflex", "lprNoneReflex"},
+						"type":        "string",
+					},
+				},
+				"type": "object",
+			},
+			"id": map[string]any{
+				"description": "The primary key of camera",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id", "body"},
+	},
+	{
+		OperationID: "patchChimeSettings",
+		Name:        "patch_chime_settings",
+		Method:      "PATCH",
+		Path:        "/v1/chimes/{id}",
+		Description: "Patch the settings for a specific chime",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"body": map[string]any{
+				"additionalProperties": false,
+				"description":          "Request body",
+				"properties": map[string]any{
+					"cameraIds": map[string]any{
+						"description": "The list of (doorbell-only) cameras which this chime is paired to.",
+						"items": map[string]any{
+							"description": "The primary key of camera",
+							"type":        "string",
+						},
+						"type": "array",
+					},
+					"name": map[string]any{
+						"description": "The name of the chime",
+						"type":        "string",
+					},
+					"ringSettings": map[string]any{
+						"description": "List of custom ringtone settings for (doorbell-only) cameras paired to this chime.",
+						"items": map[string]any{
+							"additionalProperties": false,
+							"properties": map[string]any{
+								"cameraId": map[string]any{
+									"description": "Which paired (doorbell-only) camera do these settings refer to.",
+									"type":        "string",
+								},
+								"repeatTimes": map[string]any{
+									"description": "How many times should the ringtone be repeated",
+									"maximum":     10,
+									"minimum":     1,
+									"type":        "number",
+								},
+								"ringtoneId": map[string]any{
+									"description": "The ID of the ringtone that should be played when the (doorbell-only) camera is rung.",
+									"type":        "string",
+								},
+								"volume": map[string]any{
+									"description": "How loud should the ringtone be played. 0 being silent and 100 the loudest.",
+									"maximum":     100,
+									"minimum":     0,
+									"type":        "number",
+								},
+							},
+							"required": []any{"cameraId", "repeatTimes", "ringtoneId", "volume"},
+							"type":     "object",
+						},
+						"type": "array",
+					},
+				},
+				"type": "object",
+			},
+			"id": map[string]any{
+				"description": "The primary key of chime",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id", "body"},
+	},
+	{
+		OperationID: "patchLightSettings",
+		Name:        "patch_light_settings",
+		Method:      "PATCH",
+		Path:        "/v1/lights/{id}",
+		Description: "Patch the settings for a specific light",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"body": map[string]any{
+				"additionalProperties": false,
+				"description":          "Request body",
+				"properties": map[string]any{
+					"isLightForceEnabled": map[string]any{
+						"description": "Whether the light has its main LED currently force-enabled.",
+						"type":        "boolean",
+					},
+					"lightDeviceSettings": map[string]any{
+						"description": "Hardware settings for light device.",
+						"properties": map[string]any{
+							"isIndicatorEnabled": map[string]any{
+								"description": "Turn on/off floodlight status LED indicator.",
+								"type":        "boolean",
+							},
+							"ledLevel": map[string]any{
+								"description": "Brightness level of the main LED (1-6).",
+								"maximum":     6,
+								"minimum":     1,
+								"type":        "number",
+							},
+							"pirDuration": map[string]any{
+								"description": "How long the light stays on after a motion event in milliseconds.",
+								"minimum":     0,
+								"type":        "number",
+							},
+							"pirSensitivity": map[string]any{
+								"description": "How sensitive is the PIR to motion (0-100)%.",
+								"maximum":     100,
+								"minimum":     0,
+								"type":        "number",
+							},
+						},
+						"type": "object",
+					},
+					"lightModeSettings": map[string]any{
+						"description": "Settings for when and how your light gets activated",
+						"properties": map[string]any{
+							"enableAt": map[string]any{
+								"description": "At what time is the lighting mode relevant and acted upon (this has no effect when mode is off).",
+								"enum":        []any{"fulltime", "dark"},
+							},
+							"mode": map[string]any{
+								"description": "When will floodlight turn on.",
+								"enum":        []any{"always", "motion", "off"},
+							},
+						},
+						"type": "object",
+					},
+					"name": map[string]any{
+						"description": "The name of the model",
+						"type":        "string",
+					},
+				},
+				"type": "object",
+			},
+			"id": map[string]any{
+				"description": "The primary key of light",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id", "body"},
+	},
+	{
+		OperationID: "patchLiveViewConfiguration",
+		Name:        "patch_live_view_configuration",
+		Method:      "PATCH",
+		Path:        "/v1/liveviews/{id}",
+		Description: "Patch the configuration about a specific live view",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"body": map[string]any{
+				"additionalProperties": false,
+				"description":          "Request body",
+				"properties": map[string]any{
+					"id": map[string]any{
+						"description": "The primary key of liveview",
+						"type":        "string",
+					},
+					"isDefault": map[string]any{
+						"description": "Whether this live view is the default one for all viewers.",
+						"type":        "boolean",
+					},
+					"isGlobal": map[string]any{
+						"description": "Whether this live view is global and available system-wide to all users",
+						"type":        "boolean",
+					},
+					"layout": map[string]any{
+						"description": "The number of slots this live view contains. Which as a consequence also affects the layout of the live view.",
+						"maximum":     26,
+						"minimum":     1,
+						"type":        "number",
+					},
+					"modelKey": map[string]any{
+						"const":       "liveview",
+						"description": "The model key of the liveview",
+						"type":        "string",
+					},
+					"name": map[string]any{
+						"description": "The name of this live view.",
+						"type":        "string",
+					},
+					"owner": map[string]any{
+						"description": "The primary key of user",
+						"type":        "string",
+					},
+					"slots": map[string]any{
+						"description": "List of cameras visible in each given slot. And cycling settings for each slot if it has multiple cameras listed.",
+						"items": map[string]any{
+							"description": "Which cameras will be visible in a given slot and how will they be cycled through",
+							"properties": map[string]any{
+								"cameras": map[string]any{
+									"items": map[string]any{
+										"description": "The primary key of camera",
+										"type":        "string",
+									},
+									"type": "array",
+								},
+								"cycleInterval": map[string]any{
+									"description": "How long should each camera stream be shown for in seconds until we cycle to the next camera",
+									"type":        "number",
+								},
+								"cycleMode": map[string]any{
+									"description": "Whether to switch to next camera in slot based on motion events or a strict time interval",
+									"enum":        []any{"motion", "time"},
+									"type":        "string",
+								},
+							},
+							"required": []any{"cameras", "cycleMode", "cycleInterval"},
+							"type":     "object",
+						},
+						"type": "array",
+					},
+				},
+				"type": "object",
+			},
+			"id": map[string]any{
+				"description": "The primary key of liveview",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id", "body"},
+	},
+	{
+		OperationID: "patchSensorSettings",
+		Name:        "patch_sensor_settings",
+		Method:      "PATCH",
+		Path:        "/v1/sensors/{id}",
+		Description: "Patch the settings for a specific sensor",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"body": map[string]any{
+				"additionalProperties": false,
+				"description":          "Request body",
+				"properties": map[string]any{
+					"alarmSettings": map[string]any{
+						"additionalProperties": false,
+						"description":          "Smoke and carbon monoxide alarm sensor settings.",
+						"properties": map[string]any{
+							"isEnabled": map[string]any{
+								"description": "Enable smoke and carbon monoxide alarm sensor.",
+								"type":        "boolean",
+							},
+						},
+						"type": "object",
+					},
+					"humiditySettings": map[string]any{
+						"additionalProperties": false,
+						"description":          "Relative humidity sensor settings.",
+						"properties": map[string]any{
+							"highThreshold": map[string]any{
+								"description": "Humidity high level threshold from 1 to 99 (%).",
+								"type":        []any{"number", "null"},
+							},
+							"isEnabled": map[string]any{
+								"description": "Enable relative humidity sensor.",
+								"type":        "boolean",
+							},
+							"lowThreshold": map[string]any{
+								"description": "Humidity low level threshold from 1 to 99 (%).",
+								"maximum":     99,
+								"minimum":     1,
+								"type":        []any{"number", "null"},
+							},
+							"margin": map[string]any{
+								"description": "Humidity threshold detection hysteresis margin (%). Read-only value decided by sensor implementation.",
+								"type":        "number",
+							},
+						},
+						"type": "object",
+					},
+					"lightSettings": map[string]any{
+						"additionalProperties": false,
+						"description":          "Ambient light sensor settings.",
+						"properties": map[string]any{
+							"highThreshold": map[string]any{
+								"description": "Ambient light interrupt threshold high level from 1 to 503192 (Lux).",
+								"type":        []any{"number", "null"},
+							},
+							"isEnabled": map[string]any{
+								"description": "Enable ambient light sensor.",
+								"type":        "boolean",
+							},
+							"lowThreshold": map[string]any{
+								"description": "Ambient light interrupt threshold low level from 1 to 503192 (Lux).",
+								"maximum":     503192,
+								"minimum":     1,
+								"type":        []any{"number", "null"},
+							},
+							"margin": map[string]any{
+								"description": "Ambient light threshold detection hysteresis margin (Lux). Read-only value decided by sensor implementation.",
+								"type":        "number",
+							},
+						},
+						"type": "object",
+					},
+					"motionSettings": map[string]any{
+						"additionalProperties": false,
+						"description":          "Motion sensor settings.",
+						"properties": map[string]any{
+							"isEnabled": map[string]any{
+								"description": "Enable motion sensor.",
+								"type":        "boolean",
+							},
+							"sensitivity": map[string]any{
+								"description": "Motion sensitivity (0-100).",
+								"maximum":     100,
+								"minimum":     0,
+								"type":        "number",
+							},
+						},
+						"type": "object",
+					},
+					"name": map[string]any{
+						"description": "The name of the model",
+						"type":        "string",
+					},
+					"temperatureSettings": map[string]any{
+						"additionalProperties": false,
+						"description":          "Temperature sensor settings.",
+						"properties": map[string]any{
+							"highThreshold": map[string]any{
+								"description": "Temperature high level threshold from -39 to 124 (C).",
+								"type":        []any{"number", "null"},
+							},
+							"isEnabled": map[string]any{
+								"description": "Enable temperature sensor.",
+								"type":        "boolean",
+							},
+							"lowThreshold": map[string]any{
+								"description": "Temperature low level threshold from -39 to 124 (C).",
+								"maximum":     124,
+								"minimum":     -39,
+								"type":        []any{"number", "null"},
+							},
+							"margin": map[string]any{
+								"description": "Temperature threshold detection hysteresis margin (C). Read-only value decided by sensor implementation.",
+								"type":        "number",
+							},
+						},
+						"type": "object",
+					},
+				},
+				"type": "object",
+			},
+			"id": map[string]any{
+				"description": "The primary key of sensor",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id", "body"},
+	},
+	{
+		OperationID: "patchViewerSettings",
+		Name:        "patch_viewer_settings",
+		Method:      "PATCH",
+		Path:        "/v1/viewers/{id}",
+		Description: "Patch the settings for a specific viewer",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"body": map[string]any{
+				"additionalProperties": false,
+				"description":          "Request body",
+				"properties": map[string]any{
+					"liveview": map[string]any{
+						"description": "The primary key of liveview",
+						"type":        []any{"string", "null"},
+					},
+					"name": map[string]any{
+						"description": "The name of the model",
+						"type":        "string",
+					},
+				},
+				"type": "object",
+			},
+			"id": map[string]any{
+				"description": "The primary key of viewer",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id", "body"},
+	},
+	{
+		OperationID: "permanentlyDisableCameraMicrophone",
+		Name:        "permanently_disable_camera_microphone",
+		Method:      "POST",
+		Path:        "/v1/cameras/{id}/disable-mic-permanently",
+		Description: "Disable the microphone for a specific camera. This action cannot be undone unless the camera is reset.",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of camera",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+	{
+		OperationID: "sendAWebhookToTheAlarmManager",
+		Name:        "send_a_webhook_to_the_alarm_manager",
+		Method:      "POST",
+		Path:        "/v1/alarm-manager/webhook/{id}",
+		Description: "Send a webhook to the alarm manager to trigger configured alarms",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "User defined string used to trigger only specific alarms. Alarm should be configured with the same ID to be triggered.",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+	{
+		OperationID: "startACameraPtzPatrol",
+		Name:        "start_a_camera_ptz_patrol",
+		Method:      "POST",
+		Path:        "/v1/cameras/{id}/ptz/patrol/start/{slot}",
+		Description: "Start a camera PTZ patrol",
+		PathParams:  []string{"id", "slot"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of camera",
+				"type":        "string",
+			},
+			"slot": map[string]any{
+				"description": "The slot number (0-4) of the patrol that is currently running, or null if no patrol is running",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id", "slot"},
+	},
+	{
+		OperationID: "stopActiveCameraPtzPatrol",
+		Name:        "stop_active_camera_ptz_patrol",
+		Method:      "POST",
+		Path:        "/v1/cameras/{id}/ptz/patrol/stop",
+		Description: "Stop active camera PTZ patrol",
+		PathParams:  []string{"id"},
+		Properties: map[string]any{
+			"id": map[string]any{
+				"description": "The primary key of camera",
+				"type":        "string",
+			},
+		},
+		Required: []string{"id"},
+	},
+}
@@ -0,0 +1,156 @@
+package mcp
+
+//go:generate go run ../../cmd/toolgen -spec ../../docs/protect_integration.json -out spec_tools_gen.go
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/toolgen"
+)
+
+// specTool is a tool definition derived from one API operation by toolgen.
+// Properties are self-contained JSON Schemas keyed by the operation's
+// parameter names, plus body for a JSON request body.
+type specTool struct {
+	OperationID string
+	Name        string
+	Method      string
+	Path        string
+	Description string
+	PathParams  []string
+	QueryParams []string
+	Properties  map[string]any
+	Required    []string
+}
+
+// operationTools names the hand-written tool that covers each operation.
+// Operations missing here are offered as generated tools.
+var operationTools = map[string]string{
+	"getAllCameras":                      "get_protect_cameras",
+	"getAllSensors":                      "get_protect_sensors",
+	"getAllLights":                       "get_protect_lights",
+	"getAllChimes":                       "get_protect_chimes",
+	"getAllLiveViews":                    "get_protect_liveviews",
+	"getAllViewers":                      "get_protect_viewers",
+	"getCameraDetails":                   "get_camera_detailed",
+	"getSensorDetails":                   "get_sensor_detailed",
+	"getLightDetails":                    "get_light_detailed",
+	"getChimeDetails":                    "get_chime_detailed",
+	"getLiveViewDetails":                 "get_liveview_detailed",
+	"getViewerDetails":                   "get_protect_viewer_detailed",
+	"getApplicationInformation":          "get_protect_info",
+	"getNvrDetails":                      "get_protect_nvr",
+	"patchViewerSettings":                "patch_protect_viewer",
+	"startACameraPtzPatrol":              "camera_start_ptz_patrol",
+	"stopActiveCameraPtzPatrol":          "camera_stop_ptz_patrol",
+	"movePtzCameraToPreset":              "camera_goto_ptz_preset",
+	"createRtspsStreamsForCamera":        "camera_create_rtsps_stream",
+	"createTalkbackSessionForCamera":     "camera_create_talkback_session",
+	"permanentlyDisableCameraMicrophone": "camera_disable_mic_permanently",
+	"sendAWebhookToTheAlarmManager":      "trigger_webhook_alarm",
+	"getDeviceAssetFiles":                "list_asset_files",
+}
+
+// operationTool names the hand-written or generated tool covering an operation
+func operationTool(operationID string) string {
+	if name, ok := operationTools[operationID]; ok {
+		return name
+	}
+	t, _ := findSpecTool(operationID)
+	return t.Name
+}
+
+func findSpecTool(operationID string) (specTool, bool) {
+	for _, t := range specTools {
+		if t.OperationID == operationID {
+			return t, true
+		}
+	}
+	return specTool{}, false
+}
+
+// isReadOnlySpecTool reports whether a generated tool only reads state
+func isReadOnlySpecTool(name string) bool {
+	for _, t := range specTools {
+		if t.Name == name {
+			return t.Method == "GET"
+		}
+	}
+	return false
+}
+
+// specInput derives input properties and the required list for a
+// hand-written tool from the operation it wraps. names maps the operation's
+// parameter names, and body for its request body, to the tool's argument
+// names; anything else the operation takes is left out.
+func specInput(operationID string, names map[string]string) (map[string]any, []string) {
+	properties := map[string]any{}
+	var required []string
+	t, ok := findSpecTool(operationID)
+	if !ok {
+		return properties, required
+	}
+	for from, to := range names {
+		if prop, ok := t.Properties[from]; ok {
+			properties[to] = prop
+		}
+	}
+	for _, name := range t.Required {
+		if to, ok := names[name]; ok {
+			required = append(required, to)
+		}
+	}
+	return properties, required
+}
+
+// describe returns a copy of a property schema with a tool's own description
+func describe(property any, description string) map[string]any {
+	described := map[string]any{"description": description}
+	if schema, ok := property.(map[string]any); ok {
+		for k, v := range schema {
+			if k != "description" {
+				described[k] = v
+			}
+		}
+	}
+	return described
+}
+
+// optional removes arguments a tool defaults from a required list
+func optional(required []string, names ...string) []string {
+	defaulted := map[string]bool{}
+	for _, name := range names {
+		defaulted[name] = true
+	}
+	var kept []string
+	for _, name := range required {
+		if !defaulted[name] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// operationTool calls a generated tool's operation with its arguments
+func (s *Server) operationTool(t specTool) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.Debugf("Tool called: %s", t.Name)
+
+		args := request.GetArguments()
+		pathParams := map[string]interface{}{}
+		for _, name := range t.PathParams {
+			if v, ok := args[name]; ok {
+				pathParams[name] = v
+			}
+		}
+		query := map[string]interface{}{}
+		for _, name := range t.QueryParams {
+			if v, ok := args[name]; ok {
+				query[name] = v
+			}
+		}
+		return s.callOperation(ctx, t.OperationID, pathParams, query, args[toolgen.BodyProperty])
+	}
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/talkback"
+)
+
+// maxAudioFileSize bounds audio read from disk; two minutes of 48kHz stereo
+// 16-bit WAV is about 23MB
+const maxAudioFileSize = 32 << 20
+
+func (s *Server) cameraPlayAudio(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: camera_play_audio")
+
+	cameraID := request.GetString("camera_id", "")
+	if cameraID == "" {
+		return mcp.NewToolResultError("camera_id is required"), nil
+	}
+	localPath := request.GetString("path", "")
+	content := request.GetString("content_base64", "")
+	text := request.GetString("text", "")
+	sources := 0
+	for _, v := range []string{localPath, content, text} {
+		if v != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return mcp.NewToolResultError("exactly one of path, content_base64 or text is required"), nil
+	}
+	if text != "" && !s.talkback.TTSEnabled() {
+		return mcp.NewToolResultError("text to speech is not configured, set TALKBACK_TTS_COMMAND"), nil
+	}
+
+	var audio *talkback.Audio
+	if text == "" {
+		data, errResult := readAudioInput(localPath, content)
+		if errResult != nil {
+			return errResult, nil
+		}
+		var err error
+		if rate := request.GetInt("pcm_sample_rate", 0); rate > 0 {
+			audio, err = talkback.DecodePCM(data, rate)
+		} else {
+			audio, err = talkback.DecodeWAV(data)
+		}
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Invalid audio", err), nil
+		}
+	}
+
+	if err := s.protectClient.Authenticate(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr("Authentication failed", err), nil
+	}
+
+	var (
+		result *talkback.Result
+		err    error
+	)
+	if text != "" {
+		result, err = s.talkback.Speak(ctx, cameraID, text)
+	} else {
+		result, err = s.talkback.Play(ctx, cameraID, audio)
+	}
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to play audio", err), nil
+	}
+	return mcp.NewToolResultJSON(result)
+}
+
+// readAudioInput loads audio bytes from a local file or base64 content
+func readAudioInput(localPath, content string) ([]byte, *mcp.CallToolResult) {
+	if content != "" {
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return nil, mcp.NewToolResultErrorFromErr("Invalid content_base64", err)
+		}
+		return data, nil
+	}
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, mcp.NewToolResultErrorFromErr("Failed to read file", err)
+	}
+	if info.Size() > maxAudioFileSize {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("file is %d bytes, maximum is %d", info.Size(), maxAudioFileSize))
+	}
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, mcp.NewToolResultErrorFromErr("Failed to read file", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/humantime"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/telemetry"
+)
+
+func (s *Server) getSensorHistorySummary(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: get_sensor_history_summary")
+
+	sensor := request.GetString("sensor", "")
+	if sensor == "" {
+		return mcp.NewToolResultJSON(map[string]interface{}{
+			"message": "sensor is required; these sensors have recorded history",
+			"sensors": s.telemetry.Sensors(),
+		})
+	}
+	from, to, err := historyWindow(request)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid window", err), nil
+	}
+	metrics := telemetry.Metrics
+	if metric := request.GetString("metric", ""); metric != "" {
+		if _, ok := telemetry.Units[metric]; !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unknown metric %q, expected one of %v", metric, telemetry.Metrics)), nil
+		}
+		metrics = []string{metric}
+	}
+
+	series, err := s.telemetry.Series(sensor)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to get sensor history", err), nil
+	}
+
+	summaries := make([]telemetry.Summary, 0, len(metrics))
+	for _, metric := range metrics {
+		summaries = append(summaries, series.Summarize(metric, from, to))
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"sensor_id": series.SensorID,
+		"name":      series.Name,
+		"from":      from.Format(time.RFC3339),
+		"to":        to.Format(time.RFC3339),
+		"metrics":   summaries,
+	})
+}
+
+func (s *Server) getSensorHistorySeries(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: get_sensor_history_series")
+
+	sensor := request.GetString("sensor", "")
+	metric := request.GetString("metric", "")
+	if sensor == "" || metric == "" {
+		return mcp.NewToolResultError("sensor and metric are required"), nil
+	}
+	if _, ok := telemetry.Units[metric]; !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("unknown metric %q, expected one of %v", metric, telemetry.Metrics)), nil
+	}
+	from, to, err := historyWindow(request)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid window", err), nil
+	}
+	points := request.GetInt("points", 24)
+	if points < 1 || points > 500 {
+		return mcp.NewToolResultError("points must be between 1 and 500"), nil
+	}
+
+	series, err := s.telemetry.Series(sensor)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to get sensor history", err), nil
+	}
+	buckets, err := series.Resample(metric, from, to, points)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to resample history", err), nil
+	}
+
+	values := make([]*float64, len(buckets))
+	for i, b := range buckets {
+		values[i] = b.Avg
+	}
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"sensor_id": series.SensorID,
+		"name":      series.Name,
+		"metric":    metric,
+		"unit":      telemetry.Units[metric],
+		"from":      from.Format(time.RFC3339),
+		"to":        to.Format(time.RFC3339),
+		"summary":   series.Summarize(metric, from, to),
+		"sparkline": telemetry.Sparkline(buckets),
+		"values":    values,
+		"buckets":   buckets,
+	})
+}
+
+// historyWindow resolves the query window from "from"/"to" timestamps or a
+// "window" length ending now, defaulting to the last 24 hours
+func historyWindow(request mcp.CallToolRequest) (time.Time, time.Time, error) {
+	to := time.Now()
+	if v := request.GetString("to", ""); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to must be an RFC 3339 timestamp")
+		}
+		to = t
+	}
+	if v := request.GetString("from", ""); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from must be an RFC 3339 timestamp")
+		}
+		if !to.After(from) {
+			return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+		}
+		return from, to, nil
+	}
+	window := 24 * time.Hour
+	if v := request.GetString("window", ""); v != "" {
+		d, err := humantime.Duration(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		window = d
+	}
+	return to.Add(-window), to, nil
+}
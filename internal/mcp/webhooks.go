@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/webhooks"
+)
+
+func (s *Server) getWebhookDeliveries(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Tool called: get_webhook_deliveries")
+
+	status := request.GetString("status", "")
+	switch status {
+	case "", webhooks.StatusPending, webhooks.StatusDelivered, webhooks.StatusFailed:
+	default:
+		return mcp.NewToolResultError("status must be one of: pending, delivered, failed"), nil
+	}
+	endpointID := request.GetString("endpoint_id", "")
+	limit := request.GetInt("limit", 50)
+
+	deliveries := s.webhooks.Deliveries(status, endpointID, limit)
+	return mcp.NewToolResultJSON(map[string]interface{}{
+		"endpoints":  s.webhooks.Endpoints(),
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	})
+}
@@ -0,0 +1,205 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Quirk relaxes validation of one operation where the published spec is
+// known to be stricter than the API it describes
+type Quirk struct {
+	Method string
+	Path   string
+	Reason string
+	// Request applies to request bodies sent to the operation
+	Request Options
+}
+
+// Checker validates requests and responses against a spec
+type Checker struct {
+	Spec   *Spec
+	Quirks []Quirk
+}
+
+// NewChecker creates a checker for spec with the given quirks
+func NewChecker(spec *Spec, quirks ...Quirk) *Checker {
+	return &Checker{Spec: spec, Quirks: quirks}
+}
+
+// RequestOptions returns the relaxations that apply to an operation's
+// request body
+func (c *Checker) RequestOptions(ep *Endpoint) Options {
+	for _, q := range c.Quirks {
+		if q.Method == ep.Method && q.Path == ep.Path {
+			return q.Request
+		}
+	}
+	return Options{}
+}
+
+// CheckRequest validates a request path relative to the spec's server URL,
+// such as /v1/cameras/abc, along with its query string and body
+func (c *Checker) CheckRequest(method, path string, query url.Values, contentType string, body []byte) (*Endpoint, []error) {
+	ep, pathParams, ok := c.Spec.Find(method, path)
+	if !ok {
+		return nil, []error{fmt.Errorf("%s %s is not in the spec", method, path)}
+	}
+
+	var errs []error
+	declared := map[string]bool{}
+	for _, p := range ep.Parameters {
+		switch p.In {
+		case "path":
+			if v, ok := pathParams[p.Name]; ok {
+				errs = append(errs, c.checkParameter(p, []string{v})...)
+			}
+		case "query":
+			declared[p.Name] = true
+			values, ok := query[p.Name]
+			if !ok {
+				if p.Required {
+					errs = append(errs, fmt.Errorf("query: missing required parameter %s", p.Name))
+				}
+				continue
+			}
+			errs = append(errs, c.checkParameter(p, values)...)
+		}
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !declared[name] {
+			errs = append(errs, fmt.Errorf("query: unexpected parameter %s", name))
+		}
+	}
+
+	errs = append(errs, c.checkRequestBody(ep, contentType, body)...)
+	return ep, errs
+}
+
+func (c *Checker) checkRequestBody(ep *Endpoint, contentType string, body []byte) []error {
+	empty := len(bytes.TrimSpace(body)) == 0
+	if ep.RequestBody == nil {
+		if !empty {
+			return []error{fmt.Errorf("body: operation takes no request body")}
+		}
+		return nil
+	}
+	if empty {
+		if ep.RequestBody.Required {
+			return []error{fmt.Errorf("body: missing required request body")}
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return []error{fmt.Errorf("body: invalid content type %q", contentType)}
+	}
+	media, ok := ep.RequestBody.Content[mediaType]
+	if !ok {
+		return []error{fmt.Errorf("body: content type %s is not accepted", mediaType)}
+	}
+	// Only JSON bodies are checked against their schema
+	if mediaType != "application/json" || media.Schema == nil {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []error{fmt.Errorf("body: invalid JSON: %w", err)}
+	}
+	return prefix("body", c.Spec.Validate(media.Schema, value, c.RequestOptions(ep)))
+}
+
+// checkParameter converts string values to the parameter's schema type
+// before validating them
+func (c *Checker) checkParameter(p Parameter, values []string) []error {
+	schema, err := c.Spec.Resolve(p.Schema)
+	if err != nil || schema == nil {
+		return nil
+	}
+	var value interface{}
+	if schema.Type.Has("array") {
+		var items []interface{}
+		itemSchema, _ := c.Spec.Resolve(schema.Items)
+		for _, v := range values {
+			// Arrays may be repeated or comma separated
+			for _, part := range strings.Split(v, ",") {
+				items = append(items, coerce(itemSchema, part))
+			}
+		}
+		value = items
+	} else {
+		value = coerce(schema, values[0])
+	}
+	return prefix(p.In+"."+p.Name, c.Spec.Validate(schema, value, Options{}))
+}
+
+func coerce(schema *Schema, raw string) interface{} {
+	if schema == nil {
+		return raw
+	}
+	switch {
+	case schema.Type.Has("integer"), schema.Type.Has("number"):
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			return f
+		}
+	case schema.Type.Has("boolean"):
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+// CheckResponse validates a response to an operation. Status codes without
+// their own entry are checked against the default response.
+func (c *Checker) CheckResponse(ep *Endpoint, status int, contentType string, body []byte) []error {
+	resp, ok := ep.Responses[strconv.Itoa(status)]
+	if !ok {
+		resp, ok = ep.Responses["default"]
+	}
+	if !ok {
+		return []error{fmt.Errorf("response: status %d is not documented", status)}
+	}
+
+	empty := len(bytes.TrimSpace(body)) == 0
+	if len(resp.Content) == 0 {
+		if !empty {
+			return []error{fmt.Errorf("response: status %d should have no body", status)}
+		}
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return []error{fmt.Errorf("response: invalid content type %q", contentType)}
+	}
+	media, ok := resp.Content[mediaType]
+	if !ok {
+		return []error{fmt.Errorf("response: content type %s is not documented for status %d", mediaType, status)}
+	}
+	if mediaType != "application/json" || media.Schema == nil {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []error{fmt.Errorf("response: invalid JSON: %w", err)}
+	}
+	return prefix("response", c.Spec.Validate(media.Schema, value, Options{}))
+}
+
+func prefix(where string, errs []error) []error {
+	for i, err := range errs {
+		errs[i] = fmt.Errorf("%s %w", where, err)
+	}
+	return errs
+}
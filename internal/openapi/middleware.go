@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Violation is a contract failure observed by a Validator
+type Violation struct {
+	Method string
+	Path   string
+	Err    error
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s %s: %v", v.Method, v.Path, v.Err)
+}
+
+// Call is one request observed by a Validator
+type Call struct {
+	Method string
+	// Operation is the matched path template, empty when nothing matched
+	Operation string
+	Status    int
+}
+
+// Validator is HTTP middleware that checks every request it forwards and
+// every response it returns against a spec. Prefixes are stripped from
+// request paths before matching, so the API can be served behind the
+// console's proxy path.
+type Validator struct {
+	checker  *Checker
+	next     http.Handler
+	prefixes []string
+
+	mu         sync.Mutex
+	violations []Violation
+	calls      []Call
+}
+
+// NewValidator wraps next with contract checks
+func NewValidator(checker *Checker, next http.Handler, prefixes ...string) *Validator {
+	return &Validator{checker: checker, next: next, prefixes: prefixes}
+}
+
+// ServeHTTP forwards the request to the wrapped handler and records any
+// violations in either direction
+func (v *Validator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	for _, p := range v.prefixes {
+		if rest, ok := strings.CutPrefix(path, p); ok {
+			path = rest
+			break
+		}
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	ep, errs := v.checker.CheckRequest(r.Method, path, r.URL.Query(), r.Header.Get("Content-Type"), body)
+
+	rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+	v.next.ServeHTTP(rec, r)
+
+	call := Call{Method: r.Method, Status: rec.status}
+	if ep != nil {
+		call.Operation = ep.Path
+		errs = append(errs, v.checker.CheckResponse(ep, rec.status, rec.Header().Get("Content-Type"), rec.body.Bytes())...)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.calls = append(v.calls, call)
+	for _, err := range errs {
+		v.violations = append(v.violations, Violation{Method: r.Method, Path: path, Err: err})
+	}
+}
+
+// Violations returns every violation observed so far
+func (v *Validator) Violations() []Violation {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]Violation(nil), v.violations...)
+}
+
+// Calls returns every request observed so far
+func (v *Validator) Calls() []Call {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]Call(nil), v.calls...)
+}
+
+// Reset forgets observed calls and violations
+func (v *Validator) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.violations = nil
+	v.calls = nil
+}
+
+// recorder tees the response so it can be validated after it is written
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush supports streaming responses
+func (r *recorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
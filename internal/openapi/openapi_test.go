@@ -0,0 +1,190 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testSpec = `{
+  "openapi": "3.1.0",
+  "paths": {
+    "/v1/things/{id}": {
+      "parameters": [],
+      "patch": {
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {
+          "type": "object",
+          "properties": {"name": {"type": "string", "minLength": 1}, "level": {"type": "integer", "minimum": 0, "maximum": 6}},
+          "additionalProperties": false
+        }}}},
+        "responses": {"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/thing"}}}}}
+      }
+    },
+    "/v1/things/{id}/stream": {
+      "delete": {
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}},
+          {"name": "qualities", "in": "query", "required": true, "schema": {"type": "array", "items": {"enum": ["high", "low"]}}}
+        ],
+        "responses": {"204": {"description": "Removed"}}
+      }
+    }
+  },
+  "components": {"schemas": {
+    "thing": {
+      "type": "object",
+      "properties": {
+        "id": {"type": "string"},
+        "state": {"type": "string", "enum": ["CONNECTED", "DISCONNECTED"]},
+        "lastSeen": {"type": ["number", "null"]},
+        "mode": {"oneOf": [{"const": "auto"}, {"type": "integer"}]}
+      },
+      "required": ["id", "state"]
+    },
+    "strict": {"allOf": [{"$ref": "#/components/schemas/thing"}], "additionalProperties": false}
+  }}
+}`
+
+func mustParse(t *testing.T) *Spec {
+	t.Helper()
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestFind(t *testing.T) {
+	spec := mustParse(t)
+	ep, params, ok := spec.Find("PATCH", "/v1/things/abc")
+	if !ok || ep.Path != "/v1/things/{id}" || params["id"] != "abc" {
+		t.Errorf("unexpected match %v %v %v", ep, params, ok)
+	}
+	if _, _, ok := spec.Find("GET", "/v1/things/abc"); ok {
+		t.Error("expected GET to be unmatched")
+	}
+	if _, _, ok := spec.Find("PATCH", "/v1/things"); ok {
+		t.Error("expected a shorter path to be unmatched")
+	}
+	if n := len(spec.Endpoints()); n != 2 {
+		t.Errorf("expected 2 endpoints, got %d", n)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	spec := mustParse(t)
+	thing, _ := spec.Schema("thing")
+	strict, _ := spec.Schema("strict")
+
+	valid := map[string]interface{}{"id": "a", "state": "CONNECTED", "lastSeen": nil, "mode": "auto"}
+	if errs := spec.Validate(thing, valid, Options{}); len(errs) != 0 {
+		t.Errorf("expected valid thing, got %v", errs)
+	}
+	if errs := spec.Validate(strict, valid, Options{}); len(errs) != 0 {
+		t.Errorf("expected allOf properties to be known, got %v", errs)
+	}
+
+	invalid := map[string]interface{}{"state": "ASLEEP", "lastSeen": "yesterday", "mode": 1.5, "extra": true}
+	errs := spec.Validate(strict, invalid, Options{})
+	for _, want := range []string{"missing required property id", "ASLEEP is not one of", "$.lastSeen: expected number or null", "$.mode: matches none", "unexpected property extra"} {
+		found := false
+		for _, err := range errs {
+			if strings.Contains(err.Error(), want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected an error containing %q, got %v", want, errs)
+		}
+	}
+
+	if errs := spec.Validate(thing, map[string]interface{}{"state": "CONNECTED"}, Options{IgnoreRequired: []string{"id"}}); len(errs) != 0 {
+		t.Errorf("expected ignored required property to pass, got %v", errs)
+	}
+	if errs := spec.Validate(thing, map[string]interface{}{}, Options{Partial: true}); len(errs) != 0 {
+		t.Errorf("expected partial object to pass, got %v", errs)
+	}
+}
+
+func TestCheckRequest(t *testing.T) {
+	checker := NewChecker(mustParse(t))
+
+	if _, errs := checker.CheckRequest("PATCH", "/v1/things/a", nil, "application/json", []byte(`{"level":3}`)); len(errs) != 0 {
+		t.Errorf("expected valid request, got %v", errs)
+	}
+	if _, errs := checker.CheckRequest("PATCH", "/v1/things/a", nil, "application/json", []byte(`{"level":7,"colour":"red"}`)); len(errs) != 2 {
+		t.Errorf("expected range and property errors, got %v", errs)
+	}
+	if _, errs := checker.CheckRequest("PATCH", "/v1/things/a", nil, "", nil); len(errs) != 1 {
+		t.Errorf("expected missing body error, got %v", errs)
+	}
+	if _, errs := checker.CheckRequest("DELETE", "/v1/things/a/stream", url.Values{"qualities": {"high,low"}}, "", nil); len(errs) != 0 {
+		t.Errorf("expected valid query, got %v", errs)
+	}
+	if _, errs := checker.CheckRequest("DELETE", "/v1/things/a/stream", url.Values{"qualities": {"ultra"}, "force": {"1"}}, "", nil); len(errs) != 2 {
+		t.Errorf("expected enum and unknown parameter errors, got %v", errs)
+	}
+	if _, errs := checker.CheckRequest("DELETE", "/v1/things/a/stream", nil, "", []byte(`{}`)); len(errs) != 2 {
+		t.Errorf("expected missing query and unexpected body errors, got %v", errs)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	checker := NewChecker(mustParse(t))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"a","state":"LOST"}`))
+	})
+	v := NewValidator(checker, handler, "/api")
+	srv := httptest.NewServer(v)
+	defer srv.Close()
+
+	req, _ := http.NewRequest("PATCH", srv.URL+"/api/v1/things/a", strings.NewReader(`{"name":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	calls := v.Calls()
+	if len(calls) != 1 || calls[0].Operation != "/v1/things/{id}" || calls[0].Status != 200 {
+		t.Errorf("unexpected calls %+v", calls)
+	}
+	violations := v.Violations()
+	if len(violations) != 1 || !strings.Contains(violations[0].String(), "LOST is not one of") {
+		t.Errorf("expected one response violation, got %v", violations)
+	}
+}
+
+func TestOperationIDs(t *testing.T) {
+	spec := mustParse(t)
+	ep, ok := spec.Operation("deleteV1ThingsByIdStream")
+	if !ok || ep.Method != "DELETE" || ep.Path != "/v1/things/{id}/stream" {
+		t.Fatalf("expected an ID derived from the method and path, got %+v", ep)
+	}
+	path, err := ep.Expand(map[string]string{"id": "a/b"})
+	if err != nil || path != "/v1/things/a%2Fb/stream" {
+		t.Errorf("expected an escaped path, got %q %v", path, err)
+	}
+	if _, err := ep.Expand(map[string]string{}); err == nil {
+		t.Error("expected a missing path parameter to fail")
+	}
+	if _, err := ep.Expand(map[string]string{"id": "a", "slot": "1"}); err == nil {
+		t.Error("expected an undeclared path parameter to fail")
+	}
+
+	summarized, err := Parse([]byte(`{"paths": {"/v1/nvrs": {"get": {"summary": "Get NVR details"}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := summarized.Operation("getNvrDetails"); !ok {
+		t.Error("expected an ID derived from the summary")
+	}
+	if _, err := Parse([]byte(`{"paths": {"/a": {"get": {"operationId": "x"}}, "/b": {"get": {"operationId": "x"}}}}`)); err == nil {
+		t.Error("expected duplicate operation IDs to be rejected")
+	}
+}
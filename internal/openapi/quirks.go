@@ -0,0 +1,15 @@
+package openapi
+
+// ProtectQuirks relax checks where docs/protect_integration.json is stricter
+// than the API it describes
+var ProtectQuirks = []Quirk{{
+	Method:  "POST",
+	Path:    "/v1/liveviews",
+	Reason:  "the create body reuses the liveview schema, which requires server-assigned fields",
+	Request: Options{IgnoreRequired: []string{"id", "modelKey", "owner"}},
+}, {
+	Method:  "PATCH",
+	Path:    "/v1/liveviews/{id}",
+	Reason:  "the update body reuses the full liveview schema, but the API applies partial updates",
+	Request: Options{Partial: true},
+}}
@@ -0,0 +1,348 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema used by the spec
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Additional        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Const                json.RawMessage    `json:"const,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Discriminator        *Discriminator     `json:"discriminator,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Examples             []interface{}      `json:"examples,omitempty"`
+}
+
+// Types is a schema type, written in the spec as a string or a list
+type Types []string
+
+// UnmarshalJSON accepts "string" or ["string", "null"]
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// Has reports whether the type list includes name
+func (t Types) Has(name string) bool {
+	return slices.Contains(t, name)
+}
+
+// Additional is additionalProperties: either a boolean or a schema
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// UnmarshalJSON accepts a boolean or a schema
+func (a *Additional) UnmarshalJSON(data []byte) error {
+	var allowed bool
+	if err := json.Unmarshal(data, &allowed); err == nil {
+		a.Allowed = allowed
+		return nil
+	}
+	a.Allowed = true
+	a.Schema = &Schema{}
+	return json.Unmarshal(data, a.Schema)
+}
+
+// Discriminator selects a oneOf branch by a property value
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping"`
+}
+
+// Options relax validation for known spec quirks
+type Options struct {
+	// IgnoreRequired lists properties whose absence is not an error, such
+	// as server-assigned fields in a create request
+	IgnoreRequired []string
+	// Partial skips the required check on the top-level object, for PATCH
+	// bodies described with the full resource schema
+	Partial bool
+}
+
+// Validate checks a decoded JSON value against a schema and returns every
+// violation found, each prefixed with its location
+func (s *Spec) Validate(schema *Schema, value interface{}, opts Options) []error {
+	v := validator{spec: s, opts: opts}
+	v.check(schema, value, "$")
+	return v.errs
+}
+
+type validator struct {
+	spec *Spec
+	opts Options
+	errs []error
+}
+
+func (v *validator) fail(at, format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", at, fmt.Sprintf(format, args...)))
+}
+
+// sub validates against a schema without recording errors
+func (v *validator) sub(schema *Schema, value interface{}, at string) []error {
+	inner := validator{spec: v.spec, opts: v.opts}
+	inner.check(schema, value, at)
+	return inner.errs
+}
+
+func (v *validator) check(schema *Schema, value interface{}, at string) {
+	schema, err := v.spec.Resolve(schema)
+	if err != nil {
+		v.fail(at, "%v", err)
+		return
+	}
+	if schema == nil {
+		return
+	}
+
+	for _, part := range schema.AllOf {
+		v.check(part, value, at)
+	}
+	if len(schema.AnyOf) > 0 {
+		matched := false
+		for _, option := range schema.AnyOf {
+			if len(v.sub(option, value, at)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(at, "matches none of the allowed schemas")
+		}
+	}
+	if len(schema.OneOf) > 0 {
+		v.checkOneOf(schema, value, at)
+	}
+
+	if len(schema.Type) > 0 && !matchesType(schema.Type, value) {
+		v.fail(at, "expected %s, got %s", strings.Join(schema.Type, " or "), typeName(value))
+		return
+	}
+	if len(schema.Const) > 0 {
+		var want interface{}
+		_ = json.Unmarshal(schema.Const, &want)
+		if !reflect.DeepEqual(want, value) {
+			v.fail(at, "expected %v, got %v", want, value)
+		}
+	}
+	if len(schema.Enum) > 0 {
+		found := false
+		for _, option := range schema.Enum {
+			if reflect.DeepEqual(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(at, "%v is not one of %v", value, schema.Enum)
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		v.checkObject(schema, value, at)
+	case []interface{}:
+		if schema.MinItems != nil && len(value) < *schema.MinItems {
+			v.fail(at, "has %d items, minimum is %d", len(value), *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(value) > *schema.MaxItems {
+			v.fail(at, "has %d items, maximum is %d", len(value), *schema.MaxItems)
+		}
+		if schema.Items != nil {
+			for i, item := range value {
+				v.check(schema.Items, item, fmt.Sprintf("%s[%d]", at, i))
+			}
+		}
+	case float64:
+		if schema.Minimum != nil && value < *schema.Minimum {
+			v.fail(at, "%v is below the minimum %v", value, *schema.Minimum)
+		}
+		if schema.Maximum != nil && value > *schema.Maximum {
+			v.fail(at, "%v is above the maximum %v", value, *schema.Maximum)
+		}
+		if schema.ExclusiveMinimum != nil && value <= *schema.ExclusiveMinimum {
+			v.fail(at, "%v must be greater than %v", value, *schema.ExclusiveMinimum)
+		}
+		if schema.ExclusiveMaximum != nil && value >= *schema.ExclusiveMaximum {
+			v.fail(at, "%v must be less than %v", value, *schema.ExclusiveMaximum)
+		}
+	case string:
+		n := len([]rune(value))
+		if schema.MinLength != nil && n < *schema.MinLength {
+			v.fail(at, "is %d characters, minimum is %d", n, *schema.MinLength)
+		}
+		if schema.MaxLength != nil && n > *schema.MaxLength {
+			v.fail(at, "is %d characters, maximum is %d", n, *schema.MaxLength)
+		}
+	}
+}
+
+func (v *validator) checkOneOf(schema *Schema, value interface{}, at string) {
+	if d := schema.Discriminator; d != nil {
+		if obj, ok := value.(map[string]interface{}); ok {
+			key, _ := obj[d.PropertyName].(string)
+			if ref, ok := d.Mapping[key]; ok {
+				v.check(&Schema{Ref: ref}, value, at)
+				return
+			}
+			v.fail(at, "unknown %s %q", d.PropertyName, key)
+			return
+		}
+	}
+	matches := 0
+	var closest []error
+	for _, option := range schema.OneOf {
+		errs := v.sub(option, value, at)
+		if len(errs) == 0 {
+			matches++
+		} else if closest == nil || len(errs) < len(closest) {
+			closest = errs
+		}
+	}
+	switch {
+	case matches == 0:
+		v.fail(at, "matches none of the allowed schemas, closest: %v", closest)
+	case matches > 1:
+		v.fail(at, "matches %d schemas, expected exactly one", matches)
+	}
+}
+
+func (v *validator) checkObject(schema *Schema, obj map[string]interface{}, at string) {
+	partial := v.opts.Partial && at == "$"
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok && !partial && !slices.Contains(v.opts.IgnoreRequired, name) {
+			v.fail(at, "missing required property %s", name)
+		}
+	}
+
+	known := v.knownProperties(schema)
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if prop, ok := schema.Properties[key]; ok {
+			v.check(prop, obj[key], at+"."+key)
+			continue
+		}
+		if ap := schema.AdditionalProperties; ap != nil {
+			switch {
+			case ap.Schema != nil:
+				v.check(ap.Schema, obj[key], at+"."+key)
+			case !ap.Allowed && !known[key]:
+				v.fail(at, "unexpected property %s", key)
+			}
+		}
+	}
+}
+
+// knownProperties collects properties declared directly or through allOf,
+// which additionalProperties: false is meant to permit
+func (v *validator) knownProperties(schema *Schema) map[string]bool {
+	known := map[string]bool{}
+	var walk func(*Schema, int)
+	walk = func(s *Schema, depth int) {
+		s, err := v.spec.Resolve(s)
+		if err != nil || s == nil || depth > 8 {
+			return
+		}
+		for name := range s.Properties {
+			known[name] = true
+		}
+		for _, part := range s.AllOf {
+			walk(part, depth+1)
+		}
+	}
+	walk(schema, 0)
+	return known
+}
+
+func matchesType(types Types, value interface{}) bool {
+	for _, t := range types {
+		switch t {
+		case "null":
+			if value == nil {
+				return true
+			}
+		case "object":
+			if _, ok := value.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]interface{}); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := value.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
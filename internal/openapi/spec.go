@@ -0,0 +1,286 @@
+// Package openapi loads the Protect integration API description and checks
+// JSON values, requests and responses against it. It implements the subset of
+// OpenAPI 3.1 and JSON Schema that docs/protect_integration.json uses.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Spec is a parsed OpenAPI document
+type Spec struct {
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Servers []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// Operation is one method on one path
+type Operation struct {
+	// OperationID is the spec's operationId, or one derived from the summary
+	// when the spec leaves it out
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Description string               `json:"description"`
+	Tags        []string             `json:"tags"`
+	Parameters  []Parameter          `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the accepted request content types
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response for one status code
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// MediaType holds the schema for one content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Endpoint is an operation together with its method and path template
+type Endpoint struct {
+	Method string
+	Path   string
+	*Operation
+}
+
+var methods = map[string]bool{"get": true, "put": true, "post": true, "patch": true, "delete": true}
+
+// Load reads an OpenAPI JSON document
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes an OpenAPI JSON document
+func Parse(data []byte) (*Spec, error) {
+	var raw struct {
+		Spec
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	spec := raw.Spec
+	spec.Paths = map[string]map[string]*Operation{}
+	for path, item := range raw.Paths {
+		spec.Paths[path] = map[string]*Operation{}
+		for method, body := range item {
+			// Path items may also hold shared parameters and summaries
+			if !methods[method] {
+				continue
+			}
+			var op Operation
+			if err := json.Unmarshal(body, &op); err != nil {
+				return nil, fmt.Errorf("failed to parse %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			spec.Paths[path][strings.ToUpper(method)] = &op
+		}
+	}
+	if err := spec.assignOperationIDs(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// assignOperationIDs derives an ID for every operation without one. The
+// Protect spec has none, so IDs come from the summary, e.g. "Get camera
+// details" becomes getCameraDetails, or from the method and path when two
+// summaries collide.
+func (s *Spec) assignOperationIDs() error {
+	seen := map[string]string{}
+	var derived []Endpoint
+	for _, ep := range s.Endpoints() {
+		if ep.OperationID == "" {
+			derived = append(derived, ep)
+			continue
+		}
+		if other, ok := seen[ep.OperationID]; ok {
+			return fmt.Errorf("operationId %s is used by both %s and %s %s", ep.OperationID, other, ep.Method, ep.Path)
+		}
+		seen[ep.OperationID] = ep.Method + " " + ep.Path
+	}
+	for _, ep := range derived {
+		id := camelCase(ep.Summary)
+		if _, taken := seen[id]; id == "" || taken {
+			id = camelCase(strings.ToLower(ep.Method) + " " + strings.NewReplacer("/", " ", "{", "by ", "}", "", "-", " ").Replace(ep.Path))
+		}
+		if other, ok := seen[id]; ok {
+			return fmt.Errorf("derived operationId %s is used by both %s and %s %s", id, other, ep.Method, ep.Path)
+		}
+		seen[id] = ep.Method + " " + ep.Path
+		ep.OperationID = id
+	}
+	return nil
+}
+
+// camelCase joins the letters and digits of text into a lower camel case
+// identifier
+func camelCase(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// Endpoints returns every operation sorted by path then method
+func (s *Spec) Endpoints() []Endpoint {
+	var list []Endpoint
+	for path, ops := range s.Paths {
+		for method, op := range ops {
+			list = append(list, Endpoint{Method: method, Path: path, Operation: op})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Path != list[j].Path {
+			return list[i].Path < list[j].Path
+		}
+		return list[i].Method < list[j].Method
+	})
+	return list
+}
+
+// Operation returns the operation with the given operationId
+func (s *Spec) Operation(id string) (*Endpoint, bool) {
+	for path, ops := range s.Paths {
+		for method, op := range ops {
+			if op.OperationID == id {
+				return &Endpoint{Method: method, Path: path, Operation: op}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Expand substitutes path parameter values into the endpoint's path template,
+// escaping each one. It fails when a parameter is missing or not declared.
+func (e *Endpoint) Expand(params map[string]string) (string, error) {
+	parts := strings.Split(e.Path, "/")
+	used := 0
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+			continue
+		}
+		name := part[1 : len(part)-1]
+		value, ok := params[name]
+		if !ok || value == "" {
+			return "", fmt.Errorf("missing path parameter %s", name)
+		}
+		parts[i] = url.PathEscape(value)
+		used++
+	}
+	if used != len(params) {
+		names := make([]string, 0, len(params))
+		for name := range params {
+			if !strings.Contains(e.Path, "{"+name+"}") {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("unexpected path parameters %v for %s", names, e.Path)
+	}
+	return strings.Join(parts, "/"), nil
+}
+
+// Find returns the operation matching a concrete request path, such as
+// /v1/cameras/abc, along with the path parameter values
+func (s *Spec) Find(method, path string) (*Endpoint, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for template, ops := range s.Paths {
+		op, ok := ops[strings.ToUpper(method)]
+		if !ok {
+			continue
+		}
+		if params, ok := matchTemplate(template, segments); ok {
+			return &Endpoint{Method: strings.ToUpper(method), Path: template, Operation: op}, params, true
+		}
+	}
+	return nil, nil, false
+}
+
+func matchTemplate(template string, segments []string) (map[string]string, bool) {
+	parts := strings.Split(strings.Trim(template, "/"), "/")
+	if len(parts) != len(segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[part[1:len(part)-1]] = segments[i]
+			continue
+		}
+		if part != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// Schema returns a named component schema
+func (s *Spec) Schema(name string) (*Schema, bool) {
+	schema, ok := s.Components.Schemas[name]
+	return schema, ok
+}
+
+// Resolve follows $ref pointers to component schemas
+func (s *Spec) Resolve(schema *Schema) (*Schema, error) {
+	for depth := 0; schema != nil && schema.Ref != ""; depth++ {
+		if depth > 32 {
+			return nil, fmt.Errorf("reference loop at %s", schema.Ref)
+		}
+		name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+		if !ok {
+			return nil, fmt.Errorf("unsupported reference %s", schema.Ref)
+		}
+		next, ok := s.Components.Schemas[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown schema %s", name)
+		}
+		schema = next
+	}
+	return schema, nil
+}
@@ -0,0 +1,124 @@
+package protectmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Fault changes how matching requests are answered. Status answers with that
+// HTTP status (401 behaves like a bad API key), Malformed answers 200 with a
+// truncated JSON body and Latency delays the response.
+type Fault struct {
+	// Method and Path restrict the fault to matching requests; Path matches
+	// as a prefix of the path after /v1, e.g. "/v1/cameras"
+	Method    string
+	Path      string
+	Latency   time.Duration
+	Status    int
+	Malformed bool
+	// Count is how many requests the fault affects; zero means until cleared
+	Count int
+}
+
+// InjectFault adds a fault. Faults are matched in the order they were added.
+func (m *Mock) InjectFault(f Fault) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.faults = append(m.faults, f)
+}
+
+// ClearFaults removes all injected faults
+func (m *Mock) ClearFaults() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.faults = nil
+}
+
+// takeFault returns the first fault matching a request, consuming one use of
+// it. The caller must hold m.mu.
+func (m *Mock) takeFault(method, path string) (Fault, bool) {
+	for i, f := range m.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, method) {
+			continue
+		}
+		if f.Path != "" && !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		if f.Count > 0 {
+			m.faults[i].Count--
+			if m.faults[i].Count == 0 {
+				m.faults = append(m.faults[:i], m.faults[i+1:]...)
+			}
+		}
+		return f, true
+	}
+	return Fault{}, false
+}
+
+// controlPrefix serves endpoints for steering a running mock, such as from
+// curl while demoing. They need no API key.
+const controlPrefix = "/_mock/"
+
+// faultRequest is the JSON form of a Fault accepted by the control endpoint
+type faultRequest struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Latency   string `json:"latency"`
+	Status    int    `json:"status"`
+	Malformed bool   `json:"malformed"`
+	Count     int    `json:"count"`
+}
+
+func (m *Mock) serveControl(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimPrefix(r.URL.Path, controlPrefix) {
+	case "faults":
+		switch r.Method {
+		case http.MethodPost:
+			var req faultRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Invalid JSON body: %v", err))
+				return
+			}
+			f := Fault{Method: req.Method, Path: req.Path, Status: req.Status, Malformed: req.Malformed, Count: req.Count}
+			if req.Latency != "" {
+				d, err := time.ParseDuration(req.Latency)
+				if err != nil {
+					writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Invalid latency: %v", err))
+					return
+				}
+				f.Latency = d
+			}
+			m.InjectFault(f)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			m.ClearFaults()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Use POST or DELETE")
+		}
+	case "events":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Use POST")
+			return
+		}
+		var req struct {
+			Type             string   `json:"type"`
+			Device           string   `json:"device"`
+			SmartDetectTypes []string `json:"smartDetectTypes"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Type == "" || req.Device == "" {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", "type and device are required")
+			return
+		}
+		writeJSON(w, http.StatusOK, m.EmitEvent(req.Type, req.Device, req.SmartDetectTypes))
+	case "requests":
+		writeJSON(w, http.StatusOK, m.Requests())
+	case "state":
+		writeJSON(w, http.StatusOK, m.Snapshot())
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Unknown control endpoint")
+	}
+}
@@ -0,0 +1,169 @@
+package protectmock
+
+// DefaultVersion is the Protect application version the mock reports
+const DefaultVersion = "6.2.72"
+
+// IDs of the devices in DefaultState, for tests
+const (
+	DoorbellID = "65a1b2c3d4e5f60718293a01"
+	CameraID   = "65a1b2c3d4e5f60718293a02"
+	SensorID   = "65a1b2c3d4e5f60718293a03"
+	LightID    = "65a1b2c3d4e5f60718293a04"
+	ChimeID    = "65a1b2c3d4e5f60718293a05"
+	ViewerID   = "65a1b2c3d4e5f60718293a06"
+	LiveviewID = "65a1b2c3d4e5f60718293a07"
+	NVRID      = "65a1b2c3d4e5f60718293a00"
+	SpeakerID  = "65a1b2c3d4e5f60718293a08"
+)
+
+// DefaultState returns a small site: a doorbell and a bullet camera, a
+// sensor, a floodlight paired to the bullet, a chime paired to the doorbell,
+// a viewer showing one liveview, and a speaker only known from the devices
+// subscription
+func DefaultState() *State {
+	return &State{
+		Version: DefaultVersion,
+		NVR: map[string]interface{}{
+			"id":       NVRID,
+			"modelKey": "nvr",
+			"name":     "Mock NVR",
+			"doorbellSettings": map[string]interface{}{
+				"defaultMessageText":           "Welcome",
+				"defaultMessageResetTimeoutMs": float64(60000),
+				"customMessages":               []interface{}{"Back in 5 minutes"},
+				"customImages":                 []interface{}{},
+			},
+		},
+		Cameras: []map[string]interface{}{
+			camera(DoorbellID, "Front Door", "AABBCCDDEE01", []interface{}{"person", "vehicle", "package", "face"},
+				[]interface{}{"alrmSmoke", "alrmCmonx", "alrmSpeak"}, []interface{}{"default", "highFps"}, true),
+			camera(CameraID, "Driveway", "AABBCCDDEE02", []interface{}{"person", "vehicle", "animal", "licensePlate"},
+				[]interface{}{"alrmSmoke", "alrmSiren", "alrmBark"}, []interface{}{"default", "highFps", "sport", "slowShutter"}, false),
+		},
+		Sensors: []map[string]interface{}{{
+			"id":                     SensorID,
+			"modelKey":               "sensor",
+			"state":                  "CONNECTED",
+			"name":                   "Garage Door",
+			"mac":                    "AABBCCDDEE03",
+			"mountType":              "garage",
+			"batteryStatus":          map[string]interface{}{"percentage": float64(87), "isLow": false},
+			"stats":                  sensorStats(21.5, 45, 120),
+			"lightSettings":          thresholds(1, 10000, 5),
+			"humiditySettings":       thresholds(20, 80, 2),
+			"temperatureSettings":    thresholds(5, 35, 0.5),
+			"isOpened":               false,
+			"openStatusChangedAt":    float64(1741267544209),
+			"isMotionDetected":       false,
+			"motionDetectedAt":       nil,
+			"motionSettings":         map[string]interface{}{"isEnabled": true, "sensitivity": float64(80)},
+			"alarmTriggeredAt":       nil,
+			"alarmSettings":          map[string]interface{}{"isEnabled": false},
+			"leakDetectedAt":         nil,
+			"externalLeakDetectedAt": nil,
+			"leakSettings":           map[string]interface{}{"isInternalEnabled": false, "isExternalEnabled": false},
+			"tamperingDetectedAt":    nil,
+		}},
+		Lights: []map[string]interface{}{{
+			"id":                  LightID,
+			"modelKey":            "light",
+			"state":               "CONNECTED",
+			"name":                "Driveway Flood",
+			"mac":                 "AABBCCDDEE04",
+			"lightModeSettings":   map[string]interface{}{"mode": "motion", "enableAt": "dark"},
+			"lightDeviceSettings": map[string]interface{}{"isIndicatorEnabled": true, "pirDuration": float64(15000), "pirSensitivity": float64(60), "ledLevel": float64(4)},
+			"isDark":              true,
+			"isLightOn":           false,
+			"isLightForceEnabled": false,
+			"lastMotion":          nil,
+			"isPirMotionDetected": false,
+			"camera":              CameraID,
+		}},
+		Chimes: []map[string]interface{}{{
+			"id":        ChimeID,
+			"modelKey":  "chime",
+			"state":     "CONNECTED",
+			"name":      "Hallway Chime",
+			"mac":       "AABBCCDDEE05",
+			"cameraIds": []interface{}{DoorbellID},
+			"ringSettings": []interface{}{map[string]interface{}{
+				"cameraId": DoorbellID, "repeatTimes": float64(1), "ringtoneId": "default", "volume": float64(80),
+			}},
+		}},
+		Viewers: []map[string]interface{}{{
+			"id":          ViewerID,
+			"modelKey":    "viewer",
+			"state":       "CONNECTED",
+			"name":        "Kitchen Viewport",
+			"mac":         "AABBCCDDEE06",
+			"liveview":    LiveviewID,
+			"streamLimit": float64(4),
+		}},
+		Liveviews: []map[string]interface{}{{
+			"id":        LiveviewID,
+			"modelKey":  "liveview",
+			"name":      "All Cameras",
+			"isDefault": true,
+			"isGlobal":  true,
+			"owner":     "65a1b2c3d4e5f60718293aff",
+			"layout":    float64(2),
+			"slots": []interface{}{
+				map[string]interface{}{"cameras": []interface{}{DoorbellID}, "cycleMode": "time", "cycleInterval": float64(10)},
+				map[string]interface{}{"cameras": []interface{}{CameraID}, "cycleMode": "time", "cycleInterval": float64(10)},
+			},
+		}},
+		Files: []map[string]interface{}{},
+		OtherDevices: []map[string]interface{}{{
+			"id":       SpeakerID,
+			"modelKey": "speaker",
+			"state":    "CONNECTED",
+			"name":     "Patio Speaker",
+			"mac":      "AABBCCDDEE08",
+		}},
+	}
+}
+
+func camera(id, name, mac string, objectTypes, audioTypes, videoModes []interface{}, doorbell bool) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           id,
+		"modelKey":     "camera",
+		"state":        "CONNECTED",
+		"name":         name,
+		"mac":          mac,
+		"isMicEnabled": true,
+		"osdSettings": map[string]interface{}{
+			"isNameEnabled": true, "isDateEnabled": true, "isLogoEnabled": false, "isDebugEnabled": false, "overlayLocation": "topLeft",
+		},
+		"ledSettings":      map[string]interface{}{"isEnabled": true, "welcomeLed": false, "floodLed": false},
+		"lcdMessage":       map[string]interface{}{},
+		"micVolume":        float64(80),
+		"activePatrolSlot": nil,
+		"videoMode":        "default",
+		"hdrType":          "auto",
+		"featureFlags": map[string]interface{}{
+			"supportFullHdSnapshot": true,
+			"hasHdr":                true,
+			"smartDetectTypes":      objectTypes,
+			"smartDetectAudioTypes": audioTypes,
+			"videoModes":            videoModes,
+			"hasMic":                true,
+			"hasLedStatus":          true,
+			"hasSpeaker":            doorbell,
+		},
+		"smartDetectSettings": map[string]interface{}{
+			"objectTypes": []interface{}{"person"},
+			"audioTypes":  []interface{}{},
+		},
+	}
+}
+
+func sensorStats(temperature, humidity, light float64) map[string]interface{} {
+	stat := func(v float64) map[string]interface{} {
+		return map[string]interface{}{"value": v, "status": "neutral"}
+	}
+	return map[string]interface{}{"temperature": stat(temperature), "humidity": stat(humidity), "light": stat(light)}
+}
+
+func thresholds(low, high, margin float64) map[string]interface{} {
+	return map[string]interface{}{"isEnabled": true, "lowThreshold": low, "highThreshold": high, "margin": margin}
+}
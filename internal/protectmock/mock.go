@@ -0,0 +1,224 @@
+// Package protectmock is a fake UniFi Protect console implementing the
+// integration API in docs/protect_integration.json. It keeps device state in
+// memory, applies PATCH bodies with merge semantics, feeds the WebSocket
+// subscriptions and can inject latency and errors, for tests and for
+// developing the MCP server without hardware.
+package protectmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultAPIKey is the API key the mock accepts unless WithAPIKey is used
+const DefaultAPIKey = "mock-api-key"
+
+// Prefixes under which the integration API is served. Consoles expose it
+// behind the Protect proxy; the spec's server URL is the bare prefix.
+var Prefixes = []string{"/proxy/protect/integration", "/integration"}
+
+// Mock is an http.Handler serving the Protect integration API
+type Mock struct {
+	apiKey        string
+	talkbackURL   string
+	talkbackCodec string
+	talkbackRate  int
+	latency       time.Duration
+	errorRate     float64
+
+	mu       sync.Mutex
+	state    *State
+	streams  map[string]map[string]interface{}
+	faults   []Fault
+	requests []Request
+	rng      *rand.Rand
+
+	subs   *hub
+	mux    *http.ServeMux
+	logger *logrus.Entry
+}
+
+// Option configures a Mock
+type Option func(*Mock)
+
+// WithState serves st instead of DefaultState
+func WithState(st *State) Option {
+	return func(m *Mock) {
+		m.state = st
+	}
+}
+
+// WithAPIKey sets the API key clients must send
+func WithAPIKey(key string) Option {
+	return func(m *Mock) {
+		m.apiKey = key
+	}
+}
+
+// WithTalkbackURL sets the RTP URL returned by talkback sessions
+func WithTalkbackURL(url string) Option {
+	return func(m *Mock) {
+		m.talkbackURL = url
+	}
+}
+
+// WithTalkbackCodec sets the codec and sampling rate returned by talkback
+// sessions; the default is opus at 24000 Hz
+func WithTalkbackCodec(codec string, samplingRate int) Option {
+	return func(m *Mock) {
+		m.talkbackCodec = codec
+		m.talkbackRate = samplingRate
+	}
+}
+
+// WithLatency delays every response
+func WithLatency(d time.Duration) Option {
+	return func(m *Mock) {
+		m.latency = d
+	}
+}
+
+// WithErrorRate fails the given fraction of requests with a 503
+func WithErrorRate(rate float64) Option {
+	return func(m *Mock) {
+		m.errorRate = rate
+	}
+}
+
+// New creates a mock console
+func New(opts ...Option) *Mock {
+	m := &Mock{
+		apiKey:        DefaultAPIKey,
+		talkbackURL:   "rtp://127.0.0.1:7004",
+		talkbackCodec: "opus",
+		talkbackRate:  24000,
+		streams:       map[string]map[string]interface{}{},
+		rng:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		subs:          newHub(),
+		logger:        logrus.WithField("component", "ProtectMock"),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.state == nil {
+		m.state = DefaultState()
+	}
+	m.routes()
+	return m
+}
+
+// NewServer starts a mock console on a local test server. Point a
+// ProtectClient at the returned server's URL with DefaultAPIKey.
+func NewServer(opts ...Option) (*Mock, *httptest.Server) {
+	m := New(opts...)
+	return m, httptest.NewServer(m)
+}
+
+// Request is a request the mock received
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   []byte `json:"body,omitempty"`
+}
+
+// Requests returns the requests received so far, oldest first
+func (m *Mock) Requests() []Request {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Request(nil), m.requests...)
+}
+
+// Snapshot returns a copy of the current state
+func (m *Mock) Snapshot() *State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, _ := json.Marshal(m.state)
+	var st State
+	_ = json.Unmarshal(data, &st)
+	return &st
+}
+
+// ServeHTTP implements http.Handler
+func (m *Mock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, controlPrefix) {
+		m.serveControl(w, r)
+		return
+	}
+
+	path := r.URL.Path
+	for _, prefix := range Prefixes {
+		if rest, ok := strings.CutPrefix(path, prefix); ok {
+			path = rest
+			break
+		}
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	m.mu.Lock()
+	m.requests = append(m.requests, Request{Method: r.Method, Path: path, Query: r.URL.RawQuery, Body: body})
+	fault, faulted := m.takeFault(r.Method, path)
+	randomFailure := m.errorRate > 0 && m.rng.Float64() < m.errorRate
+	m.mu.Unlock()
+
+	delay := m.latency
+	if faulted && fault.Latency > 0 {
+		delay = fault.Latency
+	}
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if r.Header.Get("X-API-KEY") != m.apiKey || (faulted && fault.Status == http.StatusUnauthorized) {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Unauthorized")
+		return
+	}
+	if faulted && fault.Status != 0 {
+		writeError(w, fault.Status, "API_ERROR", fmt.Sprintf("Injected fault: %s", http.StatusText(fault.Status)))
+		return
+	}
+	if randomFailure {
+		writeError(w, http.StatusServiceUnavailable, "API_ERROR", "Service temporarily unavailable")
+		return
+	}
+	if faulted && fault.Malformed {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id": "truncated`))
+		return
+	}
+
+	r2 := r.Clone(r.Context())
+	r2.URL.Path = path
+	m.mux.ServeHTTP(w, r2)
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a genericError response
+func writeError(w http.ResponseWriter, status int, name, message string) {
+	writeJSON(w, status, map[string]interface{}{"error": message, "name": name})
+}
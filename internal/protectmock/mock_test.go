@@ -0,0 +1,171 @@
+package protectmock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func do(t *testing.T, method, url string, body interface{}) (*http.Response, map[string]interface{}) {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	}
+	req, _ := http.NewRequest(method, url, reader)
+	req.Header.Set("X-API-KEY", DefaultAPIKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	var result map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	return resp, result
+}
+
+func TestClientReadsFixture(t *testing.T) {
+	_, srv := NewServer()
+	defer srv.Close()
+	client := unifi.NewProtectClient(srv.URL, DefaultAPIKey, false)
+	ctx := context.Background()
+
+	cameras, err := client.GetCameras(ctx)
+	if err != nil || len(cameras) != 2 {
+		t.Fatalf("expected 2 cameras, got %d: %v", len(cameras), err)
+	}
+	camera, err := client.GetCamera(ctx, DoorbellID)
+	if err != nil || camera.Name != "Front Door" || camera.FeatureFlags == nil || !camera.FeatureFlags.HasHdr {
+		t.Fatalf("unexpected camera %+v: %v", camera, err)
+	}
+	nvr, err := client.GetNVR(ctx)
+	if err != nil || nvr["id"] != NVRID {
+		t.Fatalf("unexpected NVR %v: %v", nvr, err)
+	}
+	if _, err := client.GetCameraDetailed(ctx, "missing"); err == nil {
+		t.Error("expected a 404 for an unknown camera")
+	}
+}
+
+func TestPatchMergesAndAnnounces(t *testing.T) {
+	mock, srv := NewServer()
+	defer srv.Close()
+	client := unifi.NewProtectClient(srv.URL, DefaultAPIKey, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := client.SubscribeDevices(ctx)
+	if err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	// The speaker has no list endpoint and is announced on connect
+	select {
+	case msg := <-updates:
+		if msg.Type != "add" || msg.ID() != SpeakerID {
+			t.Fatalf("unexpected first message %+v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no device announcement received")
+	}
+
+	url := srv.URL + "/proxy/protect/integration/v1/lights/" + LightID
+	resp, light := do(t, http.MethodPatch, url, map[string]interface{}{
+		"lightDeviceSettings": map[string]interface{}{"ledLevel": 6},
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("patch failed with %d", resp.StatusCode)
+	}
+	settings := light["lightDeviceSettings"].(map[string]interface{})
+	if settings["ledLevel"] != float64(6) || settings["pirDuration"] != float64(15000) {
+		t.Errorf("expected a merged update, got %v", settings)
+	}
+
+	select {
+	case msg := <-updates:
+		if msg.Type != "update" || msg.ID() != LightID || msg.ModelKey() != "light" {
+			t.Errorf("unexpected update %+v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no device update received")
+	}
+
+	if resp, _ := do(t, http.MethodPatch, url, map[string]interface{}{"id": "other"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected read-only id to be rejected, got %d", resp.StatusCode)
+	}
+	if got := mock.Snapshot().Lights[0]["lightDeviceSettings"].(map[string]interface{})["ledLevel"]; got != float64(6) {
+		t.Errorf("state not updated: %v", got)
+	}
+}
+
+func TestEventsSubscription(t *testing.T) {
+	mock, srv := NewServer()
+	defer srv.Close()
+	client := unifi.NewProtectClient(srv.URL, DefaultAPIKey, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.SubscribeEvents(ctx)
+	if err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	for deadline := time.Now().Add(2 * time.Second); mock.Subscribers(topicEvents) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("subscriber never connected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mock.EmitEvent("smartDetectZone", CameraID, []string{"person"})
+
+	select {
+	case msg := <-events:
+		if msg.Item.Type != "smartDetectZone" || msg.Item.Device != CameraID || len(msg.Item.SmartDetectTypes) != 1 {
+			t.Errorf("unexpected event %+v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+	}
+}
+
+func TestFaultInjection(t *testing.T) {
+	mock, srv := NewServer()
+	defer srv.Close()
+	client := unifi.NewProtectClient(srv.URL, DefaultAPIKey, false)
+	ctx := context.Background()
+
+	mock.InjectFault(Fault{Path: "/v1/cameras", Status: http.StatusInternalServerError, Count: 1})
+	if _, err := client.GetCameras(ctx); err == nil {
+		t.Error("expected an injected 500")
+	}
+	if _, err := client.GetCameras(ctx); err != nil {
+		t.Errorf("expected the fault to be used up: %v", err)
+	}
+
+	mock.InjectFault(Fault{Path: "/v1/sensors", Malformed: true})
+	if _, err := client.GetSensors(ctx); err == nil {
+		t.Error("expected malformed JSON to fail decoding")
+	}
+	mock.ClearFaults()
+
+	mock.InjectFault(Fault{Status: http.StatusUnauthorized, Count: 1})
+	if _, err := client.GetSystemInfo(ctx); err == nil {
+		t.Error("expected an injected 401")
+	}
+
+	mock.InjectFault(Fault{Latency: 50 * time.Millisecond, Count: 1})
+	start := time.Now()
+	if _, err := client.GetLights(ctx); err != nil || time.Since(start) < 50*time.Millisecond {
+		t.Errorf("expected a delayed success, took %s: %v", time.Since(start), err)
+	}
+
+	bad := unifi.NewProtectClient(srv.URL, "wrong", false)
+	if _, err := bad.GetCameras(ctx); err == nil {
+		t.Error("expected a bad API key to be rejected")
+	}
+}
@@ -0,0 +1,396 @@
+package protectmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// assetMIMETypes are the upload types the files endpoint accepts
+var assetMIMETypes = map[string]string{
+	"image/gif": ".gif", "image/jpeg": ".jpg", "image/png": ".png",
+	"audio/mpeg": ".mp3", "audio/mp4": ".m4a", "audio/wave": ".wav", "audio/x-caf": ".caf",
+}
+
+// rtspsQualities are the stream qualities a camera can share
+var rtspsQualities = []string{"high", "medium", "low", "package"}
+
+// readOnlyFields cannot be changed with PATCH
+var readOnlyFields = []string{"id", "modelKey", "state", "mac", "featureFlags"}
+
+func (m *Mock) routes() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("Cannot %s %s", r.Method, r.URL.Path))
+	})
+	mux.HandleFunc("GET /v1/meta/info", m.getInfo)
+	mux.HandleFunc("GET /v1/nvrs", m.getNVR)
+	for _, c := range Collections {
+		collection := c
+		mux.HandleFunc("GET /v1/"+collection, func(w http.ResponseWriter, r *http.Request) { m.list(w, collection) })
+		mux.HandleFunc("GET /v1/"+collection+"/{id}", func(w http.ResponseWriter, r *http.Request) { m.get(w, collection, r.PathValue("id")) })
+		if collection != "liveviews" {
+			mux.HandleFunc("PATCH /v1/"+collection+"/{id}", func(w http.ResponseWriter, r *http.Request) { m.patch(w, r, collection) })
+		}
+	}
+	mux.HandleFunc("POST /v1/liveviews", m.createLiveview)
+	mux.HandleFunc("PATCH /v1/liveviews/{id}", func(w http.ResponseWriter, r *http.Request) { m.patch(w, r, "liveviews") })
+
+	mux.HandleFunc("POST /v1/cameras/{id}/ptz/patrol/start/{slot}", m.ptzPatrolStart)
+	mux.HandleFunc("POST /v1/cameras/{id}/ptz/patrol/stop", m.ptzPatrolStop)
+	mux.HandleFunc("POST /v1/cameras/{id}/ptz/goto/{slot}", m.ptzGoto)
+	mux.HandleFunc("POST /v1/cameras/{id}/rtsps-stream", m.createRTSPSStream)
+	mux.HandleFunc("GET /v1/cameras/{id}/rtsps-stream", m.getRTSPSStream)
+	mux.HandleFunc("DELETE /v1/cameras/{id}/rtsps-stream", m.deleteRTSPSStream)
+	mux.HandleFunc("GET /v1/cameras/{id}/snapshot", m.snapshot)
+	mux.HandleFunc("POST /v1/cameras/{id}/disable-mic-permanently", m.disableMic)
+	mux.HandleFunc("POST /v1/cameras/{id}/talkback-session", m.talkbackSession)
+	mux.HandleFunc("POST /v1/alarm-manager/webhook/{id}", m.alarmWebhook)
+	mux.HandleFunc("GET /v1/files/{fileType}", m.listFiles)
+	mux.HandleFunc("POST /v1/files/{fileType}", m.uploadFile)
+	mux.HandleFunc("GET /v1/subscribe/devices", func(w http.ResponseWriter, r *http.Request) { m.subscribe(w, r, topicDevices) })
+	mux.HandleFunc("GET /v1/subscribe/events", func(w http.ResponseWriter, r *http.Request) { m.subscribe(w, r, topicEvents) })
+	m.mux = mux
+}
+
+func (m *Mock) getInfo(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{"applicationVersion": m.state.Version})
+}
+
+func (m *Mock) getNVR(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	writeJSON(w, http.StatusOK, clone(m.state.NVR))
+}
+
+func (m *Mock) list(w http.ResponseWriter, collection string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	writeJSON(w, http.StatusOK, clone(*m.state.collection(collection)))
+}
+
+func (m *Mock) get(w http.ResponseWriter, collection, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item := m.state.find(collection, id)
+	if item == nil {
+		writeNotFound(w, collection, id)
+		return
+	}
+	writeJSON(w, http.StatusOK, clone(item))
+}
+
+func (m *Mock) patch(w http.ResponseWriter, r *http.Request, collection string) {
+	id := r.PathValue("id")
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Invalid JSON body: %v", err))
+		return
+	}
+	for _, field := range readOnlyFields {
+		if _, ok := body[field]; ok {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Field %s is read-only", field))
+			return
+		}
+	}
+
+	m.mu.Lock()
+	item := m.state.find(collection, id)
+	if item == nil {
+		m.mu.Unlock()
+		writeNotFound(w, collection, id)
+		return
+	}
+	merge(item, clone(body).(map[string]interface{}))
+	result := clone(item)
+	modelKey := item["modelKey"]
+	m.mu.Unlock()
+
+	if collection != "liveviews" {
+		update := clone(body).(map[string]interface{})
+		update["id"] = id
+		update["modelKey"] = modelKey
+		m.EmitDevice("update", update)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (m *Mock) createLiveview(w http.ResponseWriter, r *http.Request) {
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Invalid JSON body: %v", err))
+		return
+	}
+	for _, field := range []string{"name", "layout", "slots"} {
+		if _, ok := body[field]; !ok {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Field %s is required", field))
+			return
+		}
+	}
+	liveview := map[string]interface{}{
+		"id":        newID(),
+		"modelKey":  "liveview",
+		"isDefault": false,
+		"isGlobal":  false,
+		"owner":     "65a1b2c3d4e5f60718293aff",
+	}
+	merge(liveview, body)
+
+	m.mu.Lock()
+	m.state.Liveviews = append(m.state.Liveviews, liveview)
+	result := clone(liveview)
+	m.mu.Unlock()
+	writeJSON(w, http.StatusOK, result)
+}
+
+// cameraLocked returns the stored camera for a request, writing a 404 if missing.
+// The caller must hold m.mu.
+func (m *Mock) cameraLocked(w http.ResponseWriter, r *http.Request) map[string]interface{} {
+	id := r.PathValue("id")
+	cam := m.state.find("cameras", id)
+	if cam == nil {
+		writeNotFound(w, "cameras", id)
+	}
+	return cam
+}
+
+func (m *Mock) ptzPatrolStart(w http.ResponseWriter, r *http.Request) {
+	slot, err := strconv.Atoi(r.PathValue("slot"))
+	if err != nil || slot < 0 || slot > 4 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "Patrol slot must be between 0 and 4")
+		return
+	}
+	m.setCameraField(w, r, "activePatrolSlot", float64(slot))
+}
+
+func (m *Mock) ptzPatrolStop(w http.ResponseWriter, r *http.Request) {
+	m.setCameraField(w, r, "activePatrolSlot", nil)
+}
+
+func (m *Mock) ptzGoto(w http.ResponseWriter, r *http.Request) {
+	if _, err := strconv.Atoi(r.PathValue("slot")); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "Preset slot must be a number")
+		return
+	}
+	m.mu.Lock()
+	cam := m.cameraLocked(w, r)
+	m.mu.Unlock()
+	if cam != nil {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// setCameraField changes one camera field, announces it and answers 204
+func (m *Mock) setCameraField(w http.ResponseWriter, r *http.Request, field string, value interface{}) {
+	m.mu.Lock()
+	cam := m.cameraLocked(w, r)
+	if cam == nil {
+		m.mu.Unlock()
+		return
+	}
+	cam[field] = value
+	m.mu.Unlock()
+	m.EmitDevice("update", map[string]interface{}{"id": r.PathValue("id"), "modelKey": "camera", field: value})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *Mock) createRTSPSStream(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Qualities []string `json:"qualities"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Qualities) == 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "qualities is required")
+		return
+	}
+	for _, q := range body.Qualities {
+		if !slices.Contains(rtspsQualities, q) {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Invalid quality %q", q))
+			return
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cameraLocked(w, r) == nil {
+		return
+	}
+	id := r.PathValue("id")
+	streams := m.streams[id]
+	if streams == nil {
+		streams = map[string]interface{}{}
+		m.streams[id] = streams
+	}
+	created := map[string]interface{}{}
+	for _, q := range body.Qualities {
+		url := fmt.Sprintf("rtsps://127.0.0.1:7441/%s?enableSrtp", newID()[:16])
+		streams[q] = url
+		created[q] = url
+	}
+	writeJSON(w, http.StatusOK, created)
+}
+
+func (m *Mock) getRTSPSStream(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cameraLocked(w, r) == nil {
+		return
+	}
+	existing := map[string]interface{}{}
+	for _, q := range rtspsQualities {
+		existing[q] = m.streams[r.PathValue("id")][q]
+	}
+	writeJSON(w, http.StatusOK, existing)
+}
+
+func (m *Mock) deleteRTSPSStream(w http.ResponseWriter, r *http.Request) {
+	qualities := r.URL.Query()["qualities"]
+	if len(qualities) == 1 {
+		qualities = strings.Split(qualities[0], ",")
+	}
+	if len(qualities) == 0 || qualities[0] == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "qualities is required")
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cameraLocked(w, r) == nil {
+		return
+	}
+	for _, q := range qualities {
+		delete(m.streams[r.PathValue("id")], q)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// snapshotJPEG is a small grey frame served for every snapshot
+var snapshotJPEG = func() []byte {
+	img := image.NewGray(image.Rect(0, 0, 64, 36))
+	for i := range img.Pix {
+		img.Pix[i] = color.Gray{Y: 128}.Y
+	}
+	var buf bytes.Buffer
+	_ = jpeg.Encode(&buf, img, nil)
+	return buf.Bytes()
+}()
+
+func (m *Mock) snapshot(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	cam := m.cameraLocked(w, r)
+	m.mu.Unlock()
+	if cam == nil {
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(snapshotJPEG)
+}
+
+func (m *Mock) disableMic(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	cam := m.cameraLocked(w, r)
+	if cam == nil {
+		m.mu.Unlock()
+		return
+	}
+	cam["isMicEnabled"] = false
+	cam["micVolume"] = float64(0)
+	result := clone(cam)
+	m.mu.Unlock()
+	m.EmitDevice("update", map[string]interface{}{"id": r.PathValue("id"), "modelKey": "camera", "isMicEnabled": false, "micVolume": float64(0)})
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (m *Mock) talkbackSession(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	cam := m.cameraLocked(w, r)
+	m.mu.Unlock()
+	if cam == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"url":           m.talkbackURL,
+		"codec":         m.talkbackCodec,
+		"samplingRate":  m.talkbackRate,
+		"bitsPerSample": 16,
+	})
+}
+
+func (m *Mock) alarmWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("id") == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "id is required")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *Mock) listFiles(w http.ResponseWriter, r *http.Request) {
+	fileType := r.PathValue("fileType")
+	if fileType != "animations" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Invalid file type %q", fileType))
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	writeJSON(w, http.StatusOK, clone(m.state.Files))
+}
+
+func (m *Mock) uploadFile(w http.ResponseWriter, r *http.Request) {
+	fileType := r.PathValue("fileType")
+	if fileType != "animations" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Invalid file type %q", fileType))
+		return
+	}
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Invalid multipart body: %v", err))
+		return
+	}
+	var header *multipartHeader
+	for _, files := range r.MultipartForm.File {
+		if len(files) > 0 {
+			header = &multipartHeader{name: files[0].Filename, contentType: files[0].Header.Get("Content-Type")}
+			break
+		}
+	}
+	if header == nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "A file is required")
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.contentType)
+	ext, ok := assetMIMETypes[mediaType]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Unsupported file type %q", header.contentType))
+		return
+	}
+
+	name := newID() + ext
+	file := map[string]interface{}{
+		"name":         name,
+		"type":         fileType,
+		"originalName": filepath.Base(header.name),
+		"path":         "/data/protect/assets/" + name,
+	}
+	m.mu.Lock()
+	m.state.Files = append(m.state.Files, file)
+	m.mu.Unlock()
+	writeJSON(w, http.StatusOK, file)
+}
+
+type multipartHeader struct {
+	name        string
+	contentType string
+}
+
+func writeNotFound(w http.ResponseWriter, collection, id string) {
+	writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("%s %s not found", strings.TrimSuffix(collection, "s"), id))
+}
@@ -0,0 +1,127 @@
+package protectmock
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Collections served by the list and detail endpoints, in the order the
+// device inventory reports them
+var Collections = []string{"cameras", "sensors", "lights", "chimes", "viewers", "liveviews"}
+
+// State is the console's data. It is also the fixture file format.
+type State struct {
+	Version   string                   `json:"version"`
+	NVR       map[string]interface{}   `json:"nvr"`
+	Cameras   []map[string]interface{} `json:"cameras"`
+	Sensors   []map[string]interface{} `json:"sensors"`
+	Lights    []map[string]interface{} `json:"lights"`
+	Chimes    []map[string]interface{} `json:"chimes"`
+	Viewers   []map[string]interface{} `json:"viewers"`
+	Liveviews []map[string]interface{} `json:"liveviews"`
+	Files     []map[string]interface{} `json:"files"`
+	// OtherDevices have no list endpoint (speakers, bridges and so on) and are
+	// announced to each new devices subscriber instead
+	OtherDevices []map[string]interface{} `json:"otherDevices"`
+}
+
+// LoadState reads a fixture file
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture: %w", err)
+	}
+	if st.NVR == nil {
+		return nil, fmt.Errorf("fixture has no nvr")
+	}
+	if st.Version == "" {
+		st.Version = DefaultVersion
+	}
+	return &st, nil
+}
+
+func (st *State) collection(name string) *[]map[string]interface{} {
+	switch name {
+	case "cameras":
+		return &st.Cameras
+	case "sensors":
+		return &st.Sensors
+	case "lights":
+		return &st.Lights
+	case "chimes":
+		return &st.Chimes
+	case "viewers":
+		return &st.Viewers
+	case "liveviews":
+		return &st.Liveviews
+	}
+	return nil
+}
+
+func (st *State) find(collection, id string) map[string]interface{} {
+	items := st.collection(collection)
+	if items == nil {
+		return nil
+	}
+	for _, item := range *items {
+		if item["id"] == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// merge applies a PATCH body to obj: nested objects are merged field by field
+// and every other value, including arrays and null, replaces the old one
+func merge(obj, patch map[string]interface{}) {
+	for key, value := range patch {
+		if sub, ok := value.(map[string]interface{}); ok {
+			if current, ok := obj[key].(map[string]interface{}); ok {
+				merge(current, sub)
+				continue
+			}
+		}
+		obj[key] = value
+	}
+}
+
+// clone deep copies a JSON value so responses never alias stored state
+func clone(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			out[k] = clone(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = clone(val)
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = clone(val)
+		}
+		return out
+	}
+	return v
+}
+
+// newID returns a 24 character hex ID in the style Protect uses
+func newID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,182 @@
+package protectmock
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	topicDevices = "devices"
+	topicEvents  = "events"
+)
+
+// hub fans subscription messages out to connected WebSocket clients
+type hub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan []byte]struct{}
+}
+
+func newHub() *hub {
+	return &hub{subs: map[string]map[chan []byte]struct{}{topicDevices: {}, topicEvents: {}}}
+}
+
+func (h *hub) add(topic string) chan []byte {
+	ch := make(chan []byte, 64)
+	h.mu.Lock()
+	h.subs[topic][ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *hub) remove(topic string, ch chan []byte) {
+	h.mu.Lock()
+	delete(h.subs[topic], ch)
+	h.mu.Unlock()
+}
+
+// publish delivers msg to every subscriber of topic, dropping it for
+// subscribers too slow to keep up
+func (h *hub) publish(topic string, msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[topic] {
+		select {
+		case ch <- data:
+		default:
+		}
+	}
+}
+
+// Subscribers returns the number of connected clients on a topic
+func (m *Mock) Subscribers(topic string) int {
+	m.subs.mu.Lock()
+	defer m.subs.mu.Unlock()
+	return len(m.subs.subs[topic])
+}
+
+var upgrader = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+
+func (m *Mock) subscribe(w http.ResponseWriter, r *http.Request, topic string) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	ch := m.subs.add(topic)
+	defer m.subs.remove(topic, ch)
+
+	if topic == topicDevices {
+		m.mu.Lock()
+		others := clone(m.state.OtherDevices)
+		m.mu.Unlock()
+		for _, device := range others.([]interface{}) {
+			data, _ := json.Marshal(map[string]interface{}{"type": "add", "item": device})
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		}
+	}
+
+	// Reading detects the client going away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case data := <-ch:
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// EmitDevice sends a message on the devices subscription. msgType is add,
+// update or remove.
+func (m *Mock) EmitDevice(msgType string, item map[string]interface{}) {
+	m.subs.publish(topicDevices, map[string]interface{}{"type": msgType, "item": item})
+}
+
+// EmitEvent sends a finished event of the given type for a device on the
+// events subscription and returns it
+func (m *Mock) EmitEvent(eventType, device string, smartDetectTypes []string) map[string]interface{} {
+	now := time.Now().UnixMilli()
+	event := map[string]interface{}{
+		"id":       newID(),
+		"modelKey": "event",
+		"type":     eventType,
+		"start":    now,
+		"end":      now + 1000,
+		"device":   device,
+	}
+	if len(smartDetectTypes) > 0 {
+		event["smartDetectTypes"] = smartDetectTypes
+	}
+	if eventType == "sensorOpened" || eventType == "sensorClosed" {
+		m.mu.Lock()
+		mountType := "none"
+		if sensor := m.state.find("sensors", device); sensor != nil {
+			if v, ok := sensor["mountType"].(string); ok {
+				mountType = v
+			}
+		}
+		m.mu.Unlock()
+		event["metadata"] = map[string]interface{}{"sensorMountType": map[string]interface{}{"text": mountType}}
+	}
+	m.subs.publish(topicEvents, map[string]interface{}{"type": "add", "item": event})
+	return event
+}
+
+// Run emits a synthetic event every interval until ctx is cancelled, cycling
+// through camera motion, smart detections, doorbell rings and sensor events
+func (m *Mock) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		m.mu.Lock()
+		var cameraID, sensorID string
+		if n := len(m.state.Cameras); n > 0 {
+			cameraID, _ = m.state.Cameras[i%n]["id"].(string)
+		}
+		if len(m.state.Sensors) > 0 {
+			sensorID, _ = m.state.Sensors[0]["id"].(string)
+		}
+		m.mu.Unlock()
+
+		switch {
+		case cameraID != "" && i%4 == 0:
+			m.EmitEvent("motion", cameraID, nil)
+		case cameraID != "" && i%4 == 1:
+			m.EmitEvent("smartDetectZone", cameraID, []string{"person"})
+		case cameraID != "" && i%4 == 2:
+			m.EmitEvent("ring", cameraID, nil)
+		case sensorID != "":
+			m.EmitEvent("sensorOpened", sensorID, nil)
+		}
+	}
+}
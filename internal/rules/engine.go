@@ -0,0 +1,317 @@
+// Package rules runs declarative "when X happens, do Y" automations against
+// Unifi Protect
+package rules
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// Client is the subset of the Protect client used to evaluate and run rules
+type Client interface {
+	GetCameraDetailed(ctx context.Context, cameraID string) (map[string]interface{}, error)
+	GetSensorDetailed(ctx context.Context, sensorID string) (map[string]interface{}, error)
+	GetLightDetailed(ctx context.Context, lightID string) (map[string]interface{}, error)
+	GetChimeDetailed(ctx context.Context, chimeID string) (map[string]interface{}, error)
+	PatchCamera(ctx context.Context, cameraID string, settings map[string]interface{}) (map[string]interface{}, error)
+	SetDoorbellMessage(ctx context.Context, cameraID, messageType, text string, resetAt *time.Time) (map[string]interface{}, error)
+	GetDoorbellDefaultResetTimeout(ctx context.Context) (time.Duration, error)
+	PatchLight(ctx context.Context, lightID string, settings map[string]interface{}) (map[string]interface{}, error)
+	CameraGotoPTZPreset(ctx context.Context, cameraID string, slot int) (map[string]interface{}, error)
+	TriggerWebhookAlarm(ctx context.Context, webhookID string, payload map[string]interface{}) (map[string]interface{}, error)
+}
+
+// Result describes one evaluation of a rule
+type Result struct {
+	RuleID     string            `json:"rule_id"`
+	DryRun     bool              `json:"dry_run"`
+	Triggered  bool              `json:"triggered"`
+	Matched    bool              `json:"matched"`
+	Conditions []ConditionResult `json:"conditions,omitempty"`
+	Actions    []ActionResult    `json:"actions,omitempty"`
+	Time       time.Time         `json:"time"`
+}
+
+// ConditionResult is the outcome of a single condition
+type ConditionResult struct {
+	Description string `json:"description"`
+	Passed      bool   `json:"passed"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ActionResult is the outcome of a single action
+type ActionResult struct {
+	Description string `json:"description"`
+	Executed    bool   `json:"executed"`
+	Error       string `json:"error,omitempty"`
+}
+
+// RuleStatus is a rule together with its most recent run
+type RuleStatus struct {
+	Rule
+	LastRun *Result `json:"last_run,omitempty"`
+}
+
+// Engine holds the rule set and executes it
+type Engine struct {
+	client  Client
+	path    string
+	mu      sync.Mutex
+	rules   []Rule
+	lastRun map[string]*Result
+	cron    *cron.Cron
+	entries map[string]cron.EntryID
+	ctx     context.Context
+	logger  *logrus.Entry
+}
+
+// NewEngine loads the rules stored at path
+func NewEngine(client Client, path string) (*Engine, error) {
+	f, err := loadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Engine{
+		client:  client,
+		path:    path,
+		rules:   f.Rules,
+		lastRun: map[string]*Result{},
+		cron:    cron.New(),
+		entries: map[string]cron.EntryID{},
+		ctx:     context.Background(),
+		logger:  logrus.WithField("component", "RulesEngine"),
+	}
+	for _, r := range e.rules {
+		e.scheduleLocked(r)
+	}
+	return e, nil
+}
+
+// Run evaluates event-triggered rules and runs scheduled ones until ctx is
+// cancelled
+func (e *Engine) Run(ctx context.Context, events <-chan unifi.ProtectEventMessage) {
+	e.mu.Lock()
+	e.ctx = ctx
+	e.mu.Unlock()
+
+	e.logger.WithField("rules", len(e.Rules())).Info("Starting rules engine")
+	e.cron.Start()
+	defer e.cron.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-events:
+			if !ok {
+				return
+			}
+			e.handleEvent(ctx, msg)
+		}
+	}
+}
+
+// Rules returns all rules with their last run
+func (e *Engine) Rules() []RuleStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	statuses := make([]RuleStatus, 0, len(e.rules))
+	for _, r := range e.rules {
+		statuses = append(statuses, RuleStatus{Rule: r, LastRun: e.lastRun[r.ID]})
+	}
+	return statuses
+}
+
+// Create adds a new rule and persists the rule set
+func (e *Engine) Create(rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, r := range e.rules {
+		if r.ID == rule.ID {
+			return fmt.Errorf("rule %s already exists", rule.ID)
+		}
+	}
+
+	rules := append(append([]Rule{}, e.rules...), rule)
+	if err := saveFile(e.path, File{Rules: rules}); err != nil {
+		return err
+	}
+	e.rules = rules
+	e.scheduleLocked(rule)
+	return nil
+}
+
+// SetEnabled enables or disables a rule and persists the change
+func (e *Engine) SetEnabled(id string, enabled bool) (Rule, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i := range e.rules {
+		if e.rules[i].ID != id {
+			continue
+		}
+		rules := append([]Rule{}, e.rules...)
+		rules[i].Enabled = enabled
+		if err := saveFile(e.path, File{Rules: rules}); err != nil {
+			return Rule{}, err
+		}
+		e.rules = rules
+		return rules[i], nil
+	}
+	return Rule{}, fmt.Errorf("rule %s not found", id)
+}
+
+// DryRun evaluates a rule against live device state, optionally with a sample
+// event, and reports which actions would run without executing them
+func (e *Engine) DryRun(ctx context.Context, id string, event *unifi.ProtectEventMessage) (*Result, error) {
+	rule, ok := e.rule(id)
+	if !ok {
+		return nil, fmt.Errorf("rule %s not found", id)
+	}
+	return e.evaluate(ctx, rule, event, true), nil
+}
+
+func (e *Engine) rule(id string) (Rule, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, r := range e.rules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (e *Engine) scheduleLocked(rule Rule) {
+	if rule.Trigger.Schedule == "" {
+		return
+	}
+	id := rule.ID
+	entry, err := e.cron.AddFunc(rule.Trigger.Schedule, func() {
+		e.mu.Lock()
+		ctx := e.ctx
+		e.mu.Unlock()
+
+		if r, ok := e.rule(id); ok && r.Enabled {
+			e.fire(ctx, r, nil)
+		}
+	})
+	if err != nil {
+		// Schedules are validated before rules are accepted
+		e.logger.WithError(err).WithField("rule_id", id).Error("Failed to schedule rule")
+		return
+	}
+	e.entries[id] = entry
+}
+
+func (e *Engine) handleEvent(ctx context.Context, msg unifi.ProtectEventMessage) {
+	if msg.Type != "add" {
+		return
+	}
+	for _, status := range e.Rules() {
+		r := status.Rule
+		if !r.Enabled || r.Trigger.Event == nil || !r.Trigger.Event.matches(msg) {
+			continue
+		}
+		e.fire(ctx, r, &msg)
+	}
+}
+
+func (e *Engine) fire(ctx context.Context, rule Rule, event *unifi.ProtectEventMessage) {
+	if rule.CooldownSeconds > 0 {
+		e.mu.Lock()
+		last := e.lastRun[rule.ID]
+		e.mu.Unlock()
+		if last != nil && last.Matched && time.Since(last.Time) < time.Duration(rule.CooldownSeconds)*time.Second {
+			return
+		}
+	}
+
+	result := e.evaluate(ctx, rule, event, false)
+
+	e.mu.Lock()
+	e.lastRun[rule.ID] = result
+	e.mu.Unlock()
+
+	logger := e.logger.WithField("rule_id", rule.ID)
+	for _, a := range result.Actions {
+		if a.Error != "" {
+			logger.WithField("action", a.Description).Warn("Rule action failed: " + a.Error)
+		}
+	}
+	if result.Matched {
+		logger.Info("Rule fired")
+	}
+}
+
+// evaluate checks the trigger and conditions and, unless dryRun, runs the actions
+func (e *Engine) evaluate(ctx context.Context, rule Rule, event *unifi.ProtectEventMessage, dryRun bool) *Result {
+	result := &Result{
+		RuleID:    rule.ID,
+		DryRun:    dryRun,
+		Triggered: true,
+		Matched:   true,
+		Time:      time.Now(),
+	}
+
+	if event != nil && rule.Trigger.Event != nil {
+		result.Triggered = rule.Trigger.Event.matches(*event)
+	}
+
+	for _, c := range rule.Conditions {
+		cr := e.checkCondition(ctx, c, result.Time)
+		result.Conditions = append(result.Conditions, cr)
+		if !cr.Passed {
+			result.Matched = false
+		}
+	}
+	if !result.Triggered {
+		result.Matched = false
+	}
+
+	for _, a := range rule.Actions {
+		ar := ActionResult{Description: a.describe()}
+		if result.Matched && !dryRun {
+			if err := e.runAction(ctx, a, result.Time); err != nil {
+				ar.Error = err.Error()
+			} else {
+				ar.Executed = true
+			}
+		}
+		result.Actions = append(result.Actions, ar)
+	}
+	return result
+}
+
+func (t EventTrigger) matches(msg unifi.ProtectEventMessage) bool {
+	if len(t.Types) > 0 && !slices.Contains(t.Types, msg.Item.Type) {
+		return false
+	}
+	if len(t.Devices) > 0 && !slices.Contains(t.Devices, msg.Item.Device) {
+		return false
+	}
+	if len(t.SmartDetectTypes) > 0 {
+		for _, st := range msg.Item.SmartDetectTypes {
+			if slices.Contains(t.SmartDetectTypes, st) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,171 @@
+package rules
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+type fakeClient struct {
+	lights  map[string]map[string]interface{}
+	patched []string
+	resetAt *time.Time
+}
+
+func (f *fakeClient) GetCameraDetailed(ctx context.Context, id string) (map[string]interface{}, error) {
+	return map[string]interface{}{"state": "CONNECTED"}, nil
+}
+
+func (f *fakeClient) GetSensorDetailed(ctx context.Context, id string) (map[string]interface{}, error) {
+	return map[string]interface{}{"isOpened": false}, nil
+}
+
+func (f *fakeClient) GetLightDetailed(ctx context.Context, id string) (map[string]interface{}, error) {
+	return f.lights[id], nil
+}
+
+func (f *fakeClient) GetChimeDetailed(ctx context.Context, id string) (map[string]interface{}, error) {
+	return map[string]interface{}{"state": "CONNECTED"}, nil
+}
+
+func (f *fakeClient) PatchCamera(ctx context.Context, id string, settings map[string]interface{}) (map[string]interface{}, error) {
+	f.patched = append(f.patched, "camera:"+id)
+	return settings, nil
+}
+
+func (f *fakeClient) SetDoorbellMessage(ctx context.Context, id, messageType, text string, resetAt *time.Time) (map[string]interface{}, error) {
+	f.patched = append(f.patched, "doorbell:"+id)
+	f.resetAt = resetAt
+	return map[string]interface{}{}, nil
+}
+
+func (f *fakeClient) GetDoorbellDefaultResetTimeout(ctx context.Context) (time.Duration, error) {
+	return 10 * time.Minute, nil
+}
+
+func (f *fakeClient) PatchLight(ctx context.Context, id string, settings map[string]interface{}) (map[string]interface{}, error) {
+	f.patched = append(f.patched, "light:"+id)
+	return settings, nil
+}
+
+func (f *fakeClient) CameraGotoPTZPreset(ctx context.Context, id string, slot int) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func (f *fakeClient) TriggerWebhookAlarm(ctx context.Context, id string, payload map[string]interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func drivewayRule() Rule {
+	return Rule{
+		ID:      "driveway",
+		Enabled: true,
+		Trigger: Trigger{Event: &EventTrigger{Types: []string{"smartDetectZone"}, SmartDetectTypes: []string{"person"}}},
+		Conditions: []Condition{
+			{IsDark: &LightIsDark{Light: "light1", Value: true}},
+		},
+		Actions: []Action{
+			{Light: &LightAction{Light: "light1", Settings: map[string]interface{}{"isLightForceEnabled": true}}},
+		},
+	}
+}
+
+func TestEngineFiresOnMatchingEvent(t *testing.T) {
+	client := &fakeClient{lights: map[string]map[string]interface{}{"light1": {"isDark": true}}}
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+
+	e, err := NewEngine(client, path)
+	if err != nil {
+		t.Fatalf("NewEngine failed: %v", err)
+	}
+	if err := e.Create(drivewayRule()); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	ctx := context.Background()
+	e.handleEvent(ctx, unifi.ProtectEventMessage{Type: "add", Item: unifi.ProtectEventItem{Type: "smartDetectZone", SmartDetectTypes: []string{"vehicle"}}})
+	if len(client.patched) != 0 {
+		t.Fatalf("rule fired for non-matching event: %v", client.patched)
+	}
+
+	e.handleEvent(ctx, unifi.ProtectEventMessage{Type: "add", Item: unifi.ProtectEventItem{Type: "smartDetectZone", SmartDetectTypes: []string{"person"}}})
+	if len(client.patched) != 1 || client.patched[0] != "light:light1" {
+		t.Fatalf("expected light1 to be patched, got %v", client.patched)
+	}
+
+	// Rules are persisted and disabled rules do not fire
+	e, err = NewEngine(client, path)
+	if err != nil {
+		t.Fatalf("reloading rules failed: %v", err)
+	}
+	if _, err := e.SetEnabled("driveway", false); err != nil {
+		t.Fatalf("SetEnabled failed: %v", err)
+	}
+	e.handleEvent(ctx, unifi.ProtectEventMessage{Type: "add", Item: unifi.ProtectEventItem{Type: "smartDetectZone", SmartDetectTypes: []string{"person"}}})
+	if len(client.patched) != 1 {
+		t.Fatalf("disabled rule fired: %v", client.patched)
+	}
+}
+
+func TestDryRunDoesNotExecute(t *testing.T) {
+	client := &fakeClient{lights: map[string]map[string]interface{}{"light1": {"isDark": false}}}
+	e, err := NewEngine(client, filepath.Join(t.TempDir(), "rules.yaml"))
+	if err != nil {
+		t.Fatalf("NewEngine failed: %v", err)
+	}
+	if err := e.Create(drivewayRule()); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	result, err := e.DryRun(context.Background(), "driveway", nil)
+	if err != nil {
+		t.Fatalf("DryRun failed: %v", err)
+	}
+	if result.Matched || len(result.Conditions) != 1 || result.Conditions[0].Passed {
+		t.Errorf("expected isDark condition to fail, got %+v", result)
+	}
+	if len(client.patched) != 0 {
+		t.Errorf("dry run executed actions: %v", client.patched)
+	}
+}
+
+func TestTimeWindowWrapsMidnight(t *testing.T) {
+	w := TimeWindow{After: "22:00", Before: "06:00"}
+	for _, tt := range []struct {
+		clock string
+		want  bool
+	}{{"23:30", true}, {"05:59", true}, {"06:00", false}, {"12:00", false}} {
+		now, _ := time.Parse("15:04", tt.clock)
+		if got, _ := w.contains(now); got != tt.want {
+			t.Errorf("contains(%s) = %v, want %v", tt.clock, got, tt.want)
+		}
+	}
+}
+
+func TestDoorbellActionUsesNVRDefault(t *testing.T) {
+	client := &fakeClient{}
+	e, err := NewEngine(client, filepath.Join(t.TempDir(), "rules.yaml"))
+	if err != nil {
+		t.Fatalf("NewEngine failed: %v", err)
+	}
+
+	now := time.Now()
+	action := Action{DoorbellMessage: &DoorbellMessageAction{Camera: "door", Type: unifi.LCDMessageDoNotDisturb}}
+	if err := e.runAction(context.Background(), action, now); err != nil {
+		t.Fatalf("runAction failed: %v", err)
+	}
+	if client.resetAt == nil || !client.resetAt.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("expected the NVR default timeout, got %v", client.resetAt)
+	}
+
+	action.DoorbellMessage.DurationMinutes = 5
+	if err := e.runAction(context.Background(), action, now); err != nil {
+		t.Fatalf("runAction failed: %v", err)
+	}
+	if client.resetAt == nil || !client.resetAt.Equal(now.Add(5*time.Minute)) {
+		t.Errorf("expected a five minute message, got %v", client.resetAt)
+	}
+}
@@ -0,0 +1,135 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func (e *Engine) checkCondition(ctx context.Context, c Condition, now time.Time) ConditionResult {
+	switch {
+	case c.TimeWindow != nil:
+		cr := ConditionResult{Description: fmt.Sprintf("time between %s and %s", c.TimeWindow.After, c.TimeWindow.Before)}
+		passed, err := c.TimeWindow.contains(now)
+		if err != nil {
+			cr.Error = err.Error()
+		}
+		cr.Passed = passed
+		return cr
+
+	case c.IsDark != nil:
+		cr := ConditionResult{Description: fmt.Sprintf("light %s isDark is %t", c.IsDark.Light, c.IsDark.Value)}
+		light, err := e.client.GetLightDetailed(ctx, c.IsDark.Light)
+		if err != nil {
+			cr.Error = err.Error()
+			return cr
+		}
+		value, ok := light["isDark"].(bool)
+		if !ok {
+			cr.Error = "light did not report isDark"
+			return cr
+		}
+		cr.Passed = value == c.IsDark.Value
+		return cr
+
+	case c.SensorOpened != nil:
+		cr := ConditionResult{Description: fmt.Sprintf("sensor %s isOpened is %t", c.SensorOpened.Sensor, c.SensorOpened.Value)}
+		sensor, err := e.client.GetSensorDetailed(ctx, c.SensorOpened.Sensor)
+		if err != nil {
+			cr.Error = err.Error()
+			return cr
+		}
+		value, ok := sensor["isOpened"].(bool)
+		if !ok {
+			cr.Error = "sensor did not report isOpened"
+			return cr
+		}
+		cr.Passed = value == c.SensorOpened.Value
+		return cr
+
+	case c.DeviceState != nil:
+		ds := c.DeviceState
+		cr := ConditionResult{Description: fmt.Sprintf("%s %s state is %s", ds.DeviceType, ds.Device, ds.State)}
+		var device map[string]interface{}
+		var err error
+		switch ds.DeviceType {
+		case "camera":
+			device, err = e.client.GetCameraDetailed(ctx, ds.Device)
+		case "light":
+			device, err = e.client.GetLightDetailed(ctx, ds.Device)
+		case "sensor":
+			device, err = e.client.GetSensorDetailed(ctx, ds.Device)
+		case "chime":
+			device, err = e.client.GetChimeDetailed(ctx, ds.Device)
+		}
+		if err != nil {
+			cr.Error = err.Error()
+			return cr
+		}
+		state, _ := device["state"].(string)
+		cr.Passed = state == ds.State
+		return cr
+	}
+
+	return ConditionResult{Description: "unknown condition", Error: "no condition type set"}
+}
+
+// contains reports whether now falls inside the window
+func (w TimeWindow) contains(now time.Time) (bool, error) {
+	after, err := parseClock(w.After)
+	if err != nil {
+		return false, err
+	}
+	before, err := parseClock(w.Before)
+	if err != nil {
+		return false, err
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	if after <= before {
+		return minute >= after && minute < before, nil
+	}
+	// Window wraps midnight, e.g. 22:00-06:00
+	return minute >= after || minute < before, nil
+}
+
+func (e *Engine) runAction(ctx context.Context, a Action, now time.Time) error {
+	var err error
+	switch {
+	case a.Light != nil:
+		_, err = e.client.PatchLight(ctx, a.Light.Light, a.Light.Settings)
+	case a.PTZPreset != nil:
+		_, err = e.client.CameraGotoPTZPreset(ctx, a.PTZPreset.Camera, a.PTZPreset.Slot)
+	case a.Alarm != nil:
+		_, err = e.client.TriggerWebhookAlarm(ctx, a.Alarm.WebhookID, map[string]interface{}{})
+	case a.DoorbellMessage != nil:
+		dm := a.DoorbellMessage
+		// Without a duration the message follows the NVR default timeout
+		duration := time.Duration(dm.DurationMinutes) * time.Minute
+		if duration == 0 {
+			if duration, err = e.client.GetDoorbellDefaultResetTimeout(ctx); err != nil {
+				return err
+			}
+		}
+		_, err = e.client.SetDoorbellMessage(ctx, dm.Camera, dm.Type, dm.Text, unifi.DoorbellResetAt(now, duration))
+	default:
+		err = fmt.Errorf("no action type set")
+	}
+	return err
+}
+
+func (a Action) describe() string {
+	switch {
+	case a.Light != nil:
+		return fmt.Sprintf("patch light %s with %v", a.Light.Light, a.Light.Settings)
+	case a.PTZPreset != nil:
+		return fmt.Sprintf("move camera %s to PTZ preset %d", a.PTZPreset.Camera, a.PTZPreset.Slot)
+	case a.Alarm != nil:
+		return fmt.Sprintf("trigger alarm webhook %s", a.Alarm.WebhookID)
+	case a.DoorbellMessage != nil:
+		return fmt.Sprintf("set doorbell %s message to %s %q", a.DoorbellMessage.Camera, a.DoorbellMessage.Type, a.DoorbellMessage.Text)
+	}
+	return "unknown action"
+}
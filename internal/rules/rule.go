@@ -0,0 +1,267 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/store"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+	"gopkg.in/yaml.v3"
+)
+
+var ruleIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// File is the on-disk YAML document holding all rules
+type File struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// Rule runs Actions when Trigger fires and every Condition holds
+type Rule struct {
+	ID              string      `yaml:"id" json:"id"`
+	Name            string      `yaml:"name,omitempty" json:"name,omitempty"`
+	Enabled         bool        `yaml:"enabled" json:"enabled"`
+	CooldownSeconds int         `yaml:"cooldown_seconds,omitempty" json:"cooldown_seconds,omitempty"`
+	Trigger         Trigger     `yaml:"trigger" json:"trigger"`
+	Conditions      []Condition `yaml:"conditions,omitempty" json:"conditions,omitempty"`
+	Actions         []Action    `yaml:"actions" json:"actions"`
+}
+
+// Trigger fires a rule on matching events or on a cron schedule. Exactly one
+// of Event or Schedule must be set.
+type Trigger struct {
+	Event    *EventTrigger `yaml:"event,omitempty" json:"event,omitempty"`
+	Schedule string        `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+}
+
+// EventTrigger matches events from the events subscription. Empty lists match
+// everything.
+type EventTrigger struct {
+	Types            []string `yaml:"types,omitempty" json:"types,omitempty"`
+	Devices          []string `yaml:"devices,omitempty" json:"devices,omitempty"`
+	SmartDetectTypes []string `yaml:"smart_detect_types,omitempty" json:"smart_detect_types,omitempty"`
+}
+
+// Condition is a single check; exactly one field must be set
+type Condition struct {
+	TimeWindow   *TimeWindow           `yaml:"time_window,omitempty" json:"time_window,omitempty"`
+	IsDark       *LightIsDark          `yaml:"is_dark,omitempty" json:"is_dark,omitempty"`
+	SensorOpened *SensorOpened         `yaml:"sensor_opened,omitempty" json:"sensor_opened,omitempty"`
+	DeviceState  *DeviceStateCondition `yaml:"device_state,omitempty" json:"device_state,omitempty"`
+}
+
+// TimeWindow holds between After and Before (local "HH:MM"), wrapping midnight
+// when After is later than Before
+type TimeWindow struct {
+	After  string `yaml:"after" json:"after"`
+	Before string `yaml:"before" json:"before"`
+}
+
+// LightIsDark checks a light's isDark flag
+type LightIsDark struct {
+	Light string `yaml:"light" json:"light"`
+	Value bool   `yaml:"value" json:"value"`
+}
+
+// SensorOpened checks a sensor's isOpened flag
+type SensorOpened struct {
+	Sensor string `yaml:"sensor" json:"sensor"`
+	Value  bool   `yaml:"value" json:"value"`
+}
+
+// DeviceStateCondition checks a device's connection state
+type DeviceStateCondition struct {
+	DeviceType string `yaml:"device_type" json:"device_type"`
+	Device     string `yaml:"device" json:"device"`
+	State      string `yaml:"state" json:"state"`
+}
+
+// Action is a single step; exactly one field must be set
+type Action struct {
+	Light           *LightAction           `yaml:"light,omitempty" json:"light,omitempty"`
+	PTZPreset       *PTZPresetAction       `yaml:"ptz_preset,omitempty" json:"ptz_preset,omitempty"`
+	Alarm           *AlarmAction           `yaml:"alarm,omitempty" json:"alarm,omitempty"`
+	DoorbellMessage *DoorbellMessageAction `yaml:"doorbell_message,omitempty" json:"doorbell_message,omitempty"`
+}
+
+// LightAction patches light settings
+type LightAction struct {
+	Light    string                 `yaml:"light" json:"light"`
+	Settings map[string]interface{} `yaml:"settings" json:"settings"`
+}
+
+// PTZPresetAction moves a PTZ camera to a preset
+type PTZPresetAction struct {
+	Camera string `yaml:"camera" json:"camera"`
+	Slot   int    `yaml:"slot" json:"slot"`
+}
+
+// AlarmAction triggers an alarm manager webhook
+type AlarmAction struct {
+	WebhookID string `yaml:"webhook_id" json:"webhook_id"`
+}
+
+// DoorbellMessageAction sets the LCD message on a doorbell camera
+type DoorbellMessageAction struct {
+	Camera string `yaml:"camera" json:"camera"`
+	Type   string `yaml:"type" json:"type"`
+	Text   string `yaml:"text,omitempty" json:"text,omitempty"`
+	// DurationMinutes is how long the message shows; zero uses the NVR default
+	DurationMinutes int `yaml:"duration_minutes,omitempty" json:"duration_minutes,omitempty"`
+}
+
+// Validate checks that a rule is complete and well formed
+func (r Rule) Validate() error {
+	if !ruleIDPattern.MatchString(r.ID) {
+		return fmt.Errorf("rule id %q must contain only letters, digits, '-' and '_'", r.ID)
+	}
+	if (r.Trigger.Event == nil) == (r.Trigger.Schedule == "") {
+		return fmt.Errorf("rule %s: trigger must set exactly one of event or schedule", r.ID)
+	}
+	if r.Trigger.Schedule != "" {
+		if _, err := cron.ParseStandard(r.Trigger.Schedule); err != nil {
+			return fmt.Errorf("rule %s: invalid schedule: %w", r.ID, err)
+		}
+	}
+	if r.CooldownSeconds < 0 {
+		return fmt.Errorf("rule %s: cooldown_seconds must not be negative", r.ID)
+	}
+	for i, c := range r.Conditions {
+		if err := c.validate(); err != nil {
+			return fmt.Errorf("rule %s: condition %d: %w", r.ID, i, err)
+		}
+	}
+	if len(r.Actions) == 0 {
+		return fmt.Errorf("rule %s: at least one action is required", r.ID)
+	}
+	for i, a := range r.Actions {
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("rule %s: action %d: %w", r.ID, i, err)
+		}
+	}
+	return nil
+}
+
+func (c Condition) validate() error {
+	set := 0
+	if c.TimeWindow != nil {
+		set++
+		if _, err := parseClock(c.TimeWindow.After); err != nil {
+			return err
+		}
+		if _, err := parseClock(c.TimeWindow.Before); err != nil {
+			return err
+		}
+	}
+	if c.IsDark != nil {
+		set++
+		if c.IsDark.Light == "" {
+			return errors.New("is_dark requires light")
+		}
+	}
+	if c.SensorOpened != nil {
+		set++
+		if c.SensorOpened.Sensor == "" {
+			return errors.New("sensor_opened requires sensor")
+		}
+	}
+	if c.DeviceState != nil {
+		set++
+		switch c.DeviceState.DeviceType {
+		case "camera", "light", "sensor", "chime":
+		default:
+			return fmt.Errorf("device_state device_type must be camera, light, sensor or chime")
+		}
+		if c.DeviceState.Device == "" || c.DeviceState.State == "" {
+			return errors.New("device_state requires device and state")
+		}
+	}
+	if set != 1 {
+		return errors.New("exactly one condition type must be set")
+	}
+	return nil
+}
+
+func (a Action) validate() error {
+	set := 0
+	if a.Light != nil {
+		set++
+		if a.Light.Light == "" || len(a.Light.Settings) == 0 {
+			return errors.New("light requires light and settings")
+		}
+	}
+	if a.PTZPreset != nil {
+		set++
+		if a.PTZPreset.Camera == "" || a.PTZPreset.Slot < 0 {
+			return errors.New("ptz_preset requires camera and a non-negative slot")
+		}
+	}
+	if a.Alarm != nil {
+		set++
+		if a.Alarm.WebhookID == "" {
+			return errors.New("alarm requires webhook_id")
+		}
+	}
+	if a.DoorbellMessage != nil {
+		set++
+		if a.DoorbellMessage.Camera == "" {
+			return errors.New("doorbell_message requires camera")
+		}
+		if err := unifi.ValidateLCDMessage(a.DoorbellMessage.Type, a.DoorbellMessage.Text); err != nil {
+			return fmt.Errorf("doorbell_message: %w", err)
+		}
+	}
+	if set != 1 {
+		return errors.New("exactly one action type must be set")
+	}
+	return nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// loadFile reads the rules file; a missing file yields no rules
+func loadFile(path string) (File, error) {
+	var f File
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return f, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("failed to parse rules file: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for _, r := range f.Rules {
+		if err := r.Validate(); err != nil {
+			return f, err
+		}
+		if seen[r.ID] {
+			return f, fmt.Errorf("duplicate rule id %s", r.ID)
+		}
+		seen[r.ID] = true
+	}
+	return f, nil
+}
+
+// saveFile writes the rules file, replacing it atomically
+func saveFile(path string, f File) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode rules: %w", err)
+	}
+	return store.WriteFile(path, data)
+}
@@ -0,0 +1,305 @@
+// Package scenes captures and restores named multi-device settings snapshots
+package scenes
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/store"
+)
+
+// capturedFields lists the settable fields captured for each device type
+var capturedFields = map[string][]string{
+	"camera": {"osdSettings", "ledSettings", "smartDetectSettings", "micVolume"},
+	"light":  {"lightModeSettings", "lightDeviceSettings"},
+	"chime":  {"ringSettings"},
+}
+
+// Device result statuses reported by Apply
+const (
+	StatusUnchanged      = "unchanged"
+	StatusApplied        = "applied"
+	StatusWouldApply     = "would_apply"
+	StatusFailed         = "failed"
+	StatusSkipped        = "skipped"
+	StatusRolledBack     = "rolled_back"
+	StatusRollbackFailed = "rollback_failed"
+	StatusNotRolledBack  = "not_rolled_back"
+)
+
+// Client is the subset of the Protect client used to capture and apply scenes
+type Client interface {
+	GetCameraDetailed(ctx context.Context, cameraID string) (map[string]interface{}, error)
+	GetLightDetailed(ctx context.Context, lightID string) (map[string]interface{}, error)
+	GetChimeDetailed(ctx context.Context, chimeID string) (map[string]interface{}, error)
+	PatchCamera(ctx context.Context, cameraID string, settings map[string]interface{}) (map[string]interface{}, error)
+	PatchLight(ctx context.Context, lightID string, settings map[string]interface{}) (map[string]interface{}, error)
+	PatchChime(ctx context.Context, chimeID string, settings map[string]interface{}) (map[string]interface{}, error)
+}
+
+// Scene is a named snapshot of device settings
+type Scene struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Devices     []DeviceState `json:"devices"`
+}
+
+// DeviceState is the captured settings of one device
+type DeviceState struct {
+	Type     string                 `json:"type"`
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+// Selection lists the devices to capture, by ID
+type Selection struct {
+	Cameras []string `json:"cameras,omitempty"`
+	Lights  []string `json:"lights,omitempty"`
+	Chimes  []string `json:"chimes,omitempty"`
+}
+
+// ApplyResult reports the outcome of applying a scene
+type ApplyResult struct {
+	Scene      string         `json:"scene"`
+	DryRun     bool           `json:"dry_run"`
+	Success    bool           `json:"success"`
+	RolledBack bool           `json:"rolled_back"`
+	Devices    []DeviceResult `json:"devices"`
+}
+
+// DeviceResult reports the outcome for one device
+type DeviceResult struct {
+	Type    string                 `json:"type"`
+	ID      string                 `json:"id"`
+	Name    string                 `json:"name,omitempty"`
+	Status  string                 `json:"status"`
+	Changes map[string]interface{} `json:"changes,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// Manager stores scenes and applies them
+type Manager struct {
+	client Client
+	path   string
+	mu     sync.Mutex
+	scenes map[string]Scene
+	logger *logrus.Entry
+}
+
+// NewManager loads the scenes stored under dataDir
+func NewManager(client Client, dataDir string) (*Manager, error) {
+	m := &Manager{
+		client: client,
+		path:   filepath.Join(dataDir, "scenes", "scenes.json"),
+		scenes: map[string]Scene{},
+		logger: logrus.WithField("component", "Scenes"),
+	}
+	if err := store.Load(m.path, &m.scenes); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Capture reads the current settings of the selected devices and saves them
+// under name, replacing any existing scene with that name
+func (m *Manager) Capture(ctx context.Context, name, description string, sel Selection) (Scene, error) {
+	if name == "" {
+		return Scene{}, fmt.Errorf("scene name is required")
+	}
+	if len(sel.Cameras)+len(sel.Lights)+len(sel.Chimes) == 0 {
+		return Scene{}, fmt.Errorf("at least one device is required")
+	}
+
+	scene := Scene{Name: name, Description: description, CreatedAt: time.Now()}
+	for _, group := range []struct {
+		deviceType string
+		ids        []string
+	}{{"camera", sel.Cameras}, {"light", sel.Lights}, {"chime", sel.Chimes}} {
+		for _, id := range group.ids {
+			device, err := m.get(ctx, group.deviceType, id)
+			if err != nil {
+				return Scene{}, fmt.Errorf("failed to read %s %s: %w", group.deviceType, id, err)
+			}
+			state := DeviceState{Type: group.deviceType, ID: id, Settings: map[string]interface{}{}}
+			state.Name, _ = device["name"].(string)
+			for _, field := range capturedFields[group.deviceType] {
+				if value, ok := device[field]; ok {
+					state.Settings[field] = value
+				}
+			}
+			scene.Devices = append(scene.Devices, state)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.scenes[name] = scene
+	if err := store.Save(m.path, m.scenes); err != nil {
+		return Scene{}, err
+	}
+	return scene, nil
+}
+
+// List returns all scenes sorted by name
+func (m *Manager) List() []Scene {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]Scene, 0, len(m.scenes))
+	for _, scene := range m.scenes {
+		list = append(list, scene)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Delete removes a scene
+func (m *Manager) Delete(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.scenes[name]; !ok {
+		return fmt.Errorf("scene %s not found", name)
+	}
+	delete(m.scenes, name)
+	return store.Save(m.path, m.scenes)
+}
+
+// Apply patches each device with the fields that differ from the scene. If any
+// device fails, devices already changed are restored to their prior settings.
+func (m *Manager) Apply(ctx context.Context, name string, dryRun bool) (*ApplyResult, error) {
+	m.mu.Lock()
+	scene, ok := m.scenes[name]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("scene %s not found", name)
+	}
+
+	result := &ApplyResult{Scene: name, DryRun: dryRun, Success: true}
+	type applied struct {
+		index    int
+		previous map[string]interface{}
+		unknown  []string
+	}
+	var done []applied
+
+	for _, state := range scene.Devices {
+		dr := DeviceResult{Type: state.Type, ID: state.ID, Name: state.Name}
+
+		if !result.Success {
+			dr.Status = StatusSkipped
+			result.Devices = append(result.Devices, dr)
+			continue
+		}
+
+		current, err := m.get(ctx, state.Type, state.ID)
+		if err != nil {
+			dr.Status = StatusFailed
+			dr.Error = err.Error()
+			result.Success = false
+			result.Devices = append(result.Devices, dr)
+			continue
+		}
+
+		changes, previous, unknown := diff(state.Settings, current)
+		dr.Changes = changes
+		switch {
+		case len(changes) == 0:
+			dr.Status = StatusUnchanged
+		case dryRun:
+			dr.Status = StatusWouldApply
+		default:
+			if err := m.patch(ctx, state.Type, state.ID, changes); err != nil {
+				dr.Status = StatusFailed
+				dr.Error = err.Error()
+				result.Success = false
+			} else {
+				dr.Status = StatusApplied
+				done = append(done, applied{index: len(result.Devices), previous: previous, unknown: unknown})
+			}
+		}
+		result.Devices = append(result.Devices, dr)
+	}
+
+	if !result.Success && len(done) > 0 {
+		result.RolledBack = true
+		for _, a := range done {
+			dr := &result.Devices[a.index]
+			if len(a.previous) > 0 {
+				if err := m.patch(ctx, dr.Type, dr.ID, a.previous); err != nil {
+					dr.Status = StatusRollbackFailed
+					dr.Error = err.Error()
+					m.logger.WithError(err).WithField("device_id", dr.ID).Error("Failed to roll back scene change")
+					continue
+				}
+			}
+			dr.Status = StatusRolledBack
+			// Fields the device did not report have no value to restore;
+			// patching them with null would clear them
+			if len(a.unknown) > 0 {
+				dr.Status = StatusNotRolledBack
+				dr.Error = fmt.Sprintf("the device did not report %s before the scene was applied, so it was left as set by the scene", strings.Join(a.unknown, ", "))
+			}
+		}
+	}
+	return result, nil
+}
+
+// diff returns the scene fields that differ from the current device settings,
+// along with the current values of those fields. Changed fields the device
+// does not report are returned as unknown, sorted, instead of in previous.
+func diff(want, current map[string]interface{}) (changes, previous map[string]interface{}, unknown []string) {
+	changes = map[string]interface{}{}
+	previous = map[string]interface{}{}
+	for field, value := range want {
+		old, ok := current[field]
+		if ok && reflect.DeepEqual(old, value) {
+			continue
+		}
+		changes[field] = value
+		if ok {
+			previous[field] = old
+		} else {
+			unknown = append(unknown, field)
+		}
+	}
+	sort.Strings(unknown)
+	return changes, previous, unknown
+}
+
+func (m *Manager) get(ctx context.Context, deviceType, id string) (map[string]interface{}, error) {
+	switch deviceType {
+	case "camera":
+		return m.client.GetCameraDetailed(ctx, id)
+	case "light":
+		return m.client.GetLightDetailed(ctx, id)
+	case "chime":
+		return m.client.GetChimeDetailed(ctx, id)
+	}
+	return nil, fmt.Errorf("unsupported device type %s", deviceType)
+}
+
+func (m *Manager) patch(ctx context.Context, deviceType, id string, settings map[string]interface{}) error {
+	var err error
+	switch deviceType {
+	case "camera":
+		_, err = m.client.PatchCamera(ctx, id, settings)
+	case "light":
+		_, err = m.client.PatchLight(ctx, id, settings)
+	case "chime":
+		_, err = m.client.PatchChime(ctx, id, settings)
+	default:
+		err = fmt.Errorf("unsupported device type %s", deviceType)
+	}
+	return err
+}
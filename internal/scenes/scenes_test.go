@@ -0,0 +1,166 @@
+package scenes
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeClient struct {
+	devices   map[string]map[string]interface{}
+	failPatch map[string]bool
+	patches   []string
+}
+
+func (f *fakeClient) get(id string) (map[string]interface{}, error) {
+	d, ok := f.devices[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	copied := map[string]interface{}{}
+	for k, v := range d {
+		copied[k] = v
+	}
+	return copied, nil
+}
+
+func (f *fakeClient) patch(id string, settings map[string]interface{}) (map[string]interface{}, error) {
+	if f.failPatch[id] {
+		return nil, errors.New("request failed with status 500")
+	}
+	f.patches = append(f.patches, id)
+	for k, v := range settings {
+		f.devices[id][k] = v
+	}
+	return f.devices[id], nil
+}
+
+func (f *fakeClient) GetCameraDetailed(ctx context.Context, id string) (map[string]interface{}, error) {
+	return f.get(id)
+}
+
+func (f *fakeClient) GetLightDetailed(ctx context.Context, id string) (map[string]interface{}, error) {
+	return f.get(id)
+}
+
+func (f *fakeClient) GetChimeDetailed(ctx context.Context, id string) (map[string]interface{}, error) {
+	return f.get(id)
+}
+
+func (f *fakeClient) PatchCamera(ctx context.Context, id string, s map[string]interface{}) (map[string]interface{}, error) {
+	return f.patch(id, s)
+}
+
+func (f *fakeClient) PatchLight(ctx context.Context, id string, s map[string]interface{}) (map[string]interface{}, error) {
+	return f.patch(id, s)
+}
+
+func (f *fakeClient) PatchChime(ctx context.Context, id string, s map[string]interface{}) (map[string]interface{}, error) {
+	return f.patch(id, s)
+}
+
+func newFake() *fakeClient {
+	return &fakeClient{
+		devices: map[string]map[string]interface{}{
+			"cam1":   {"name": "Porch", "micVolume": float64(50), "ledSettings": map[string]interface{}{"isEnabled": true}},
+			"light1": {"name": "Drive", "lightModeSettings": map[string]interface{}{"mode": "motion", "enableAt": "dark"}},
+		},
+		failPatch: map[string]bool{},
+	}
+}
+
+func TestApplyPatchesOnlyDifferences(t *testing.T) {
+	client := newFake()
+	m, err := NewManager(client, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := m.Capture(ctx, "Night", "", Selection{Cameras: []string{"cam1"}, Lights: []string{"light1"}}); err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+
+	client.devices["cam1"]["micVolume"] = float64(80)
+
+	result, err := m.Apply(ctx, "Night", false)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("expected success, got %+v", result)
+	}
+	if result.Devices[0].Status != StatusApplied || len(result.Devices[0].Changes) != 1 {
+		t.Errorf("expected only micVolume to change on camera, got %+v", result.Devices[0])
+	}
+	if result.Devices[1].Status != StatusUnchanged {
+		t.Errorf("expected light to be unchanged, got %+v", result.Devices[1])
+	}
+	if client.devices["cam1"]["micVolume"] != float64(50) {
+		t.Errorf("micVolume not restored: %v", client.devices["cam1"]["micVolume"])
+	}
+}
+
+func TestApplyRollsBackOnFailure(t *testing.T) {
+	client := newFake()
+	m, err := NewManager(client, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := m.Capture(ctx, "Away", "", Selection{Cameras: []string{"cam1"}, Lights: []string{"light1"}}); err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+
+	client.devices["cam1"]["micVolume"] = float64(80)
+	client.devices["light1"]["lightModeSettings"] = map[string]interface{}{"mode": "off", "enableAt": "dark"}
+	client.failPatch["light1"] = true
+
+	result, err := m.Apply(ctx, "Away", false)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result.Success || !result.RolledBack {
+		t.Fatalf("expected failed apply with rollback, got %+v", result)
+	}
+	if result.Devices[0].Status != StatusRolledBack || result.Devices[1].Status != StatusFailed {
+		t.Errorf("unexpected device statuses: %+v", result.Devices)
+	}
+	if client.devices["cam1"]["micVolume"] != float64(80) {
+		t.Errorf("camera not rolled back: micVolume = %v", client.devices["cam1"]["micVolume"])
+	}
+}
+
+func TestRollbackSkipsUnreportedFields(t *testing.T) {
+	client := newFake()
+	m, err := NewManager(client, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := m.Capture(ctx, "Away", "", Selection{Cameras: []string{"cam1"}, Lights: []string{"light1"}}); err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+
+	// The camera stops reporting its LED settings
+	delete(client.devices["cam1"], "ledSettings")
+	client.devices["cam1"]["micVolume"] = float64(80)
+	client.devices["light1"]["lightModeSettings"] = map[string]interface{}{"mode": "off", "enableAt": "dark"}
+	client.failPatch["light1"] = true
+
+	result, err := m.Apply(ctx, "Away", false)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if cam := result.Devices[0]; cam.Status != StatusNotRolledBack || cam.Error == "" {
+		t.Errorf("expected the camera to be reported as not rolled back, got %+v", cam)
+	}
+	if v, ok := client.devices["cam1"]["ledSettings"]; !ok || v == nil {
+		t.Errorf("expected ledSettings to be left as set by the scene, got %v", v)
+	}
+	if client.devices["cam1"]["micVolume"] != float64(80) {
+		t.Errorf("expected micVolume to be restored, got %v", client.devices["cam1"]["micVolume"])
+	}
+}
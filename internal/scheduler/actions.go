@@ -0,0 +1,170 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// Client is the subset of the Protect client that scheduled actions call
+type Client interface {
+	PatchCamera(ctx context.Context, cameraID string, settings map[string]interface{}) (map[string]interface{}, error)
+	SetDoorbellMessage(ctx context.Context, cameraID, messageType, text string, resetAt *time.Time) (map[string]interface{}, error)
+	GetDoorbellDefaultResetTimeout(ctx context.Context) (time.Duration, error)
+	PatchLight(ctx context.Context, lightID string, settings map[string]interface{}) (map[string]interface{}, error)
+	PatchChime(ctx context.Context, chimeID string, settings map[string]interface{}) (map[string]interface{}, error)
+	PatchViewer(ctx context.Context, viewerID string, settings map[string]interface{}) (map[string]interface{}, error)
+	CameraStartPTZPatrol(ctx context.Context, cameraID string, slot int) (map[string]interface{}, error)
+	CameraStopPTZPatrol(ctx context.Context, cameraID string) (map[string]interface{}, error)
+	CameraGotoPTZPreset(ctx context.Context, cameraID string, slot int) (map[string]interface{}, error)
+	TriggerWebhookAlarm(ctx context.Context, webhookID string, payload map[string]interface{}) (map[string]interface{}, error)
+}
+
+// actionSpec describes a schedulable action and the parameters it requires
+type actionSpec struct {
+	description string
+	required    []string
+	run         func(ctx context.Context, c Client, p params) (map[string]interface{}, error)
+}
+
+var actions = map[string]actionSpec{
+	"ptz_patrol_start": {
+		description: "Start PTZ patrol (camera_id, slot)",
+		required:    []string{"camera_id", "slot"},
+		run: func(ctx context.Context, c Client, p params) (map[string]interface{}, error) {
+			return c.CameraStartPTZPatrol(ctx, p.str("camera_id"), p.integer("slot"))
+		},
+	},
+	"ptz_patrol_stop": {
+		description: "Stop PTZ patrol (camera_id)",
+		required:    []string{"camera_id"},
+		run: func(ctx context.Context, c Client, p params) (map[string]interface{}, error) {
+			return c.CameraStopPTZPatrol(ctx, p.str("camera_id"))
+		},
+	},
+	"ptz_goto_preset": {
+		description: "Move PTZ camera to preset (camera_id, slot)",
+		required:    []string{"camera_id", "slot"},
+		run: func(ctx context.Context, c Client, p params) (map[string]interface{}, error) {
+			return c.CameraGotoPTZPreset(ctx, p.str("camera_id"), p.integer("slot"))
+		},
+	},
+	"doorbell_message": {
+		description: "Set doorbell LCD message (camera_id, type, text, duration_minutes)",
+		required:    []string{"camera_id", "type"},
+		run: func(ctx context.Context, c Client, p params) (map[string]interface{}, error) {
+			// Without a duration the message follows the NVR default timeout
+			duration := time.Duration(p.integer("duration_minutes")) * time.Minute
+			if duration == 0 {
+				var err error
+				if duration, err = c.GetDoorbellDefaultResetTimeout(ctx); err != nil {
+					return nil, err
+				}
+			}
+			return c.SetDoorbellMessage(ctx, p.str("camera_id"), p.str("type"), p.str("text"), unifi.DoorbellResetAt(time.Now(), duration))
+		},
+	},
+	"patch_camera": {
+		description: "Update camera settings (camera_id, settings)",
+		required:    []string{"camera_id", "settings"},
+		run: func(ctx context.Context, c Client, p params) (map[string]interface{}, error) {
+			return c.PatchCamera(ctx, p.str("camera_id"), p.object("settings"))
+		},
+	},
+	"patch_light": {
+		description: "Update light settings (light_id, settings)",
+		required:    []string{"light_id", "settings"},
+		run: func(ctx context.Context, c Client, p params) (map[string]interface{}, error) {
+			return c.PatchLight(ctx, p.str("light_id"), p.object("settings"))
+		},
+	},
+	"patch_chime": {
+		description: "Update chime settings (chime_id, settings)",
+		required:    []string{"chime_id", "settings"},
+		run: func(ctx context.Context, c Client, p params) (map[string]interface{}, error) {
+			return c.PatchChime(ctx, p.str("chime_id"), p.object("settings"))
+		},
+	},
+	"patch_viewer": {
+		description: "Update viewer settings (viewer_id, settings)",
+		required:    []string{"viewer_id", "settings"},
+		run: func(ctx context.Context, c Client, p params) (map[string]interface{}, error) {
+			return c.PatchViewer(ctx, p.str("viewer_id"), p.object("settings"))
+		},
+	},
+	"trigger_webhook_alarm": {
+		description: "Trigger an alarm manager webhook (webhook_id, payload)",
+		required:    []string{"webhook_id"},
+		run: func(ctx context.Context, c Client, p params) (map[string]interface{}, error) {
+			payload := p.object("payload")
+			if payload == nil {
+				payload = map[string]interface{}{}
+			}
+			return c.TriggerWebhookAlarm(ctx, p.str("webhook_id"), payload)
+		},
+	},
+}
+
+// ActionNames returns the supported action names with their descriptions
+func ActionNames() map[string]string {
+	names := make(map[string]string, len(actions))
+	for name, spec := range actions {
+		names[name] = spec.description
+	}
+	return names
+}
+
+func validateAction(name string, p params) error {
+	spec, ok := actions[name]
+	if !ok {
+		known := make([]string, 0, len(actions))
+		for n := range actions {
+			known = append(known, n)
+		}
+		sort.Strings(known)
+		return fmt.Errorf("unknown action %q, expected one of %v", name, known)
+	}
+	for _, key := range spec.required {
+		if _, ok := p[key]; !ok {
+			return fmt.Errorf("action %s requires parameter %s", name, key)
+		}
+	}
+	if name == "doorbell_message" {
+		if err := unifi.ValidateLCDMessage(p.str("type"), p.str("text")); err != nil {
+			return err
+		}
+	}
+	if _, ok := p["settings"]; ok && p.object("settings") == nil {
+		return fmt.Errorf("settings must be an object")
+	}
+	if _, ok := p["slot"]; ok && p.integer("slot") < 0 {
+		return fmt.Errorf("slot must not be negative")
+	}
+	return nil
+}
+
+// params holds action parameters as decoded from JSON
+type params map[string]interface{}
+
+func (p params) str(key string) string {
+	v, _ := p[key].(string)
+	return v
+}
+
+func (p params) integer(key string) int {
+	switch v := p[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+func (p params) object(key string) map[string]interface{} {
+	v, _ := p[key].(map[string]interface{})
+	return v
+}
@@ -0,0 +1,365 @@
+// Package scheduler runs Protect actions on a cron schedule or at a set time
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/store"
+)
+
+// runHistoryLimit is how many run outcomes are kept per job
+const runHistoryLimit = 20
+
+// completedRetention is how long a one-off job is listed, with its outcome,
+// after it has run
+const completedRetention = 24 * time.Hour
+
+// Job is a scheduled action. Exactly one of Schedule (cron) or RunAt (one-off)
+// is set. Adding a job with a Key replaces any pending job with the same key,
+// which lets callers such as auto-revert timers be extended or re-armed.
+type Job struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name,omitempty"`
+	Key       string                 `json:"key,omitempty"`
+	Schedule  string                 `json:"schedule,omitempty"`
+	RunAt     *time.Time             `json:"run_at,omitempty"`
+	Action    string                 `json:"action"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	Completed bool                   `json:"completed,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	NextRun   *time.Time             `json:"next_run,omitempty"`
+	Runs      []Run                  `json:"runs,omitempty"`
+}
+
+// Run is the outcome of one execution of a job
+type Run struct {
+	Time     time.Time `json:"time"`
+	Success  bool      `json:"success"`
+	Error    string    `json:"error,omitempty"`
+	Duration string    `json:"duration"`
+}
+
+// Scheduler owns the job list and runs jobs when they are due
+type Scheduler struct {
+	client  Client
+	path    string
+	mu      sync.Mutex
+	jobs    []*Job
+	cron    *cron.Cron
+	entries map[string]cron.EntryID
+	timers  map[string]*time.Timer
+	ctx     context.Context
+	started bool
+	logger  *logrus.Entry
+}
+
+// New loads persisted jobs from dataDir
+func New(client Client, dataDir string) (*Scheduler, error) {
+	s := &Scheduler{
+		client:  client,
+		path:    filepath.Join(dataDir, "scheduler", "jobs.json"),
+		cron:    cron.New(),
+		entries: map[string]cron.EntryID{},
+		timers:  map[string]*time.Timer{},
+		ctx:     context.Background(),
+		logger:  logrus.WithField("component", "Scheduler"),
+	}
+	if err := store.Load(s.path, &s.jobs); err != nil {
+		return nil, err
+	}
+	s.pruneLocked(time.Now())
+	return s, nil
+}
+
+// Start begins running jobs; one-off jobs missed while the server was down run
+// immediately
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ctx = ctx
+	s.started = true
+	for _, job := range s.jobs {
+		if err := s.armLocked(job); err != nil {
+			s.logger.WithError(err).WithField("job_id", job.ID).Error("Failed to schedule job")
+		}
+	}
+	s.cron.Start()
+	s.logger.WithField("jobs", len(s.jobs)).Info("Scheduler started")
+
+	go func() {
+		<-ctx.Done()
+		s.cron.Stop()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, t := range s.timers {
+			t.Stop()
+		}
+	}()
+}
+
+// Add validates and persists a new job, scheduling it if the scheduler is running
+func (s *Scheduler) Add(job Job) (Job, error) {
+	if (job.Schedule == "") == (job.RunAt == nil) {
+		return Job{}, errors.New("exactly one of schedule or run_at is required")
+	}
+	if job.Schedule != "" {
+		if _, err := cron.ParseStandard(job.Schedule); err != nil {
+			return Job{}, fmt.Errorf("invalid schedule: %w", err)
+		}
+	}
+	if job.RunAt != nil && job.RunAt.Before(time.Now()) {
+		return Job{}, errors.New("run_at is in the past")
+	}
+	if err := validateAction(job.Action, job.Params); err != nil {
+		return Job{}, err
+	}
+
+	job.ID = uuid.NewString()
+	job.CreatedAt = time.Now()
+	job.Completed = false
+	job.Runs = nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A pending job with the same key is replaced, but stays armed until the
+	// new job is saved and armed so a failure leaves it in place
+	previous := s.jobs
+	jobs := make([]*Job, 0, len(previous)+1)
+	var replaced []*Job
+	for _, existing := range previous {
+		if job.Key != "" && existing.Key == job.Key && !existing.Completed {
+			replaced = append(replaced, existing)
+			continue
+		}
+		jobs = append(jobs, existing)
+	}
+	s.jobs = append(jobs, &job)
+	if err := s.saveLocked(); err != nil {
+		s.jobs = previous
+		return Job{}, err
+	}
+	if s.started {
+		if err := s.armLocked(&job); err != nil {
+			s.jobs = previous
+			if saveErr := s.saveLocked(); saveErr != nil {
+				s.logger.WithError(saveErr).Error("Failed to remove a job that could not be scheduled")
+			}
+			return Job{}, err
+		}
+	}
+	for _, old := range replaced {
+		s.disarmLocked(old.ID)
+	}
+	return s.snapshotLocked(&job), nil
+}
+
+// List returns all jobs ordered by creation time
+func (s *Scheduler) List() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(time.Now())
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, s.snapshotLocked(job))
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+// Cancel removes a job
+func (s *Scheduler) Cancel(id string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, job := range s.jobs {
+		if job.ID != id {
+			continue
+		}
+		s.removeLocked(i)
+		if err := s.saveLocked(); err != nil {
+			return Job{}, err
+		}
+		return *job, nil
+	}
+	return Job{}, fmt.Errorf("job %s not found", id)
+}
+
+// Pending returns the pending job with the given key, if any
+func (s *Scheduler) Pending(key string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.jobs {
+		if job.Key == key && !job.Completed {
+			return s.snapshotLocked(job), true
+		}
+	}
+	return Job{}, false
+}
+
+// CancelKey removes any pending job with the given key, reporting whether one
+// was found
+func (s *Scheduler) CancelKey(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.removeKeyLocked(key) {
+		return false, nil
+	}
+	return true, s.saveLocked()
+}
+
+// removeKeyLocked removes pending jobs with the given key
+func (s *Scheduler) removeKeyLocked(key string) bool {
+	removed := false
+	for i := len(s.jobs) - 1; i >= 0; i-- {
+		if s.jobs[i].Key == key && !s.jobs[i].Completed {
+			s.removeLocked(i)
+			removed = true
+		}
+	}
+	return removed
+}
+
+// removeLocked disarms and removes the job at index i
+func (s *Scheduler) removeLocked(i int) {
+	s.disarmLocked(s.jobs[i].ID)
+	s.jobs = append(s.jobs[:i], s.jobs[i+1:]...)
+}
+
+// disarmLocked stops a job's cron entry or timer
+func (s *Scheduler) disarmLocked(id string) {
+	if entry, ok := s.entries[id]; ok {
+		s.cron.Remove(entry)
+		delete(s.entries, id)
+	}
+	if t, ok := s.timers[id]; ok {
+		t.Stop()
+		delete(s.timers, id)
+	}
+}
+
+// armLocked registers a job with cron or a timer
+func (s *Scheduler) armLocked(job *Job) error {
+	id := job.ID
+	if job.Schedule != "" {
+		entry, err := s.cron.AddFunc(job.Schedule, func() { s.execute(id) })
+		if err != nil {
+			return err
+		}
+		s.entries[id] = entry
+		return nil
+	}
+	if job.Completed {
+		return nil
+	}
+	delay := time.Until(*job.RunAt)
+	if delay < 0 {
+		delay = 0
+	}
+	s.timers[id] = time.AfterFunc(delay, func() { s.execute(id) })
+	return nil
+}
+
+func (s *Scheduler) execute(id string) {
+	s.mu.Lock()
+	var job *Job
+	for _, j := range s.jobs {
+		if j.ID == id {
+			job = j
+			break
+		}
+	}
+	if job == nil {
+		s.mu.Unlock()
+		return
+	}
+	ctx := s.ctx
+	action := job.Action
+	p := params(job.Params)
+	s.mu.Unlock()
+
+	logger := s.logger.WithFields(logrus.Fields{"job_id": id, "action": action})
+	start := time.Now()
+	run := Run{Time: start, Success: true}
+	if _, err := actions[action].run(ctx, s.client, p); err != nil {
+		run.Success = false
+		run.Error = err.Error()
+		logger.WithError(err).Warn("Scheduled action failed")
+	} else {
+		logger.Info("Scheduled action completed")
+	}
+	run.Duration = time.Since(start).Round(time.Millisecond).String()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job.Runs = append(job.Runs, run)
+	if len(job.Runs) > runHistoryLimit {
+		job.Runs = job.Runs[len(job.Runs)-runHistoryLimit:]
+	}
+	if job.RunAt != nil {
+		job.Completed = true
+		delete(s.timers, id)
+	}
+	if err := s.saveLocked(); err != nil {
+		logger.WithError(err).Error("Failed to persist job run")
+	}
+}
+
+// snapshotLocked copies a job and fills in its next run time
+func (s *Scheduler) snapshotLocked(job *Job) Job {
+	c := *job
+	c.Runs = append([]Run(nil), job.Runs...)
+	c.NextRun = nil
+	if entry, ok := s.entries[job.ID]; ok {
+		if next := s.cron.Entry(entry).Next; !next.IsZero() {
+			c.NextRun = &next
+		}
+	} else if job.Schedule != "" {
+		if sched, err := cron.ParseStandard(job.Schedule); err == nil {
+			next := sched.Next(time.Now())
+			c.NextRun = &next
+		}
+	} else if !job.Completed {
+		c.NextRun = job.RunAt
+	}
+	return c
+}
+
+// pruneLocked drops one-off jobs that completed more than completedRetention
+// before now
+func (s *Scheduler) pruneLocked(now time.Time) {
+	for i := len(s.jobs) - 1; i >= 0; i-- {
+		job := s.jobs[i]
+		if !job.Completed {
+			continue
+		}
+		finished := job.RunAt
+		if n := len(job.Runs); n > 0 {
+			finished = &job.Runs[n-1].Time
+		}
+		if finished == nil || now.Sub(*finished) > completedRetention {
+			s.removeLocked(i)
+		}
+	}
+}
+
+func (s *Scheduler) saveLocked() error {
+	s.pruneLocked(time.Now())
+	return store.Save(s.path, s.jobs)
+}
@@ -0,0 +1,190 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClient struct {
+	Client
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeClient) CameraGotoPTZPreset(ctx context.Context, cameraID string, slot int) (map[string]interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, cameraID)
+	return map[string]interface{}{}, nil
+}
+
+func TestOneOffJobRunsAndPersists(t *testing.T) {
+	client := &fakeClient{}
+	dataDir := t.TempDir()
+
+	s, err := New(client, dataDir)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+
+	runAt := time.Now().Add(50 * time.Millisecond)
+	job, err := s.Add(Job{RunAt: &runAt, Action: "ptz_goto_preset", Params: map[string]interface{}{"camera_id": "cam1", "slot": float64(0)}})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		jobs := s.List()
+		if len(jobs) == 1 && jobs[0].Completed {
+			if len(jobs[0].Runs) != 1 || !jobs[0].Runs[0].Success {
+				t.Fatalf("expected one successful run, got %+v", jobs[0].Runs)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("job did not run")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	reloaded, err := New(client, dataDir)
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	jobs := reloaded.List()
+	if len(jobs) != 1 || jobs[0].ID != job.ID || !jobs[0].Completed {
+		t.Fatalf("job not persisted: %+v", jobs)
+	}
+
+	if _, err := reloaded.Cancel(job.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if len(reloaded.List()) != 0 {
+		t.Fatal("job not removed")
+	}
+}
+
+func TestCompletedJobsExpire(t *testing.T) {
+	dataDir := t.TempDir()
+	s, err := New(&fakeClient{}, dataDir)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	recent := time.Now().Add(-time.Hour)
+	old := time.Now().Add(-completedRetention - time.Hour)
+	s.jobs = []*Job{
+		{ID: "recent", RunAt: &recent, Action: "ptz_goto_preset", Completed: true, Runs: []Run{{Time: recent, Success: true}}},
+		{ID: "old", RunAt: &old, Action: "ptz_goto_preset", Completed: true, Runs: []Run{{Time: old, Success: true}}},
+	}
+	if err := s.saveLocked(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	reloaded, err := New(&fakeClient{}, dataDir)
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	jobs := reloaded.List()
+	if len(jobs) != 1 || jobs[0].ID != "recent" {
+		t.Fatalf("expected only the recently completed job, got %+v", jobs)
+	}
+}
+
+func TestAddValidatesAction(t *testing.T) {
+	s, err := New(&fakeClient{}, t.TempDir())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := s.Add(Job{Schedule: "0 22 * * *", Action: "reboot"}); err == nil {
+		t.Error("expected unknown action to be rejected")
+	}
+	if _, err := s.Add(Job{Schedule: "0 22 * * *", Action: "ptz_patrol_start", Params: map[string]interface{}{"camera_id": "cam1"}}); err == nil {
+		t.Error("expected missing slot to be rejected")
+	}
+	if _, err := s.Add(Job{Schedule: "every night", Action: "ptz_patrol_stop", Params: map[string]interface{}{"camera_id": "cam1"}}); err == nil {
+		t.Error("expected invalid schedule to be rejected")
+	}
+	if _, err := s.Add(Job{Schedule: "0 22 * * *", Action: "ptz_patrol_start", Params: map[string]interface{}{"camera_id": "cam1", "slot": float64(2)}}); err != nil {
+		t.Errorf("valid job rejected: %v", err)
+	}
+}
+
+func TestKeyedJobReplacesPendingJob(t *testing.T) {
+	s, err := New(&fakeClient{}, t.TempDir())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	params := map[string]interface{}{"light_id": "light1", "settings": map[string]interface{}{"isLightForceEnabled": false}}
+	first := time.Now().Add(time.Hour)
+	if _, err := s.Add(Job{Key: "revert:light1", RunAt: &first, Action: "patch_light", Params: params}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	second := time.Now().Add(2 * time.Hour)
+	job, err := s.Add(Job{Key: "revert:light1", RunAt: &second, Action: "patch_light", Params: params})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if jobs := s.List(); len(jobs) != 1 || jobs[0].ID != job.ID {
+		t.Fatalf("expected only the replacement job, got %+v", jobs)
+	}
+
+	found, err := s.CancelKey("revert:light1")
+	if err != nil || !found {
+		t.Fatalf("CancelKey = %v, %v", found, err)
+	}
+	if len(s.List()) != 0 {
+		t.Fatal("keyed job not removed")
+	}
+}
+
+func TestReplacingKeyedJobKeepsOldJobWhenSaveFails(t *testing.T) {
+	dataDir := t.TempDir()
+	s, err := New(&fakeClient{}, dataDir)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+
+	runAt := time.Now().Add(time.Hour)
+	params := map[string]interface{}{"camera_id": "cam1", "slot": float64(0)}
+	old, err := s.Add(Job{Key: "revert", RunAt: &runAt, Action: "ptz_goto_preset", Params: params})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	// Replace the state directory with a file so saves fail
+	dir := filepath.Join(dataDir, "scheduler")
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := runAt.Add(time.Hour)
+	if _, err := s.Add(Job{Key: "revert", RunAt: &later, Action: "ptz_goto_preset", Params: params}); err == nil {
+		t.Fatal("expected Add to fail when the jobs cannot be saved")
+	}
+
+	pending, ok := s.Pending("revert")
+	if !ok || pending.ID != old.ID {
+		t.Fatalf("expected the old job to stay pending, got %+v", pending)
+	}
+	s.mu.Lock()
+	_, armed := s.timers[old.ID]
+	s.mu.Unlock()
+	if !armed {
+		t.Error("expected the old job to stay armed")
+	}
+}
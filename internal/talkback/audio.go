@@ -0,0 +1,174 @@
+// Package talkback plays audio files and synthesized speech through camera
+// speakers over a Protect talkback session
+package talkback
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Audio is mono signed 16-bit PCM
+type Audio struct {
+	SampleRate int
+	Samples    []int16
+}
+
+// Duration returns the playing time of the audio
+func (a *Audio) Duration() time.Duration {
+	if a.SampleRate <= 0 {
+		return 0
+	}
+	return time.Duration(len(a.Samples)) * time.Second / time.Duration(a.SampleRate)
+}
+
+// Resample converts the audio to rate using linear interpolation
+func (a *Audio) Resample(rate int) *Audio {
+	if rate == a.SampleRate || len(a.Samples) == 0 {
+		return &Audio{SampleRate: rate, Samples: a.Samples}
+	}
+	n := int(int64(len(a.Samples)) * int64(rate) / int64(a.SampleRate))
+	out := make([]int16, n)
+	step := float64(a.SampleRate) / float64(rate)
+	last := len(a.Samples) - 1
+	for i := range out {
+		pos := float64(i) * step
+		j := int(pos)
+		if j >= last {
+			out[i] = a.Samples[last]
+			continue
+		}
+		frac := pos - float64(j)
+		out[i] = int16(float64(a.Samples[j])*(1-frac) + float64(a.Samples[j+1])*frac)
+	}
+	return &Audio{SampleRate: rate, Samples: out}
+}
+
+// DecodePCM reads raw little-endian signed 16-bit mono PCM
+func DecodePCM(data []byte, sampleRate int) (*Audio, error) {
+	if sampleRate <= 0 {
+		return nil, errors.New("sample rate must be positive")
+	}
+	if len(data)%2 != 0 {
+		return nil, errors.New("PCM data must be whole 16-bit samples")
+	}
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+	return &Audio{SampleRate: sampleRate, Samples: samples}, nil
+}
+
+const (
+	wavFormatPCM        = 1
+	wavFormatExtensible = 0xFFFE
+)
+
+// DecodeWAV reads an 8 or 16-bit PCM WAV file, mixing multiple channels down
+// to mono
+func DecodeWAV(data []byte) (*Audio, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, errors.New("not a WAV file")
+	}
+
+	var (
+		format, channels, bits uint16
+		rate                   uint32
+		haveFormat             bool
+	)
+	for rest := data[12:]; len(rest) >= 8; {
+		id := string(rest[:4])
+		size := int(binary.LittleEndian.Uint32(rest[4:8]))
+		rest = rest[8:]
+		if size > len(rest) {
+			// Streamed WAVs often declare a larger data chunk than they hold
+			if id != "data" {
+				return nil, fmt.Errorf("truncated WAV %q chunk", id)
+			}
+			size = len(rest)
+		}
+		chunk := rest[:size]
+		rest = rest[size:]
+		if size%2 == 1 && len(rest) > 0 {
+			rest = rest[1:]
+		}
+
+		switch id {
+		case "fmt ":
+			if len(chunk) < 16 {
+				return nil, errors.New("WAV fmt chunk is too short")
+			}
+			format = binary.LittleEndian.Uint16(chunk[0:])
+			channels = binary.LittleEndian.Uint16(chunk[2:])
+			rate = binary.LittleEndian.Uint32(chunk[4:])
+			bits = binary.LittleEndian.Uint16(chunk[14:])
+			if format == wavFormatExtensible && len(chunk) >= 26 {
+				format = binary.LittleEndian.Uint16(chunk[24:])
+			}
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, errors.New("WAV data chunk precedes fmt chunk")
+			}
+			if format != wavFormatPCM {
+				return nil, fmt.Errorf("unsupported WAV encoding %d, only PCM is supported", format)
+			}
+			if channels == 0 || rate == 0 {
+				return nil, errors.New("WAV file has no channels or sample rate")
+			}
+			return decodeFrames(chunk, int(channels), int(bits), int(rate))
+		}
+	}
+	return nil, errors.New("WAV file has no data chunk")
+}
+
+func decodeFrames(data []byte, channels, bits, rate int) (*Audio, error) {
+	var width int
+	switch bits {
+	case 8:
+		width = 1
+	case 16:
+		width = 2
+	default:
+		return nil, fmt.Errorf("unsupported WAV sample size %d bits, expected 8 or 16", bits)
+	}
+	frame := width * channels
+	samples := make([]int16, len(data)/frame)
+	for i := range samples {
+		sum := 0
+		for c := 0; c < channels; c++ {
+			off := i*frame + c*width
+			if width == 1 {
+				sum += (int(data[off]) - 128) << 8
+			} else {
+				sum += int(int16(binary.LittleEndian.Uint16(data[off:])))
+			}
+		}
+		samples[i] = int16(sum / channels)
+	}
+	return &Audio{SampleRate: rate, Samples: samples}, nil
+}
+
+// EncodeWAV writes the audio as a 16-bit mono PCM WAV file
+func EncodeWAV(a *Audio) []byte {
+	dataSize := uint32(len(a.Samples) * 2)
+	le := binary.LittleEndian
+	out := make([]byte, 0, 44+dataSize)
+	out = append(out, "RIFF"...)
+	out = le.AppendUint32(out, 36+dataSize)
+	out = append(out, "WAVEfmt "...)
+	out = le.AppendUint32(out, 16)
+	out = le.AppendUint16(out, wavFormatPCM)
+	out = le.AppendUint16(out, 1)
+	out = le.AppendUint32(out, uint32(a.SampleRate))
+	out = le.AppendUint32(out, uint32(a.SampleRate*2))
+	out = le.AppendUint16(out, 2)
+	out = le.AppendUint16(out, 16)
+	out = append(out, "data"...)
+	out = le.AppendUint32(out, dataSize)
+	for _, sample := range a.Samples {
+		out = le.AppendUint16(out, uint16(sample))
+	}
+	return out
+}
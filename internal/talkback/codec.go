@@ -0,0 +1,252 @@
+package talkback
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FrameDuration is the audio carried by each RTP packet
+const FrameDuration = 20 * time.Millisecond
+
+// RTP payload types. PCMU and PCMA are static; the rest use the first
+// dynamic payload type.
+const (
+	payloadTypePCMU    = 0
+	payloadTypePCMA    = 8
+	payloadTypeDynamic = 96
+)
+
+// Frame is one encoded RTP payload and the number of RTP clock ticks it covers
+type Frame struct {
+	Payload []byte
+	Ticks   uint32
+}
+
+// Encoder converts PCM audio into RTP payloads for one codec
+type Encoder interface {
+	// SampleRate is the PCM rate the encoder expects its input at
+	SampleRate() int
+	// ClockRate is the RTP timestamp rate
+	ClockRate() int
+	PayloadType() uint8
+	Encode(ctx context.Context, audio *Audio) ([]Frame, error)
+}
+
+// Codecs lists the talkback codecs that can be encoded
+var Codecs = []string{"opus", "pcmu", "pcma", "l16"}
+
+// NewEncoder returns the encoder for a session codec. Opus is encoded by
+// running ffmpeg, found at ffmpegPath.
+func NewEncoder(codec string, sampleRate int, ffmpegPath string) (Encoder, error) {
+	switch strings.ToLower(codec) {
+	case "opus":
+		return &opusEncoder{rate: sampleRate, ffmpeg: ffmpegPath}, nil
+	case "pcmu", "g711u", "mulaw":
+		return &g711Encoder{payloadType: payloadTypePCMU, encode: linearToULaw}, nil
+	case "pcma", "g711a", "alaw":
+		return &g711Encoder{payloadType: payloadTypePCMA, encode: linearToALaw}, nil
+	case "l16", "pcm":
+		return &l16Encoder{rate: sampleRate}, nil
+	}
+	return nil, fmt.Errorf("unsupported talkback codec %q, expected one of %v", codec, Codecs)
+}
+
+// frames splits samples into FrameDuration chunks, padding the last with silence
+func frames(samples []int16, rate int) [][]int16 {
+	size := rate * int(FrameDuration/time.Millisecond) / 1000
+	var out [][]int16
+	for start := 0; start < len(samples); start += size {
+		chunk := make([]int16, size)
+		copy(chunk, samples[start:])
+		out = append(out, chunk)
+	}
+	return out
+}
+
+// l16Encoder sends uncompressed big-endian PCM as in RFC 3551
+type l16Encoder struct {
+	rate int
+}
+
+func (e *l16Encoder) SampleRate() int    { return e.rate }
+func (e *l16Encoder) ClockRate() int     { return e.rate }
+func (e *l16Encoder) PayloadType() uint8 { return payloadTypeDynamic }
+
+func (e *l16Encoder) Encode(ctx context.Context, audio *Audio) ([]Frame, error) {
+	var out []Frame
+	for _, chunk := range frames(audio.Samples, e.rate) {
+		payload := make([]byte, 0, 2*len(chunk))
+		for _, sample := range chunk {
+			payload = binary.BigEndian.AppendUint16(payload, uint16(sample))
+		}
+		out = append(out, Frame{Payload: payload, Ticks: uint32(len(chunk))})
+	}
+	return out, nil
+}
+
+// g711Encoder sends 8kHz G.711 companded audio
+type g711Encoder struct {
+	payloadType uint8
+	encode      func(int16) byte
+}
+
+func (e *g711Encoder) SampleRate() int    { return 8000 }
+func (e *g711Encoder) ClockRate() int     { return 8000 }
+func (e *g711Encoder) PayloadType() uint8 { return e.payloadType }
+
+func (e *g711Encoder) Encode(ctx context.Context, audio *Audio) ([]Frame, error) {
+	var out []Frame
+	for _, chunk := range frames(audio.Samples, 8000) {
+		payload := make([]byte, len(chunk))
+		for i, sample := range chunk {
+			payload[i] = e.encode(sample)
+		}
+		out = append(out, Frame{Payload: payload, Ticks: uint32(len(chunk))})
+	}
+	return out, nil
+}
+
+var (
+	aLawSegments = []int{0x1F, 0x3F, 0x7F, 0xFF, 0x1FF, 0x3FF, 0x7FF, 0xFFF}
+	uLawSegments = []int{0x3F, 0x7F, 0xFF, 0x1FF, 0x3FF, 0x7FF, 0xFFF, 0x1FFF}
+)
+
+func segment(value int, ends []int) int {
+	for i, end := range ends {
+		if value <= end {
+			return i
+		}
+	}
+	return len(ends)
+}
+
+// linearToALaw encodes a sample as G.711 A-law
+func linearToALaw(sample int16) byte {
+	value := int(sample) >> 3
+	mask := 0xD5
+	if value < 0 {
+		mask = 0x55
+		value = -value - 1
+	}
+	seg := segment(value, aLawSegments)
+	if seg >= 8 {
+		return byte(0x7F ^ mask)
+	}
+	aval := seg << 4
+	if seg < 2 {
+		aval |= (value >> 1) & 0x0F
+	} else {
+		aval |= (value >> seg) & 0x0F
+	}
+	return byte(aval ^ mask)
+}
+
+// linearToULaw encodes a sample as G.711 mu-law
+func linearToULaw(sample int16) byte {
+	const clip = 8159
+	value := int(sample) >> 2
+	mask := 0xFF
+	if value < 0 {
+		value = -value
+		mask = 0x7F
+	}
+	if value > clip {
+		value = clip
+	}
+	value += 0x21
+	seg := segment(value, uLawSegments)
+	if seg >= 8 {
+		return byte(0x7F ^ mask)
+	}
+	uval := seg<<4 | ((value >> (seg + 1)) & 0x0F)
+	return byte(uval ^ mask)
+}
+
+// opusEncoder pipes PCM through ffmpeg's libopus encoder and unpacks the Ogg
+// stream it writes. Opus RTP always uses a 48kHz clock (RFC 7587).
+type opusEncoder struct {
+	rate   int
+	ffmpeg string
+}
+
+func (e *opusEncoder) SampleRate() int    { return e.rate }
+func (e *opusEncoder) ClockRate() int     { return 48000 }
+func (e *opusEncoder) PayloadType() uint8 { return payloadTypeDynamic }
+
+func (e *opusEncoder) Encode(ctx context.Context, audio *Audio) ([]Frame, error) {
+	ffmpeg := e.ffmpeg
+	if ffmpeg == "" {
+		ffmpeg = "ffmpeg"
+	}
+	cmd := exec.CommandContext(ctx, ffmpeg,
+		"-hide_banner", "-loglevel", "error",
+		"-f", "s16le", "-ar", strconv.Itoa(e.rate), "-ac", "1", "-i", "pipe:0",
+		"-c:a", "libopus", "-application", "voip",
+		"-frame_duration", strconv.Itoa(int(FrameDuration/time.Millisecond)),
+		"-f", "ogg", "pipe:1")
+
+	pcm := make([]byte, 0, 2*len(audio.Samples))
+	for _, sample := range audio.Samples {
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(sample))
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(pcm)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("opus encoding with %s failed: %w: %s", ffmpeg, err, strings.TrimSpace(stderr.String()))
+	}
+
+	packets, err := oggPackets(stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	ticks := uint32(48000 * int(FrameDuration/time.Millisecond) / 1000)
+	var out []Frame
+	for _, packet := range packets {
+		// The first two packets are the OpusHead and OpusTags headers
+		if bytes.HasPrefix(packet, []byte("OpusHead")) || bytes.HasPrefix(packet, []byte("OpusTags")) {
+			continue
+		}
+		out = append(out, Frame{Payload: packet, Ticks: ticks})
+	}
+	return out, nil
+}
+
+// oggPackets reassembles the packets of a single logical Ogg stream
+func oggPackets(data []byte) ([][]byte, error) {
+	var (
+		packets [][]byte
+		partial []byte
+	)
+	for len(data) > 0 {
+		if len(data) < 27 || string(data[:4]) != "OggS" {
+			return nil, fmt.Errorf("invalid Ogg page")
+		}
+		segments := int(data[26])
+		if len(data) < 27+segments {
+			return nil, fmt.Errorf("truncated Ogg page")
+		}
+		lacing := data[27 : 27+segments]
+		body := data[27+segments:]
+		for _, size := range lacing {
+			if len(body) < int(size) {
+				return nil, fmt.Errorf("truncated Ogg page")
+			}
+			partial = append(partial, body[:size]...)
+			body = body[size:]
+			if size < 255 {
+				packets = append(packets, partial)
+				partial = nil
+			}
+		}
+		data = body
+	}
+	return packets, nil
+}
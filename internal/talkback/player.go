@@ -0,0 +1,119 @@
+package talkback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// MaxDuration is the longest audio clip that will be played
+const MaxDuration = 2 * time.Minute
+
+// Client is the subset of the Protect client used to open talkback sessions
+type Client interface {
+	CreateTalkbackSession(ctx context.Context, cameraID string) (*unifi.ProtectTalkbackSession, error)
+}
+
+// Result reports a finished playback
+type Result struct {
+	CameraID        string  `json:"camera_id"`
+	Codec           string  `json:"codec"`
+	SampleRate      int     `json:"sample_rate"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	StreamStats
+}
+
+// Player streams audio to camera speakers, one clip per camera at a time
+type Player struct {
+	client Client
+	tts    TTS
+	ffmpeg string
+	mu     sync.Mutex
+	active map[string]bool
+	logger *logrus.Entry
+}
+
+// NewPlayer creates a player. ttsCommand configures text to speech (see TTS)
+// and ffmpegPath the binary used to encode Opus.
+func NewPlayer(client Client, ttsCommand, ffmpegPath string) *Player {
+	return &Player{
+		client: client,
+		tts:    TTS{Command: ttsCommand},
+		ffmpeg: ffmpegPath,
+		active: map[string]bool{},
+		logger: logrus.WithField("component", "Talkback"),
+	}
+}
+
+// TTSEnabled reports whether text can be spoken
+func (p *Player) TTSEnabled() bool {
+	return p.tts.Enabled()
+}
+
+// Speak synthesizes text and plays it through a camera speaker
+func (p *Player) Speak(ctx context.Context, cameraID, text string) (*Result, error) {
+	audio, err := p.tts.Synthesize(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return p.Play(ctx, cameraID, audio)
+}
+
+// Play opens a talkback session, encodes the audio to the session's codec and
+// streams it to the camera. It returns once the last packet has been sent.
+func (p *Player) Play(ctx context.Context, cameraID string, audio *Audio) (*Result, error) {
+	if len(audio.Samples) == 0 {
+		return nil, errors.New("audio is empty")
+	}
+	if d := audio.Duration(); d > MaxDuration {
+		return nil, fmt.Errorf("audio is %s long, maximum is %s", d.Round(time.Second), MaxDuration)
+	}
+
+	p.mu.Lock()
+	if p.active[cameraID] {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("camera %s is already playing audio", cameraID)
+	}
+	p.active[cameraID] = true
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.active, cameraID)
+		p.mu.Unlock()
+	}()
+
+	session, err := p.client.CreateTalkbackSession(ctx, cameraID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create talkback session: %w", err)
+	}
+	enc, err := NewEncoder(session.Codec, session.SamplingRate, p.ffmpeg)
+	if err != nil {
+		return nil, err
+	}
+	frames, err := enc.Encode(ctx, audio.Resample(enc.SampleRate()))
+	if err != nil {
+		return nil, err
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"camera_id": cameraID,
+		"codec":     session.Codec,
+		"frames":    len(frames),
+	}).Info("Streaming talkback audio")
+	stats, err := Stream(ctx, session.URL, enc, frames)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		CameraID:        cameraID,
+		Codec:           session.Codec,
+		SampleRate:      enc.SampleRate(),
+		DurationSeconds: audio.Duration().Seconds(),
+		StreamStats:     stats,
+	}, nil
+}
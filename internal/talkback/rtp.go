@@ -0,0 +1,77 @@
+package talkback
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/url"
+	"time"
+)
+
+// StreamStats reports what was sent to the camera
+type StreamStats struct {
+	Packets int `json:"packets"`
+	Bytes   int `json:"bytes"`
+}
+
+// Stream sends frames as RTP packets to an rtp://host:port URL, pacing them
+// at FrameDuration so the camera's jitter buffer does not overflow
+func Stream(ctx context.Context, rawURL string, enc Encoder, frames []Frame) (StreamStats, error) {
+	var stats StreamStats
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return stats, fmt.Errorf("invalid talkback URL: %w", err)
+	}
+	if u.Scheme != "rtp" && u.Scheme != "udp" {
+		return stats, fmt.Errorf("unsupported talkback URL scheme %q", u.Scheme)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", u.Host)
+	if err != nil {
+		return stats, fmt.Errorf("failed to connect to %s: %w", u.Host, err)
+	}
+	defer conn.Close()
+
+	seq := uint16(rand.Uint32())
+	timestamp := rand.Uint32()
+	ssrc := rand.Uint32()
+
+	ticker := time.NewTicker(FrameDuration)
+	defer ticker.Stop()
+	for i, frame := range frames {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return stats, ctx.Err()
+			case <-ticker.C:
+			}
+		}
+		packet := rtpPacket(enc.PayloadType(), i == 0, seq, timestamp, ssrc, frame.Payload)
+		if _, err := conn.Write(packet); err != nil {
+			return stats, fmt.Errorf("failed to send RTP packet: %w", err)
+		}
+		stats.Packets++
+		stats.Bytes += len(packet)
+		seq++
+		timestamp += frame.Ticks
+	}
+	return stats, nil
+}
+
+// rtpPacket builds an RTP packet (RFC 3550) with no CSRCs or extensions. The
+// marker bit flags the start of a talkspurt.
+func rtpPacket(payloadType uint8, marker bool, seq uint16, timestamp, ssrc uint32, payload []byte) []byte {
+	packet := make([]byte, 12, 12+len(payload))
+	packet[0] = 0x80
+	packet[1] = payloadType & 0x7F
+	if marker {
+		packet[1] |= 0x80
+	}
+	binary.BigEndian.PutUint16(packet[2:], seq)
+	binary.BigEndian.PutUint32(packet[4:], timestamp)
+	binary.BigEndian.PutUint32(packet[8:], ssrc)
+	return append(packet, payload...)
+}
@@ -0,0 +1,238 @@
+package talkback
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func TestDecodeWAV(t *testing.T) {
+	in := &Audio{SampleRate: 16000, Samples: []int16{0, 1000, -1000, 32767}}
+	out, err := DecodeWAV(EncodeWAV(in))
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if out.SampleRate != 16000 || len(out.Samples) != 4 || out.Samples[2] != -1000 {
+		t.Errorf("round trip mismatch: %+v", out)
+	}
+
+	// 8-bit stereo is mixed down to 16-bit mono
+	stereo := EncodeWAV(&Audio{SampleRate: 8000})
+	binary.LittleEndian.PutUint16(stereo[22:], 2)
+	binary.LittleEndian.PutUint16(stereo[34:], 8)
+	stereo = append(stereo, 255, 1)
+	binary.LittleEndian.PutUint32(stereo[40:], 2)
+	out, err = DecodeWAV(stereo)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(out.Samples) != 1 || out.Samples[0] != 0 {
+		t.Errorf("unexpected downmix: %+v", out.Samples)
+	}
+
+	if _, err := DecodeWAV([]byte("not audio")); err == nil {
+		t.Error("expected an error for a non-WAV file")
+	}
+}
+
+func TestResample(t *testing.T) {
+	a := &Audio{SampleRate: 16000, Samples: make([]int16, 1600)}
+	if got := a.Resample(8000); len(got.Samples) != 800 || got.Duration() != 100*time.Millisecond {
+		t.Errorf("unexpected resample: %d samples, %s", len(got.Samples), got.Duration())
+	}
+}
+
+func TestG711(t *testing.T) {
+	cases := []struct {
+		sample    int16
+		ulaw, alw byte
+	}{{0, 0xFF, 0xD5}, {32767, 0x80, 0xAA}, {-32768, 0x00, 0x2A}}
+	for _, c := range cases {
+		if got := linearToULaw(c.sample); got != c.ulaw {
+			t.Errorf("ulaw(%d) = %#x, want %#x", c.sample, got, c.ulaw)
+		}
+		if got := linearToALaw(c.sample); got != c.alw {
+			t.Errorf("alaw(%d) = %#x, want %#x", c.sample, got, c.alw)
+		}
+	}
+}
+
+func oggPage(lacing []byte, body []byte) []byte {
+	page := append([]byte("OggS"), make([]byte, 22)...)
+	page = append(page, byte(len(lacing)))
+	page = append(page, lacing...)
+	return append(page, body...)
+}
+
+func TestOggPackets(t *testing.T) {
+	long := make([]byte, 265)
+	data := append(oggPage([]byte{8}, []byte("OpusHead")), oggPage([]byte{255}, long[:255])...)
+	data = append(data, oggPage([]byte{10, 3}, append(long[255:], 'a', 'b', 'c'))...)
+
+	packets, err := oggPackets(data)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(packets) != 3 || len(packets[1]) != 265 || string(packets[2]) != "abc" {
+		t.Errorf("unexpected packets: %d", len(packets))
+	}
+	if _, err := oggPackets([]byte("junk")); err == nil {
+		t.Error("expected an error for invalid data")
+	}
+}
+
+type fakeClient struct {
+	session unifi.ProtectTalkbackSession
+}
+
+func (f fakeClient) CreateTalkbackSession(ctx context.Context, cameraID string) (*unifi.ProtectTalkbackSession, error) {
+	return &f.session, nil
+}
+
+func TestPlayStreamsRTP(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	player := NewPlayer(fakeClient{unifi.ProtectTalkbackSession{
+		URL: "rtp://" + listener.LocalAddr().String(), Codec: "pcmu", SamplingRate: 8000, BitsPerSample: 16,
+	}}, "", "")
+
+	// 100ms at 16kHz resamples to five 20ms PCMU frames
+	audio := &Audio{SampleRate: 16000, Samples: make([]int16, 1600)}
+	result, err := player.Play(context.Background(), "cam1", audio)
+	if err != nil {
+		t.Fatalf("play failed: %v", err)
+	}
+	if result.Packets != 5 {
+		t.Fatalf("expected 5 packets, got %d", result.Packets)
+	}
+
+	listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 1500)
+	var lastSeq uint16
+	var lastTS uint32
+	for i := 0; i < 5; i++ {
+		n, _, err := listener.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("packet %d not received: %v", i, err)
+		}
+		if n != 12+160 || buf[0] != 0x80 || buf[1]&0x7F != payloadTypePCMU {
+			t.Fatalf("packet %d: unexpected header %x, length %d", i, buf[:2], n)
+		}
+		if marker := buf[1]&0x80 != 0; marker != (i == 0) {
+			t.Errorf("packet %d: marker %v", i, marker)
+		}
+		seq := binary.BigEndian.Uint16(buf[2:])
+		ts := binary.BigEndian.Uint32(buf[4:])
+		if i > 0 && (seq != lastSeq+1 || ts != lastTS+160) {
+			t.Errorf("packet %d: seq %d ts %d after seq %d ts %d", i, seq, ts, lastSeq, lastTS)
+		}
+		if buf[12] != 0xFF {
+			t.Errorf("packet %d: expected mu-law silence, got %#x", i, buf[12])
+		}
+		lastSeq, lastTS = seq, ts
+	}
+}
+
+// opusFrame returns the duration and frame count code from an Opus packet's
+// TOC byte (RFC 6716 section 3.1)
+func opusFrame(toc byte) (time.Duration, byte) {
+	config := toc >> 3
+	var durations []time.Duration
+	switch {
+	case config < 12: // SILK
+		durations = []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 60 * time.Millisecond}
+	case config < 16: // Hybrid
+		durations = []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}
+	default: // CELT
+		durations = []time.Duration{2500 * time.Microsecond, 5 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond}
+	}
+	return durations[int(config)%len(durations)], toc & 0x03
+}
+
+func TestPlayStreamsOpus(t *testing.T) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		t.Skip("ffmpeg is not on PATH")
+	}
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	player := NewPlayer(fakeClient{unifi.ProtectTalkbackSession{
+		URL: "rtp://" + listener.LocalAddr().String(), Codec: "opus", SamplingRate: 24000, BitsPerSample: 16,
+	}}, "", "")
+
+	// 200ms of a 440Hz tone
+	audio := &Audio{SampleRate: 24000, Samples: make([]int16, 4800)}
+	for i := range audio.Samples {
+		audio.Samples[i] = int16(8000 * math.Sin(2*math.Pi*440*float64(i)/24000))
+	}
+	result, err := player.Play(context.Background(), "cam1", audio)
+	if err != nil {
+		t.Fatalf("play failed: %v", err)
+	}
+	// The encoder's pre-skip may add a frame
+	if result.Packets < 10 {
+		t.Fatalf("expected at least 10 packets, got %d", result.Packets)
+	}
+
+	listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 1500)
+	var lastTS uint32
+	for i := 0; i < result.Packets; i++ {
+		n, _, err := listener.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("packet %d not received: %v", i, err)
+		}
+		if n <= 12 || buf[1]&0x7F != payloadTypeDynamic {
+			t.Fatalf("packet %d: unexpected header %x, length %d", i, buf[:2], n)
+		}
+		ts := binary.BigEndian.Uint32(buf[4:])
+		// Opus RTP uses a 48kHz clock whatever the input rate
+		if i > 0 && ts != lastTS+960 {
+			t.Errorf("packet %d: ts %d after %d, expected a step of 960", i, ts, lastTS)
+		}
+		lastTS = ts
+
+		payload := buf[12:n]
+		if bytes.HasPrefix(payload, []byte("Opus")) || bytes.HasPrefix(payload, []byte("OggS")) {
+			t.Fatalf("packet %d: expected an Opus frame, got a container header", i)
+		}
+		if duration, code := opusFrame(payload[0]); duration != FrameDuration || code != 0 {
+			t.Errorf("packet %d: expected one 20ms Opus frame, got TOC %#x (%s, code %d)", i, payload[0], duration, code)
+		}
+	}
+}
+
+func TestTTSCommand(t *testing.T) {
+	dir := t.TempDir()
+	fixture := filepath.Join(dir, "hello.wav")
+	if err := os.WriteFile(fixture, EncodeWAV(&Audio{SampleRate: 22050, Samples: make([]int16, 2205)}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	audio, err := TTS{Command: "cp " + fixture + " {output}"}.Synthesize(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("synthesize failed: %v", err)
+	}
+	if audio.Duration() != 100*time.Millisecond {
+		t.Errorf("unexpected duration %s", audio.Duration())
+	}
+	if _, err := (TTS{}).Synthesize(context.Background(), "hello"); err == nil {
+		t.Error("expected an error without a command")
+	}
+}
@@ -0,0 +1,69 @@
+package talkback
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// TTS synthesizes speech by running a local command. Command is split on
+// whitespace; the {text} argument is replaced by the text to speak and
+// {output} by a temporary WAV path. Without {output} the command must write
+// the WAV to stdout. For example: espeak-ng -w {output} {text}
+type TTS struct {
+	Command string
+}
+
+// Enabled reports whether a TTS command is configured
+func (t TTS) Enabled() bool {
+	return strings.TrimSpace(t.Command) != ""
+}
+
+// Synthesize runs the command and decodes the WAV it produces
+func (t TTS) Synthesize(ctx context.Context, text string) (*Audio, error) {
+	fields := strings.Fields(t.Command)
+	if len(fields) == 0 {
+		return nil, errors.New("no text to speech command is configured")
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("text is required")
+	}
+
+	dir, err := os.MkdirTemp("", "talkback-tts")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "speech.wav")
+
+	usesOutput := false
+	args := make([]string, 0, len(fields)-1)
+	for _, arg := range fields[1:] {
+		if strings.Contains(arg, "{output}") {
+			usesOutput = true
+		}
+		arg = strings.ReplaceAll(arg, "{output}", output)
+		args = append(args, strings.ReplaceAll(arg, "{text}", text))
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, fields[0], args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("text to speech command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	data := stdout.Bytes()
+	if usesOutput {
+		if data, err = os.ReadFile(output); err != nil {
+			return nil, fmt.Errorf("text to speech command wrote no output: %w", err)
+		}
+	}
+	return DecodeWAV(data)
+}
@@ -0,0 +1,219 @@
+// Package telemetry records sensor readings over time and answers trend
+// queries over the recorded history
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/store"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// Retention of recorded history. Samples are kept at full resolution for
+// RawRetention, then merged into DownsampleBucket wide points until they are
+// older than Retention.
+const (
+	RawRetention     = 48 * time.Hour
+	DownsampleBucket = time.Hour
+	Retention        = 90 * 24 * time.Hour
+)
+
+// DefaultInterval is how often sensors are polled when no interval is given
+const DefaultInterval = 5 * time.Minute
+
+// saveInterval is how often new samples are written to disk
+const saveInterval = 30 * time.Minute
+
+// Client is the subset of the Protect client the sampler polls
+type Client interface {
+	GetSensors(ctx context.Context) ([]unifi.ProtectSensor, error)
+}
+
+// Sampler periodically records sensor readings to a local store
+type Sampler struct {
+	client   Client
+	interval time.Duration
+	path     string
+	mu       sync.Mutex
+	series   map[string]*Series
+	dirty    bool
+	logger   *logrus.Entry
+}
+
+// New loads recorded history from dataDir
+func New(client Client, dataDir string, interval time.Duration) (*Sampler, error) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	s := &Sampler{
+		client:   client,
+		interval: interval,
+		path:     filepath.Join(dataDir, "telemetry", "sensors.json"),
+		series:   map[string]*Series{},
+		logger:   logrus.WithField("component", "Telemetry"),
+	}
+	if err := store.Load(s.path, &s.series); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Run polls sensors until ctx is cancelled, saving the history every
+// saveInterval and on shutdown
+func (s *Sampler) Run(ctx context.Context) {
+	s.logger.WithField("interval", s.interval).Info("Sensor sampler started")
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	saveTicker := time.NewTicker(saveInterval)
+	defer saveTicker.Stop()
+
+	s.poll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			s.save()
+			return
+		case <-ticker.C:
+			s.poll(ctx)
+		case <-saveTicker.C:
+			s.save()
+		}
+	}
+}
+
+func (s *Sampler) poll(ctx context.Context) {
+	if err := s.Poll(ctx); err != nil {
+		s.logger.WithError(err).Warn("Failed to sample sensors")
+	}
+}
+
+func (s *Sampler) save() {
+	if err := s.Save(); err != nil {
+		s.logger.WithError(err).Warn("Failed to save sensor history")
+	}
+}
+
+// Poll records one sample from every sensor and downsamples old history. The
+// history is written to disk by Save.
+func (s *Sampler) Poll(ctx context.Context) error {
+	sensors, err := s.client.GetSensors(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range sensors {
+		s.Record(&sensors[i], now)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, series := range s.series {
+		series.downsample(now.Add(-RawRetention), now.Add(-Retention), DownsampleBucket)
+	}
+	return nil
+}
+
+// Save persists the history if it changed
+func (s *Sampler) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+	if err := store.Save(s.path, s.series); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// Record adds a sample of the sensor's current readings
+func (s *Sampler) Record(sensor *unifi.ProtectSensor, at time.Time) {
+	values := map[string]Stat{}
+	for _, r := range sensor.Readings() {
+		if r.Value != nil {
+			values[r.Metric] = newStat(*r.Value)
+		}
+	}
+	if sensor.BatteryStatus != nil && sensor.BatteryStatus.Percentage != nil {
+		values["battery"] = newStat(*sensor.BatteryStatus.Percentage)
+	}
+	if len(values) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, ok := s.series[sensor.ID]
+	if !ok {
+		series = &Series{SensorID: sensor.ID}
+		s.series[sensor.ID] = series
+	}
+	if sensor.Name != "" {
+		series.Name = sensor.Name
+	}
+	series.Points = append(series.Points, Point{Time: at, Values: values})
+	s.dirty = true
+	if n := len(series.Points); n > 1 && at.Before(series.Points[n-2].Time) {
+		series.sortPoints()
+	}
+}
+
+// SensorInfo describes a recorded sensor
+type SensorInfo struct {
+	SensorID string     `json:"sensor_id"`
+	Name     string     `json:"name"`
+	Points   int        `json:"points"`
+	Since    *time.Time `json:"since,omitempty"`
+}
+
+// Sensors lists the sensors with recorded history
+func (s *Sampler) Sensors() []SensorInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]SensorInfo, 0, len(s.series))
+	for _, series := range s.series {
+		info := SensorInfo{SensorID: series.SensorID, Name: series.Name, Points: len(series.Points)}
+		if len(series.Points) > 0 {
+			since := series.Points[0].Time
+			info.Since = &since
+		}
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Series returns a copy of the history for a sensor, looked up by ID or by
+// case-insensitive name
+func (s *Sampler) Series(sensor string) (*Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found, ok := s.series[sensor]
+	if !ok {
+		for _, series := range s.series {
+			if strings.EqualFold(series.Name, sensor) {
+				found = series
+				break
+			}
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no history recorded for sensor %s", sensor)
+	}
+	c := &Series{SensorID: found.SensorID, Name: found.Name}
+	c.Points = append(c.Points, found.Points...)
+	return c, nil
+}
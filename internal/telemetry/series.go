@@ -0,0 +1,222 @@
+package telemetry
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Metrics recorded for each sensor
+var Metrics = []string{"temperature", "humidity", "light", "battery"}
+
+// Units for each metric
+var Units = map[string]string{
+	"temperature": "°C",
+	"humidity":    "%",
+	"light":       "lux",
+	"battery":     "%",
+}
+
+// Stat aggregates one or more samples of a metric
+type Stat struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Sum   float64 `json:"sum"`
+	Count int     `json:"count"`
+}
+
+func newStat(v float64) Stat {
+	return Stat{Min: v, Max: v, Sum: v, Count: 1}
+}
+
+func (s *Stat) merge(o Stat) {
+	if o.Count == 0 {
+		return
+	}
+	if s.Count == 0 {
+		*s = o
+		return
+	}
+	s.Min = math.Min(s.Min, o.Min)
+	s.Max = math.Max(s.Max, o.Max)
+	s.Sum += o.Sum
+	s.Count += o.Count
+}
+
+// Avg returns the mean of the aggregated samples
+func (s Stat) Avg() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// Point is a raw sample, or an aggregate of samples once downsampled
+type Point struct {
+	Time   time.Time       `json:"t"`
+	Values map[string]Stat `json:"v"`
+}
+
+// Series is the recorded history of one sensor, oldest point first
+type Series struct {
+	SensorID string  `json:"sensor_id"`
+	Name     string  `json:"name"`
+	Points   []Point `json:"points"`
+}
+
+// downsample merges points older than rawCutoff into buckets of the given
+// width and drops points older than dropCutoff
+func (s *Series) downsample(rawCutoff, dropCutoff time.Time, bucket time.Duration) {
+	var out []Point
+	var current *Point
+	for _, p := range s.Points {
+		if p.Time.Before(dropCutoff) {
+			continue
+		}
+		if !p.Time.Before(rawCutoff) {
+			out = append(out, p)
+			continue
+		}
+		start := p.Time.Truncate(bucket)
+		if current == nil || !current.Time.Equal(start) {
+			out = append(out, Point{Time: start, Values: map[string]Stat{}})
+			current = &out[len(out)-1]
+		}
+		for metric, stat := range p.Values {
+			merged := current.Values[metric]
+			merged.merge(stat)
+			current.Values[metric] = merged
+		}
+	}
+	s.Points = out
+}
+
+// Summary describes a metric over a window
+type Summary struct {
+	Metric  string     `json:"metric"`
+	Unit    string     `json:"unit"`
+	Min     *float64   `json:"min"`
+	Max     *float64   `json:"max"`
+	Avg     *float64   `json:"avg"`
+	Samples int        `json:"samples"`
+	MinAt   *time.Time `json:"min_at,omitempty"`
+	MaxAt   *time.Time `json:"max_at,omitempty"`
+}
+
+// Summarize returns min, max and average of a metric between from and to
+func (s *Series) Summarize(metric string, from, to time.Time) Summary {
+	summary := Summary{Metric: metric, Unit: Units[metric]}
+	var total Stat
+	var minAt, maxAt time.Time
+	for _, p := range s.Points {
+		if p.Time.Before(from) || p.Time.After(to) {
+			continue
+		}
+		stat, ok := p.Values[metric]
+		if !ok {
+			continue
+		}
+		if total.Count == 0 || stat.Min < total.Min {
+			minAt = p.Time
+		}
+		if total.Count == 0 || stat.Max > total.Max {
+			maxAt = p.Time
+		}
+		total.merge(stat)
+	}
+	if total.Count == 0 {
+		return summary
+	}
+	minV, maxV, avg := total.Min, total.Max, round(total.Avg())
+	summary.Min, summary.Max, summary.Avg = &minV, &maxV, &avg
+	summary.Samples = total.Count
+	summary.MinAt, summary.MaxAt = &minAt, &maxAt
+	return summary
+}
+
+// Bucket is one point of a series resampled to a fixed width
+type Bucket struct {
+	Start time.Time `json:"start"`
+	Avg   *float64  `json:"avg"`
+	Min   *float64  `json:"min"`
+	Max   *float64  `json:"max"`
+}
+
+// Resample splits the window into n equal buckets of a metric. Buckets with no
+// samples have nil values.
+func (s *Series) Resample(metric string, from, to time.Time, n int) ([]Bucket, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("points must be at least 1")
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("window end must be after its start")
+	}
+	width := to.Sub(from) / time.Duration(n)
+	if width <= 0 {
+		return nil, fmt.Errorf("window is too short for %d points", n)
+	}
+
+	stats := make([]Stat, n)
+	for _, p := range s.Points {
+		if p.Time.Before(from) || !p.Time.Before(to) {
+			continue
+		}
+		stat, ok := p.Values[metric]
+		if !ok {
+			continue
+		}
+		i := int(p.Time.Sub(from) / width)
+		if i >= n {
+			i = n - 1
+		}
+		stats[i].merge(stat)
+	}
+
+	buckets := make([]Bucket, n)
+	for i, stat := range stats {
+		buckets[i].Start = from.Add(time.Duration(i) * width)
+		if stat.Count > 0 {
+			avg, minV, maxV := round(stat.Avg()), stat.Min, stat.Max
+			buckets[i].Avg, buckets[i].Min, buckets[i].Max = &avg, &minV, &maxV
+		}
+	}
+	return buckets, nil
+}
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders bucket averages as a unicode sparkline, with a space for
+// empty buckets
+func Sparkline(buckets []Bucket) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, b := range buckets {
+		if b.Avg != nil {
+			lo = math.Min(lo, *b.Avg)
+			hi = math.Max(hi, *b.Avg)
+		}
+	}
+	var sb strings.Builder
+	for _, b := range buckets {
+		switch {
+		case b.Avg == nil:
+			sb.WriteRune(' ')
+		case hi == lo:
+			sb.WriteRune(sparkTicks[len(sparkTicks)/2])
+		default:
+			i := int((*b.Avg - lo) / (hi - lo) * float64(len(sparkTicks)-1))
+			sb.WriteRune(sparkTicks[i])
+		}
+	}
+	return sb.String()
+}
+
+// sortPoints keeps points in time order after appends from concurrent pollers
+func (s *Series) sortPoints() {
+	sort.SliceStable(s.Points, func(i, j int) bool { return s.Points[i].Time.Before(s.Points[j].Time) })
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
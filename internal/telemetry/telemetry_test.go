@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+type fakeClient struct {
+	sensors []unifi.ProtectSensor
+}
+
+func (f *fakeClient) GetSensors(ctx context.Context) ([]unifi.ProtectSensor, error) {
+	return f.sensors, nil
+}
+
+func sensorWithTemperature(v float64) unifi.ProtectSensor {
+	return unifi.ProtectSensor{
+		ID:    "s1",
+		Name:  "Garage",
+		Stats: &unifi.ProtectSensorStats{Temperature: unifi.ProtectSensorStat{Value: &v, Status: "safe"}},
+	}
+}
+
+func TestRecordAndSummarize(t *testing.T) {
+	s, err := New(&fakeClient{}, t.TempDir(), time.Minute)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	start := time.Date(2026, 1, 10, 20, 0, 0, 0, time.UTC)
+	for i, v := range []float64{8, 4, 2, 5} {
+		sensor := sensorWithTemperature(v)
+		s.Record(&sensor, start.Add(time.Duration(i)*time.Hour))
+	}
+
+	series, err := s.Series("garage")
+	if err != nil {
+		t.Fatalf("Series by name failed: %v", err)
+	}
+	summary := series.Summarize("temperature", start, start.Add(4*time.Hour))
+	if summary.Samples != 4 || *summary.Min != 2 || *summary.Max != 8 || *summary.Avg != 4.75 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if !summary.MinAt.Equal(start.Add(2 * time.Hour)) {
+		t.Errorf("expected min at 22:00, got %v", summary.MinAt)
+	}
+	if empty := series.Summarize("humidity", start, start.Add(4*time.Hour)); empty.Samples != 0 || empty.Min != nil {
+		t.Errorf("expected no humidity samples, got %+v", empty)
+	}
+
+	buckets, err := series.Resample("temperature", start, start.Add(4*time.Hour), 4)
+	if err != nil {
+		t.Fatalf("Resample failed: %v", err)
+	}
+	if spark := Sparkline(buckets); spark != "█▃▁▄" {
+		t.Errorf("unexpected sparkline %q", spark)
+	}
+}
+
+func TestDownsampleKeepsExtremes(t *testing.T) {
+	series := &Series{SensorID: "s1"}
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i, v := range []float64{3, 9, 6} {
+		series.Points = append(series.Points, Point{Time: base.Add(time.Duration(i) * 10 * time.Minute), Values: map[string]Stat{"temperature": newStat(v)}})
+	}
+	recent := base.Add(72 * time.Hour)
+	series.Points = append(series.Points, Point{Time: recent, Values: map[string]Stat{"temperature": newStat(1)}})
+
+	series.downsample(recent.Add(-RawRetention), recent.Add(-Retention), DownsampleBucket)
+	if len(series.Points) != 2 {
+		t.Fatalf("expected one bucket and one raw point, got %d", len(series.Points))
+	}
+	stat := series.Points[0].Values["temperature"]
+	if stat.Min != 3 || stat.Max != 9 || stat.Count != 3 || stat.Avg() != 6 {
+		t.Errorf("unexpected bucket: %+v", stat)
+	}
+
+	series.downsample(recent.Add(-RawRetention), recent.Add(time.Hour), DownsampleBucket)
+	if len(series.Points) != 0 {
+		t.Errorf("expected all points dropped, got %d", len(series.Points))
+	}
+}
+
+func TestSavePersists(t *testing.T) {
+	dir := t.TempDir()
+	client := &fakeClient{sensors: []unifi.ProtectSensor{sensorWithTemperature(12)}}
+	s, err := New(client, dir, time.Minute)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := s.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "telemetry", "sensors.json")); !os.IsNotExist(err) {
+		t.Fatalf("Poll wrote history before Save: %v", err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reloaded, err := New(client, dir, time.Minute)
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if sensors := reloaded.Sensors(); len(sensors) != 1 || sensors[0].Points != 1 {
+		t.Fatalf("history not persisted: %+v", sensors)
+	}
+}
@@ -0,0 +1,445 @@
+// Package toolgen derives MCP tool definitions from the Protect integration
+// API description and renders them as Go source, so tool input schemas carry
+// the spec's required fields, enums, limits and nested objects
+package toolgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/openapi"
+)
+
+// BodyProperty is the input property that holds an operation's request body
+const BodyProperty = "body"
+
+// Tool is the definition derived from one operation
+type Tool struct {
+	OperationID string
+	Name        string
+	Method      string
+	Path        string
+	Description string
+	PathParams  []string
+	QueryParams []string
+	Properties  map[string]any
+	Required    []string
+}
+
+// Unsupported explains why an operation cannot be called as a JSON tool, or
+// returns "" when it can
+func Unsupported(ep *openapi.Endpoint) string {
+	if strings.HasPrefix(ep.Path, "/v1/subscribe/") {
+		return "it is a WebSocket subscription"
+	}
+	if ep.RequestBody != nil {
+		if _, ok := ep.RequestBody.Content["application/json"]; !ok {
+			return "it does not take a JSON body; use upload_asset_file"
+		}
+	}
+	return ""
+}
+
+// Tools derives a definition for every supported operation, sorted by
+// operation ID. Path and query parameters become properties named as in the
+// spec, and a JSON request body becomes the body property.
+func Tools(spec *openapi.Spec, quirks ...openapi.Quirk) ([]Tool, error) {
+	checker := openapi.NewChecker(spec, quirks...)
+	var tools []Tool
+	for _, ep := range spec.Endpoints() {
+		if Unsupported(&ep) != "" {
+			continue
+		}
+		tool := Tool{
+			OperationID: ep.OperationID,
+			Name:        snakeCase(ep.OperationID),
+			Method:      ep.Method,
+			Path:        ep.Path,
+			Description: ep.Description,
+			Properties:  map[string]any{},
+		}
+		if tool.Description == "" {
+			tool.Description = ep.Summary
+		}
+		c := converter{spec: spec, visiting: map[string]bool{}}
+		for _, p := range ep.Parameters {
+			if p.In != "path" && p.In != "query" {
+				continue
+			}
+			prop, err := c.convert(p.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("%s parameter %s: %w", ep.OperationID, p.Name, err)
+			}
+			if p.Description != "" {
+				prop["description"] = p.Description
+			}
+			tool.Properties[p.Name] = prop
+			if p.In == "path" {
+				tool.PathParams = append(tool.PathParams, p.Name)
+			} else {
+				tool.QueryParams = append(tool.QueryParams, p.Name)
+			}
+			// Path parameters are always required
+			if p.Required || p.In == "path" {
+				tool.Required = append(tool.Required, p.Name)
+			}
+		}
+		if ep.RequestBody != nil {
+			body, err := c.convert(ep.RequestBody.Content["application/json"].Schema)
+			if err != nil {
+				return nil, fmt.Errorf("%s body: %w", ep.OperationID, err)
+			}
+			applyQuirk(body, checker.RequestOptions(&ep))
+			if _, ok := body["description"]; !ok {
+				body["description"] = "Request body"
+			}
+			tool.Properties[BodyProperty] = body
+			if ep.RequestBody.Required {
+				tool.Required = append(tool.Required, BodyProperty)
+			}
+		}
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].OperationID < tools[j].OperationID })
+	return tools, nil
+}
+
+// applyQuirk drops the top-level required fields a quirk relaxes
+func applyQuirk(schema map[string]any, opts openapi.Options) {
+	required, _ := schema["required"].([]any)
+	if opts.Partial || len(required) == 0 {
+		delete(schema, "required")
+		return
+	}
+	var kept []any
+	for _, name := range required {
+		if !slices.Contains(opts.IgnoreRequired, name.(string)) {
+			kept = append(kept, name)
+		}
+	}
+	if len(kept) == 0 {
+		delete(schema, "required")
+		return
+	}
+	schema["required"] = kept
+}
+
+// converter turns spec schemas into self-contained JSON Schema values,
+// inlining references
+type converter struct {
+	spec     *openapi.Spec
+	visiting map[string]bool
+}
+
+func (c *converter) convert(s *openapi.Schema) (map[string]any, error) {
+	if s == nil {
+		return map[string]any{}, nil
+	}
+	if s.Ref != "" {
+		if c.visiting[s.Ref] {
+			return nil, fmt.Errorf("recursive reference %s", s.Ref)
+		}
+		resolved, err := c.spec.Resolve(s)
+		if err != nil {
+			return nil, err
+		}
+		c.visiting[s.Ref] = true
+		defer delete(c.visiting, s.Ref)
+		return c.convert(resolved)
+	}
+
+	out := map[string]any{}
+	if s.Description != "" {
+		out["description"] = s.Description
+	}
+	switch len(s.Type) {
+	case 0:
+	case 1:
+		out["type"] = s.Type[0]
+	default:
+		types := make([]any, len(s.Type))
+		for i, t := range s.Type {
+			types[i] = t
+		}
+		out["type"] = types
+	}
+	if s.Format != "" {
+		out["format"] = s.Format
+	}
+	if len(s.Enum) > 0 {
+		out["enum"] = append([]any(nil), s.Enum...)
+	}
+	if len(s.Const) > 0 {
+		var v any
+		if err := json.Unmarshal(s.Const, &v); err != nil {
+			return nil, fmt.Errorf("invalid const: %w", err)
+		}
+		out["const"] = v
+	}
+	setNumber(out, "minimum", s.Minimum)
+	setNumber(out, "maximum", s.Maximum)
+	setNumber(out, "exclusiveMinimum", s.ExclusiveMinimum)
+	setNumber(out, "exclusiveMaximum", s.ExclusiveMaximum)
+	setInt(out, "minItems", s.MinItems)
+	setInt(out, "maxItems", s.MaxItems)
+	setInt(out, "minLength", s.MinLength)
+	setInt(out, "maxLength", s.MaxLength)
+
+	if s.Items != nil {
+		items, err := c.convert(s.Items)
+		if err != nil {
+			return nil, err
+		}
+		out["items"] = items
+	}
+	if len(s.Properties) > 0 {
+		props := map[string]any{}
+		for name, prop := range s.Properties {
+			converted, err := c.convert(prop)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			props[name] = converted
+		}
+		out["properties"] = props
+	}
+	if len(s.Required) > 0 {
+		required := make([]any, len(s.Required))
+		for i, name := range s.Required {
+			required[i] = name
+		}
+		out["required"] = required
+	}
+	if s.AdditionalProperties != nil {
+		if !s.AdditionalProperties.Allowed {
+			out["additionalProperties"] = false
+		} else if s.AdditionalProperties.Schema != nil {
+			additional, err := c.convert(s.AdditionalProperties.Schema)
+			if err != nil {
+				return nil, err
+			}
+			out["additionalProperties"] = additional
+		}
+	}
+
+	// allOf is folded into one object, which MCP clients handle better
+	for _, part := range s.AllOf {
+		converted, err := c.convert(part)
+		if err != nil {
+			return nil, err
+		}
+		merge(out, converted)
+	}
+	if len(s.OneOf) > 0 {
+		if err := c.alternatives(out, "oneOf", s.OneOf); err != nil {
+			return nil, err
+		}
+	}
+	if len(s.AnyOf) > 0 {
+		if err := c.alternatives(out, "anyOf", s.AnyOf); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// alternatives simplifies oneOf and anyOf: a null branch makes the type
+// nullable, const branches become an enum, and a single remaining branch is
+// merged into the schema
+func (c *converter) alternatives(out map[string]any, keyword string, branches []*openapi.Schema) error {
+	nullable := false
+	var kept []map[string]any
+	for _, branch := range branches {
+		converted, err := c.convert(branch)
+		if err != nil {
+			return err
+		}
+		if converted["type"] == "null" && len(converted) == 1 {
+			nullable = true
+			continue
+		}
+		kept = append(kept, converted)
+	}
+
+	consts := make([]any, 0, len(kept))
+	for _, branch := range kept {
+		if v, ok := branch["const"]; ok {
+			consts = append(consts, v)
+		}
+	}
+	switch {
+	case len(kept) == 1:
+		merge(out, kept[0])
+	case len(kept) > 1 && len(consts) == len(kept):
+		out["enum"] = consts
+	case len(kept) > 1:
+		list := make([]any, len(kept))
+		for i, branch := range kept {
+			list[i] = branch
+		}
+		out[keyword] = list
+	}
+	if nullable {
+		switch t := out["type"].(type) {
+		case string:
+			out["type"] = []any{t, "null"}
+		case []any:
+			out["type"] = append(t, "null")
+		}
+	}
+	return nil
+}
+
+// merge copies src into dst, combining properties and required lists and
+// keeping dst's value for any other keyword both define
+func merge(dst, src map[string]any) {
+	for key, value := range src {
+		switch key {
+		case "properties":
+			props, _ := dst[key].(map[string]any)
+			if props == nil {
+				props = map[string]any{}
+			}
+			for name, prop := range value.(map[string]any) {
+				props[name] = prop
+			}
+			dst[key] = props
+		case "required":
+			existing, _ := dst[key].([]any)
+			for _, name := range value.([]any) {
+				if !slices.Contains(existing, name) {
+					existing = append(existing, name)
+				}
+			}
+			dst[key] = existing
+		default:
+			if _, ok := dst[key]; !ok {
+				dst[key] = value
+			}
+		}
+	}
+}
+
+func setNumber(out map[string]any, key string, v *float64) {
+	if v != nil {
+		out[key] = *v
+	}
+}
+
+func setInt(out map[string]any, key string, v *int) {
+	if v != nil {
+		out[key] = float64(*v)
+	}
+}
+
+// snakeCase turns an operation ID such as getCameraDetails into a tool name
+// such as get_camera_details
+func snakeCase(id string) string {
+	var b strings.Builder
+	for i, r := range id {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Generate renders the tools as Go source declaring specTools in package
+// pkg. The package must declare the specTool type with Tool's fields.
+func Generate(spec *openapi.Spec, pkg string, quirks ...openapi.Quirk) ([]byte, error) {
+	tools, err := Tools(spec, quirks...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by toolgen from the Protect integration API spec %s. DO NOT EDIT.\n\n", spec.Info.Version)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("// specTools are the tool definitions derived from each operation in\n")
+	b.WriteString("// docs/protect_integration.json, sorted by operation ID\n")
+	b.WriteString("var specTools = []specTool{\n")
+	for _, t := range tools {
+		b.WriteString("{\n")
+		fmt.Fprintf(&b, "OperationID: %q,\n", t.OperationID)
+		fmt.Fprintf(&b, "Name: %q,\n", t.Name)
+		fmt.Fprintf(&b, "Method: %q,\n", t.Method)
+		fmt.Fprintf(&b, "Path: %q,\n", t.Path)
+		fmt.Fprintf(&b, "Description: %q,\n", t.Description)
+		if len(t.PathParams) > 0 {
+			fmt.Fprintf(&b, "PathParams: %s,\n", stringsLiteral(t.PathParams))
+		}
+		if len(t.QueryParams) > 0 {
+			fmt.Fprintf(&b, "QueryParams: %s,\n", stringsLiteral(t.QueryParams))
+		}
+		fmt.Fprintf(&b, "Properties: %s,\n", literal(t.Properties))
+		if len(t.Required) > 0 {
+			fmt.Fprintf(&b, "Required: %s,\n", stringsLiteral(t.Required))
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated source: %w", err)
+	}
+	return src, nil
+}
+
+func stringsLiteral(list []string) string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = strconv.Quote(s)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// literal renders a decoded JSON value as a Go expression, with map keys
+// sorted so the output is stable
+func literal(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = literal(item)
+		}
+		return "[]any{" + strings.Join(items, ", ") + "}"
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		b.WriteString("map[string]any{")
+		for _, k := range keys {
+			fmt.Fprintf(&b, "\n%q: %s,", k, literal(v[k]))
+		}
+		if len(keys) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+		return b.String()
+	default:
+		return fmt.Sprintf("%#v", v)
+	}
+}
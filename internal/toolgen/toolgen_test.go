@@ -0,0 +1,101 @@
+package toolgen
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/surrealwolf/unifi-protect-mcp/docs"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/openapi"
+)
+
+func protectTools(t *testing.T) map[string]Tool {
+	t.Helper()
+	spec, err := openapi.Parse(docs.ProtectIntegration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tools, err := Tools(spec, openapi.ProtectQuirks...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := map[string]Tool{}
+	for _, tool := range tools {
+		byID[tool.OperationID] = tool
+	}
+	return byID
+}
+
+// TestGeneratedUpToDate fails when the spec or generator changed without
+// rerunning go generate in internal/mcp
+func TestGeneratedUpToDate(t *testing.T) {
+	spec, err := openapi.Parse(docs.ProtectIntegration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Generate(spec, "mcp", openapi.ProtectQuirks...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("../mcp/spec_tools_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("internal/mcp/spec_tools_gen.go is stale; run go generate ./internal/mcp")
+	}
+}
+
+func TestTools(t *testing.T) {
+	tools := protectTools(t)
+
+	if _, ok := tools["getProtectEventMessages"]; ok {
+		t.Error("expected subscriptions to be left out")
+	}
+	if _, ok := tools["uploadDeviceAssetFile"]; ok {
+		t.Error("expected multipart uploads to be left out")
+	}
+
+	light, ok := tools["patchLightSettings"]
+	if !ok {
+		t.Fatal("expected a patchLightSettings tool")
+	}
+	if light.Name != "patch_light_settings" || light.Method != "PATCH" || light.Path != "/v1/lights/{id}" {
+		t.Errorf("unexpected tool %+v", light)
+	}
+	if !reflect.DeepEqual(light.Required, []string{"id", BodyProperty}) {
+		t.Errorf("expected id and body to be required, got %v", light.Required)
+	}
+	body := light.Properties[BodyProperty].(map[string]any)
+	if body["additionalProperties"] != false {
+		t.Error("expected the body to refuse unknown fields")
+	}
+	lightMode := body["properties"].(map[string]any)["lightModeSettings"].(map[string]any)
+	mode := lightMode["properties"].(map[string]any)["mode"].(map[string]any)
+	if !reflect.DeepEqual(mode["enum"], []any{"always", "motion", "off"}) {
+		t.Errorf("expected the light mode enum to be inlined, got %v", mode)
+	}
+	level := body["properties"].(map[string]any)["lightDeviceSettings"].(map[string]any)["properties"].(map[string]any)["ledLevel"].(map[string]any)
+	if level["minimum"] != float64(1) || level["maximum"] != float64(6) {
+		t.Errorf("expected ledLevel limits, got %v", level)
+	}
+
+	// Partial updates need none of the liveview's fields
+	liveview := tools["patchLiveViewConfiguration"].Properties[BodyProperty].(map[string]any)
+	if _, ok := liveview["required"]; ok {
+		t.Errorf("expected no required fields on a partial update, got %v", liveview["required"])
+	}
+	// The console fills in these fields on create
+	created := tools["createLiveView"].Properties[BodyProperty].(map[string]any)
+	for _, name := range created["required"].([]any) {
+		if name == "id" || name == "modelKey" || name == "owner" {
+			t.Errorf("expected %s to be optional on create", name)
+		}
+	}
+
+	stream := tools["deleteCameraRtspsStream"]
+	if !reflect.DeepEqual(stream.QueryParams, []string{"qualities"}) || !reflect.DeepEqual(stream.Required, []string{"id", "qualities"}) {
+		t.Errorf("expected a required qualities query parameter, got %+v", stream)
+	}
+}
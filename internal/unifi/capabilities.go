@@ -0,0 +1,238 @@
+package unifi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Version is a Protect application version such as 6.2.72
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses a dotted Protect version. Missing minor or patch parts
+// are zero and pre-release or build suffixes ("-beta.3", "+abc") are ignored.
+func ParseVersion(s string) (Version, error) {
+	core := strings.TrimSpace(s)
+	if i := strings.IndexAny(core, "-+ "); i >= 0 {
+		core = core[:i]
+	}
+	parts := strings.Split(core, ".")
+	if core == "" || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid Protect version %q", s)
+	}
+
+	var nums [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid Protect version %q", s)
+		}
+		nums[i] = n
+	}
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}, nil
+}
+
+// String formats the version as major.minor.patch
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Capability is an optional part of the Protect API that not every console
+// serves
+type Capability string
+
+const (
+	CapabilityEvents           Capability = "events"
+	CapabilitySubscribeEvents  Capability = "subscribe_events"
+	CapabilitySubscribeDevices Capability = "subscribe_devices"
+	CapabilitySnapshot         Capability = "snapshot"
+	CapabilityFiles            Capability = "files"
+	CapabilityPTZ              Capability = "ptz"
+	CapabilityTalkback         Capability = "talkback"
+)
+
+// Capabilities lists every capability in the order they are reported
+var Capabilities = []Capability{
+	CapabilityEvents,
+	CapabilitySubscribeEvents,
+	CapabilitySubscribeDevices,
+	CapabilitySnapshot,
+	CapabilityFiles,
+	CapabilityPTZ,
+	CapabilityTalkback,
+}
+
+// capabilityDescriptions name each capability in error messages
+var capabilityDescriptions = map[Capability]string{
+	CapabilityEvents:           "Listing events",
+	CapabilitySubscribeEvents:  "The events subscription",
+	CapabilitySubscribeDevices: "The devices subscription",
+	CapabilitySnapshot:         "Camera snapshots",
+	CapabilityFiles:            "Asset files",
+	CapabilityPTZ:              "PTZ control",
+	CapabilityTalkback:         "Talkback",
+}
+
+// NotServedError is returned for a capability whose endpoint the console has
+// answered with 404. The integration API documentation carries no
+// per-operation availability, so this is learned from the console itself.
+type NotServedError struct {
+	Capability Capability
+	// Connected is the console's version; nil when it has not been detected
+	Connected *Version
+}
+
+func (e *NotServedError) Error() string {
+	description := capabilityDescriptions[e.Capability]
+	if e.Connected == nil {
+		return description + " is not served by this console"
+	}
+	return fmt.Sprintf("%s is not served by this console (Protect %s)", description, e.Connected)
+}
+
+// CapabilityStatus reports whether a console supports a capability
+type CapabilityStatus struct {
+	Capability Capability `json:"capability"`
+	Supported  bool       `json:"supported"`
+	// Reason explains why an unsupported capability is unavailable
+	Reason string `json:"reason,omitempty"`
+}
+
+// CapabilityMatrix reports every capability as far as it is known. A
+// capability counts as supported until the console answers its endpoint
+// with 404.
+func (pc *ProtectClient) CapabilityMatrix() []CapabilityStatus {
+	matrix := make([]CapabilityStatus, 0, len(Capabilities))
+	for _, c := range Capabilities {
+		status := CapabilityStatus{Capability: c, Supported: true}
+		if err := pc.RequireCapability(c); err != nil {
+			status.Supported, status.Reason = false, err.Error()
+		}
+		matrix = append(matrix, status)
+	}
+	return matrix
+}
+
+// DetectVersion reads the console's application version from meta/info and
+// remembers it for capability checks
+func (pc *ProtectClient) DetectVersion(ctx context.Context) (Version, error) {
+	info, err := pc.GetSystemInfo(ctx)
+	if err != nil {
+		return Version{}, fmt.Errorf("failed to detect Protect version: %w", err)
+	}
+	v, err := ParseVersion(info.ApplicationVersion)
+	if err != nil {
+		return Version{}, err
+	}
+
+	pc.mu.Lock()
+	pc.version = &v
+	pc.mu.Unlock()
+
+	pc.logger.WithField("version", v.String()).Info("Detected Unifi Protect version")
+	return v, nil
+}
+
+// Version returns the version found by DetectVersion
+func (pc *ProtectClient) Version() (Version, bool) {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	if pc.version == nil {
+		return Version{}, false
+	}
+	return *pc.version, true
+}
+
+// Supports reports whether the connected console serves c. Capabilities are
+// assumed to be served until the console answers their endpoint with 404.
+func (pc *ProtectClient) Supports(c Capability) bool {
+	return pc.RequireCapability(c) == nil
+}
+
+// RequireCapability returns a NotServedError when the console has already
+// answered c's endpoint with 404
+func (pc *ProtectClient) RequireCapability(c Capability) error {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	if pc.unserved[c] {
+		return &NotServedError{Capability: c, Connected: pc.version}
+	}
+	return nil
+}
+
+// OnUnavailable registers fn to be called once for each capability the
+// console turns out not to serve
+func (pc *ProtectClient) OnUnavailable(fn func(Capability)) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.unavailableHandlers = append(pc.unavailableHandlers, fn)
+}
+
+// ProbeCapabilities asks the console for each optional endpoint that can be
+// read without naming a device, so unserved capabilities are known before
+// the first call. Capabilities under a camera are learned when first used.
+func (pc *ProtectClient) ProbeCapabilities(ctx context.Context) {
+	probes := map[Capability]func() error{
+		CapabilityEvents: func() error {
+			_, err := pc.GetEvents(ctx, 1, 0)
+			return err
+		},
+		CapabilityFiles: func() error {
+			_, err := pc.ListAssetFiles(ctx, AssetFileTypeAnimations)
+			return err
+		},
+		CapabilitySubscribeEvents:  func() error { return pc.ProbeSubscription(ctx, "events") },
+		CapabilitySubscribeDevices: func() error { return pc.ProbeSubscription(ctx, "devices") },
+	}
+	for _, c := range Capabilities {
+		probe, ok := probes[c]
+		if !ok {
+			continue
+		}
+		if err := probe(); err != nil {
+			pc.logger.WithError(err).WithField("capability", c).Debug("Capability probe failed")
+		}
+	}
+}
+
+// unavailable records that the console answered c's endpoint with 404 and
+// builds the error for it
+func (pc *ProtectClient) unavailable(c Capability) error {
+	pc.mu.Lock()
+	if pc.unserved == nil {
+		pc.unserved = map[Capability]bool{}
+	}
+	first := !pc.unserved[c]
+	pc.unserved[c] = true
+	handlers := pc.unavailableHandlers
+	err := &NotServedError{Capability: c, Connected: pc.version}
+	pc.mu.Unlock()
+
+	if first {
+		for _, fn := range handlers {
+			fn(c)
+		}
+	}
+	return err
+}
+
+// unavailableUnderCamera turns a 404 from an endpoint under a camera into a
+// NotServedError when the camera itself exists. Other errors, and a 404 for
+// an unknown camera, are returned unchanged.
+func (pc *ProtectClient) unavailableUnderCamera(ctx context.Context, c Capability, cameraID string, err error) error {
+	var statusErr *statusError
+	if !errors.As(err, &statusErr) || statusErr.status != http.StatusNotFound {
+		return err
+	}
+	if _, camErr := pc.GetCameraDetailed(ctx, cameraID); camErr != nil {
+		return err
+	}
+	return pc.unavailable(c)
+}
@@ -0,0 +1,172 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Chime ring setting ranges
+const (
+	MinChimeRepeatTimes = 1
+	MaxChimeRepeatTimes = 10
+	MaxChimeVolume      = 100
+)
+
+// ProtectRingSettings is how a chime rings for one paired doorbell
+type ProtectRingSettings struct {
+	CameraID    string `json:"cameraId"`
+	RepeatTimes int    `json:"repeatTimes"`
+	RingtoneID  string `json:"ringtoneId"`
+	Volume      int    `json:"volume"`
+}
+
+// RingUpdate changes selected ring settings; nil and empty fields are left as is
+type RingUpdate struct {
+	Volume      *int
+	RepeatTimes *int
+	RingtoneID  string
+}
+
+// Validate checks the update against the ranges the API accepts
+func (u RingUpdate) Validate() error {
+	if u.Volume == nil && u.RepeatTimes == nil && u.RingtoneID == "" {
+		return fmt.Errorf("no ring settings to change")
+	}
+	if u.Volume != nil && (*u.Volume < 0 || *u.Volume > MaxChimeVolume) {
+		return fmt.Errorf("volume must be between 0 and %d", MaxChimeVolume)
+	}
+	if u.RepeatTimes != nil && (*u.RepeatTimes < MinChimeRepeatTimes || *u.RepeatTimes > MaxChimeRepeatTimes) {
+		return fmt.Errorf("repeat times must be between %d and %d", MinChimeRepeatTimes, MaxChimeRepeatTimes)
+	}
+	return nil
+}
+
+// IsPaired reports whether the doorbell camera is paired to the chime
+func (c *ProtectChime) IsPaired(cameraID string) bool {
+	return slices.Contains(c.CameraIDs, cameraID)
+}
+
+// Muted returns the chime's ring settings with every volume set to zero
+func (c *ProtectChime) Muted() []ProtectRingSettings {
+	muted := make([]ProtectRingSettings, len(c.RingSettings))
+	for i, rs := range c.RingSettings {
+		rs.Volume = 0
+		muted[i] = rs
+	}
+	return muted
+}
+
+// GetChime retrieves a single chime as a typed model
+func (pc *ProtectClient) GetChime(ctx context.Context, chimeID string) (*ProtectChime, error) {
+	pc.logger.Debugf("Fetching chime %s", chimeID)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/chimes/%s", pc.baseURL, chimeID)
+
+	var chime ProtectChime
+	if err := pc.makeTypedRequest(ctx, url, &chime); err != nil {
+		return nil, err
+	}
+	return &chime, nil
+}
+
+// PairChimeDoorbell pairs a doorbell camera with a chime
+func (pc *ProtectClient) PairChimeDoorbell(ctx context.Context, chimeID, cameraID string) (*ProtectChime, error) {
+	chime, err := pc.GetChime(ctx, chimeID)
+	if err != nil {
+		return nil, err
+	}
+	if chime.IsPaired(cameraID) {
+		return chime, nil
+	}
+
+	cameraIDs := append(append([]string{}, chime.CameraIDs...), cameraID)
+	if _, err := pc.PatchChime(ctx, chimeID, map[string]interface{}{"cameraIds": cameraIDs}); err != nil {
+		return nil, err
+	}
+	chime.CameraIDs = cameraIDs
+	return chime, nil
+}
+
+// UnpairChimeDoorbell removes a doorbell camera and its ring settings from a chime
+func (pc *ProtectClient) UnpairChimeDoorbell(ctx context.Context, chimeID, cameraID string) (*ProtectChime, error) {
+	chime, err := pc.GetChime(ctx, chimeID)
+	if err != nil {
+		return nil, err
+	}
+	if !chime.IsPaired(cameraID) {
+		return nil, fmt.Errorf("camera %s is not paired with chime %s", cameraID, chimeID)
+	}
+
+	cameraIDs := []string{}
+	for _, id := range chime.CameraIDs {
+		if id != cameraID {
+			cameraIDs = append(cameraIDs, id)
+		}
+	}
+	ringSettings := []ProtectRingSettings{}
+	for _, rs := range chime.RingSettings {
+		if rs.CameraID != cameraID {
+			ringSettings = append(ringSettings, rs)
+		}
+	}
+	if _, err := pc.PatchChime(ctx, chimeID, map[string]interface{}{
+		"cameraIds":    cameraIDs,
+		"ringSettings": ringSettings,
+	}); err != nil {
+		return nil, err
+	}
+	chime.CameraIDs = cameraIDs
+	chime.RingSettings = ringSettings
+	return chime, nil
+}
+
+// SetChimeRingSettings changes how a chime rings for one paired doorbell and
+// returns the previous settings, or nil if the doorbell had none
+func (pc *ProtectClient) SetChimeRingSettings(ctx context.Context, chimeID, cameraID string, update RingUpdate) (*ProtectRingSettings, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	chime, err := pc.GetChime(ctx, chimeID)
+	if err != nil {
+		return nil, err
+	}
+	if !chime.IsPaired(cameraID) {
+		return nil, fmt.Errorf("camera %s is not paired with chime %s", cameraID, chimeID)
+	}
+
+	var previous *ProtectRingSettings
+	ringSettings := append([]ProtectRingSettings{}, chime.RingSettings...)
+	index := -1
+	for i, rs := range ringSettings {
+		if rs.CameraID == cameraID {
+			prev := rs
+			previous = &prev
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		if update.RingtoneID == "" {
+			return nil, fmt.Errorf("camera %s has no ring settings yet, a ringtone ID is required", cameraID)
+		}
+		ringSettings = append(ringSettings, ProtectRingSettings{CameraID: cameraID, RepeatTimes: 1, Volume: MaxChimeVolume})
+		index = len(ringSettings) - 1
+	}
+
+	rs := &ringSettings[index]
+	if update.Volume != nil {
+		rs.Volume = *update.Volume
+	}
+	if update.RepeatTimes != nil {
+		rs.RepeatTimes = *update.RepeatTimes
+	}
+	if update.RingtoneID != "" {
+		rs.RingtoneID = update.RingtoneID
+	}
+
+	if _, err := pc.PatchChime(ctx, chimeID, map[string]interface{}{"ringSettings": ringSettings}); err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
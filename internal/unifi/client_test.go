@@ -1,7 +1,16 @@
 package unifi
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestProtectClientCreation(t *testing.T) {
@@ -13,3 +22,357 @@ func TestProtectClientCreation(t *testing.T) {
 		t.Errorf("Expected baseURL to be set, got %s", client.baseURL)
 	}
 }
+
+func TestUnsupportedSmartDetectTypes(t *testing.T) {
+	flags := ProtectCameraFeatureFlags{
+		SmartDetectTypes:      []string{"person", "vehicle"},
+		SmartDetectAudioTypes: []string{"alrmSmoke"},
+	}
+	if got := flags.UnsupportedSmartDetectTypes([]string{"person", "vehicle"}, []string{"alrmSmoke"}); len(got) != 0 {
+		t.Errorf("Expected all classes supported, got %v", got)
+	}
+	got := flags.UnsupportedSmartDetectTypes([]string{"person", "licensePlate"}, []string{"alrmBark"})
+	if len(got) != 2 || got[0] != "licensePlate" || got[1] != "alrmBark" {
+		t.Errorf("Expected licensePlate and alrmBark unsupported, got %v", got)
+	}
+}
+
+func TestImagingProfile(t *testing.T) {
+	camera := &ProtectCamera{
+		ID:           "cam1",
+		VideoMode:    "default",
+		HdrType:      "auto",
+		OsdSettings:  &ProtectOSDSettings{IsNameEnabled: true, OverlayLocation: "topLeft"},
+		FeatureFlags: &ProtectCameraFeatureFlags{VideoModes: []string{"default", "highFps"}},
+	}
+
+	if err := (ImagingProfile{VideoMode: "sport"}).Validate(camera); err == nil {
+		t.Error("Expected unsupported video mode to be rejected")
+	}
+	if err := (ImagingProfile{HdrType: "on"}).Validate(camera); err == nil {
+		t.Error("Expected HDR to be rejected on a camera without HDR")
+	}
+	if err := (ImagingProfile{HdrType: "off"}).Validate(camera); err != nil {
+		t.Errorf("Expected HDR off to be accepted, got %v", err)
+	}
+
+	off := false
+	profile := ImagingProfile{VideoMode: "highFps", OSD: &OSDUpdate{IsNameEnabled: &off}}
+	if err := profile.Validate(camera); err != nil {
+		t.Fatalf("Expected profile to be valid, got %v", err)
+	}
+	changes, previous := profile.Diff(camera)
+	if changes["videoMode"] != "highFps" || previous["videoMode"] != "default" {
+		t.Errorf("Unexpected video mode diff: %v %v", changes, previous)
+	}
+	osd, ok := changes["osdSettings"].(ProtectOSDSettings)
+	if !ok || osd.IsNameEnabled || osd.OverlayLocation != "topLeft" {
+		t.Errorf("Expected OSD change to keep the overlay location, got %v", changes["osdSettings"])
+	}
+
+	if changes, _ := (ImagingProfile{VideoMode: "default"}).Diff(camera); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
+
+func TestLightDeviceUpdateValidate(t *testing.T) {
+	level, sensitivity := 7, 50
+	if err := (LightDeviceUpdate{LEDLevel: &level}).Validate(); err == nil {
+		t.Error("Expected brightness 7 to be rejected")
+	}
+	level = 6
+	if err := (LightDeviceUpdate{LEDLevel: &level, PIRSensitivity: &sensitivity}).Validate(); err != nil {
+		t.Errorf("Expected valid update, got %v", err)
+	}
+	if err := (LightDeviceUpdate{}).Validate(); err == nil {
+		t.Error("Expected empty update to be rejected")
+	}
+}
+
+func TestRingUpdateValidate(t *testing.T) {
+	volume, repeat := 101, 11
+	if err := (RingUpdate{Volume: &volume}).Validate(); err == nil {
+		t.Error("Expected volume 101 to be rejected")
+	}
+	if err := (RingUpdate{RepeatTimes: &repeat}).Validate(); err == nil {
+		t.Error("Expected repeat 11 to be rejected")
+	}
+	volume, repeat = 0, 10
+	if err := (RingUpdate{Volume: &volume, RepeatTimes: &repeat}).Validate(); err != nil {
+		t.Errorf("Expected valid update, got %v", err)
+	}
+}
+
+func TestChimeMuted(t *testing.T) {
+	chime := &ProtectChime{RingSettings: []ProtectRingSettings{{CameraID: "door", Volume: 80, RepeatTimes: 2}}}
+	muted := chime.Muted()
+	if muted[0].Volume != 0 || muted[0].RepeatTimes != 2 {
+		t.Errorf("Unexpected muted settings: %+v", muted[0])
+	}
+	if chime.RingSettings[0].Volume != 80 {
+		t.Error("Muted must not modify the chime's settings")
+	}
+}
+
+func TestSensorConfigUpdatePatch(t *testing.T) {
+	low, high := 10.0, 30.0
+	sensor := &ProtectSensor{
+		TemperatureSettings: &ProtectSensorThresholds{IsEnabled: true, Margin: 0.5, LowThreshold: &low, HighThreshold: &high},
+	}
+
+	newHigh := 35.0
+	patch, err := SensorConfigUpdate{Thresholds: map[string]ThresholdUpdate{"temperature": {HighThreshold: &newHigh}}}.Patch(sensor)
+	if err != nil {
+		t.Fatalf("Patch failed: %v", err)
+	}
+	temperature := patch["temperatureSettings"].(map[string]interface{})
+	if *temperature["lowThreshold"].(*float64) != 10 || *temperature["highThreshold"].(*float64) != 35 || temperature["isEnabled"] != true {
+		t.Errorf("Unexpected temperature patch: %v", temperature)
+	}
+	if _, ok := temperature["margin"]; ok {
+		t.Error("Read-only margin must not be patched")
+	}
+
+	tooHot := 130.0
+	if _, err := (SensorConfigUpdate{Thresholds: map[string]ThresholdUpdate{"temperature": {HighThreshold: &tooHot}}}).Patch(sensor); err == nil {
+		t.Error("Expected out of range threshold to be rejected")
+	}
+	inverted := 5.0
+	if _, err := (SensorConfigUpdate{Thresholds: map[string]ThresholdUpdate{"temperature": {HighThreshold: &inverted}}}).Patch(sensor); err == nil {
+		t.Error("Expected high threshold below low threshold to be rejected")
+	}
+}
+
+func TestSensorReadings(t *testing.T) {
+	value := 21.5
+	sensor := &ProtectSensor{Stats: &ProtectSensorStats{Temperature: ProtectSensorStat{Value: &value, Status: "safe"}}}
+	readings := sensor.Readings()
+	if len(readings) != 3 || readings[0].Metric != "temperature" || *readings[0].Value != 21.5 || readings[0].Unit != "°C" || readings[0].Status != "safe" {
+		t.Errorf("Unexpected readings: %+v", readings)
+	}
+}
+
+func TestLiveviewValidate(t *testing.T) {
+	lv := ProtectLiveview{Name: "Front", Layout: 4, Slots: []ProtectLiveviewSlot{{Cameras: []string{"a", "b"}, CycleMode: "motion"}}}
+	if err := lv.Validate(); err != nil {
+		t.Fatalf("Expected valid liveview, got %v", err)
+	}
+	if len(lv.Slots) != 4 || lv.Slots[0].CycleInterval != DefaultLiveviewCycleSeconds || lv.Slots[3].Cameras == nil {
+		t.Errorf("Expected slots padded to the layout with defaults, got %+v", lv.Slots)
+	}
+
+	tooMany := ProtectLiveview{Name: "Grid", Layout: 1, Slots: make([]ProtectLiveviewSlot, 2)}
+	if err := tooMany.Validate(); err == nil {
+		t.Error("Expected slots exceeding the layout to be rejected")
+	}
+	if err := (&ProtectLiveview{Name: "Big", Layout: 27}).Validate(); err == nil {
+		t.Error("Expected layout 27 to be rejected")
+	}
+}
+
+func TestValidateAssetFile(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	if mimeType, err := ValidateAssetFile(png); err != nil || mimeType != "image/png" {
+		t.Errorf("expected image/png, got %q %v", mimeType, err)
+	}
+	if mimeType, err := ValidateAssetFile([]byte("caff\x00\x01\x00\x00desc")); err != nil || mimeType != "audio/x-caf" {
+		t.Errorf("expected audio/x-caf, got %q %v", mimeType, err)
+	}
+	if _, err := ValidateAssetFile([]byte("just some text")); err == nil {
+		t.Error("expected plain text to be rejected")
+	}
+	if _, err := ValidateAssetFile(nil); err == nil {
+		t.Error("expected an empty file to be rejected")
+	}
+	if _, err := ValidateAssetFile(append(png, make([]byte, MaxAssetFileSize)...)); err == nil {
+		t.Error("expected an oversized file to be rejected")
+	}
+}
+
+func TestUploadAssetFile(t *testing.T) {
+	gif := []byte("GIF89a\x01\x00\x01\x00")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/proxy/protect/integration/v1/files/animations" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("expected a file part: %v", err)
+			http.Error(w, "missing file part", http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		if header.Filename != "wave.gif" || header.Header.Get("Content-Type") != "image/gif" || !bytes.Equal(data, gif) {
+			t.Errorf("unexpected part %q %q %q", header.Filename, header.Header.Get("Content-Type"), data)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"name":"abc.gif","type":"animations","originalName":"wave.gif","path":"/files/abc.gif"}`)
+	}))
+	defer srv.Close()
+
+	client := NewProtectClient(srv.URL, "key", false)
+	file, err := client.UploadAssetFile(context.Background(), AssetFileTypeAnimations, "/tmp/wave.gif", gif)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if file.Name != "abc.gif" {
+		t.Errorf("unexpected file %+v", file)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := map[string]Version{
+		"6.2.72":        {6, 2, 72},
+		"5.3":           {5, 3, 0},
+		"6.0.0-beta.3":  {6, 0, 0},
+		" 6.1.4+build ": {6, 1, 4},
+	}
+	for input, want := range tests {
+		got, err := ParseVersion(input)
+		if err != nil || got != want {
+			t.Errorf("ParseVersion(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "six", "6.x.1", "1.2.3.4"} {
+		if _, err := ParseVersion(input); err == nil {
+			t.Errorf("expected ParseVersion(%q) to fail", input)
+		}
+	}
+}
+
+func TestCapabilityChecks(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/proxy/protect/integration/v1/meta/info":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"applicationVersion":"6.2.72"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewProtectClient(srv.URL, "key", false)
+	for _, status := range client.CapabilityMatrix() {
+		if !status.Supported {
+			t.Errorf("expected every capability before the console answers 404, got %+v", status)
+		}
+	}
+	if _, err := client.GetEvents(context.Background(), 10, 0); err == nil {
+		t.Error("expected a missing events endpoint to be an error")
+	} else if err.Error() != "Listing events is not served by this console" {
+		t.Errorf("unexpected error %q", err)
+	}
+
+	if _, err := client.DetectVersion(context.Background()); err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+	if client.Supports(CapabilityEvents) || !client.Supports(CapabilityPTZ) {
+		t.Error("expected only the events list to be unavailable")
+	}
+
+	requests = 0
+	_, err := client.GetEvents(context.Background(), 10, 0)
+	var notServed *NotServedError
+	if !errors.As(err, &notServed) {
+		t.Fatalf("expected a NotServedError, got %v", err)
+	}
+	if err.Error() != "Listing events is not served by this console (Protect 6.2.72)" {
+		t.Errorf("unexpected error %q", err)
+	}
+	if requests != 0 {
+		t.Error("expected a call to an unserved endpoint to make no request")
+	}
+
+	for _, status := range client.CapabilityMatrix() {
+		if status.Supported == (status.Capability == CapabilityEvents) {
+			t.Errorf("unexpected status %+v", status)
+		}
+	}
+}
+
+func TestStreamRetriesFirstDial(t *testing.T) {
+	var dials atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dials.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := NewEventStream(NewProtectClient(srv.URL, "key", false))
+	events := stream.Subscribe()
+	done := make(chan error, 1)
+	go func() { done <- stream.Run(ctx) }()
+
+	for deadline := time.Now().Add(2 * time.Second); dials.Load() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("stream never dialled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("expected a failed dial to be retried, Run returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("expected a clean stop on cancel, got %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("expected consumers to be closed")
+	}
+}
+
+func TestStreamStopsWhenUnserved(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	stream := NewDeviceStream(NewProtectClient(srv.URL, "key", false))
+	devices := stream.Subscribe()
+	var notServed *NotServedError
+	if err := stream.Run(context.Background()); !errors.As(err, &notServed) {
+		t.Fatalf("expected an unserved subscription to stop the stream, got %v", err)
+	}
+	if _, ok := <-devices; ok {
+		t.Error("expected consumers to be closed")
+	}
+}
+
+func TestProbeCapabilities(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/proxy/protect/integration/v1/cameras/cam1":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"id":"cam1"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewProtectClient(srv.URL, "key", false)
+	var lost []Capability
+	client.OnUnavailable(func(c Capability) { lost = append(lost, c) })
+	client.ProbeCapabilities(context.Background())
+	want := []Capability{CapabilityEvents, CapabilitySubscribeEvents, CapabilitySubscribeDevices, CapabilityFiles}
+	if fmt.Sprint(lost) != fmt.Sprint(want) {
+		t.Fatalf("expected %v to be probed unavailable, got %v", want, lost)
+	}
+
+	// A 404 under an unknown camera says nothing about the endpoint
+	if _, err := client.GetCameraSnapshot(context.Background(), "missing", false); err == nil || !client.Supports(CapabilitySnapshot) {
+		t.Fatalf("expected snapshots to stay supported, got %v", err)
+	}
+	var notServed *NotServedError
+	if _, err := client.GetCameraSnapshot(context.Background(), "cam1", false); !errors.As(err, &notServed) {
+		t.Fatalf("expected a NotServedError for a known camera, got %v", err)
+	}
+	if client.Supports(CapabilitySnapshot) || len(lost) != 5 {
+		t.Errorf("expected snapshots to be recorded as unavailable once, got %v", lost)
+	}
+}
@@ -0,0 +1,325 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/openapi"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/protectmock"
+)
+
+const specPath = "../../docs/protect_integration.json"
+
+// contractExempt lists client methods the contract test does not exercise
+var contractExempt = map[string]string{
+	"Authenticate":      "makes no request",
+	"WrapTransport":     "configures the client",
+	"Version":           "makes no request",
+	"Supports":          "makes no request",
+	"RequireCapability": "makes no request",
+	"CapabilityMatrix":  "makes no request",
+	"OnUnavailable":     "makes no request",
+	"ProbeCapabilities": "probes optional endpoints, covered by TestProbeCapabilities",
+	"BaseURL":           "makes no request",
+	"SkipsTLSVerify":    "makes no request",
+	"ProbeSubscription": "WebSocket subscription, covered by the diagnostics tests",
+	"GetEvents":         "/v1/events is not in the 6.2.72 integration API; a 404 marks CapabilityEvents unavailable",
+	"SubscribeEvents":   "WebSocket subscription, covered by the protectmock tests",
+	"SubscribeDevices":  "WebSocket subscription, covered by the protectmock tests",
+}
+
+// specUnused lists spec operations the client does not call
+var specUnused = map[string]string{
+	"GET /v1/subscribe/devices":            "WebSocket upgrade",
+	"GET /v1/subscribe/events":             "WebSocket upgrade",
+	"GET /v1/cameras/{id}/rtsps-stream":    "not implemented by the client",
+	"DELETE /v1/cameras/{id}/rtsps-stream": "not implemented by the client",
+}
+
+type contractCase struct {
+	method string
+	call   func(ctx context.Context, pc *ProtectClient) error
+}
+
+func ignore[T any](_ T, err error) error {
+	return err
+}
+
+func contractCases() []contractCase {
+	on, level, volume := true, 3, 40
+	pir := 30 * time.Second
+	high := 30.0
+	resetAt := time.Now().Add(time.Minute)
+	gif := []byte("GIF89a\x01\x00\x01\x00")
+
+	camera := func(ctx context.Context, pc *ProtectClient) *ProtectCamera {
+		c, _ := pc.GetCamera(ctx, protectmock.CameraID)
+		return c
+	}
+
+	return []contractCase{
+		{"GetSystemInfo", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.GetSystemInfo(ctx)) }},
+		{"Probe", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.Probe(ctx, "cameras")) }},
+		{"DetectVersion", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.DetectVersion(ctx)) }},
+		{"Request", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.Request(ctx, "PATCH", "/v1/lights/"+protectmock.LightID, nil, []byte(`{"lightModeSettings":{"mode":"motion"}}`)))
+		}},
+		{"GetHealth", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.GetHealth(ctx)) }},
+		{"GetNVR", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.GetNVR(ctx)) }},
+		{"GetDevices", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.GetDevices(ctx)) }},
+		{"ListDevicesRaw", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.ListDevicesRaw(ctx, ModelKeySensor))
+		}},
+		{"GetCameras", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.GetCameras(ctx)) }},
+		{"GetCamera", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetCamera(ctx, protectmock.CameraID))
+		}},
+		{"GetCameraDetailed", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetCameraDetailed(ctx, protectmock.CameraID))
+		}},
+		{"PatchCamera", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.PatchCamera(ctx, protectmock.CameraID, map[string]interface{}{"name": "Driveway"}))
+		}},
+		{"ApplyImagingProfile", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.ApplyImagingProfile(ctx, camera(ctx, pc), ImagingProfile{OSD: &OSDUpdate{IsDateEnabled: &on}}))
+		}},
+		{"SetCameraVideoMode", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.SetCameraVideoMode(ctx, protectmock.CameraID, "default"))
+		}},
+		{"SetCameraHDRType", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.SetCameraHDRType(ctx, protectmock.CameraID, "off"))
+		}},
+		{"SetCameraOSD", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.SetCameraOSD(ctx, protectmock.CameraID, OSDUpdate{IsNameEnabled: &on}))
+		}},
+		{"SetCameraSmartDetection", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.SetCameraSmartDetection(ctx, camera(ctx, pc), []string{"person"}, nil))
+		}},
+		{"SetDoorbellMessage", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.SetDoorbellMessage(ctx, protectmock.DoorbellID, LCDMessageCustom, "Back soon", &resetAt))
+		}},
+		{"GetDoorbellDefaultResetTimeout", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetDoorbellDefaultResetTimeout(ctx))
+		}},
+		{"CameraStartPTZPatrol", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.CameraStartPTZPatrol(ctx, protectmock.CameraID, 1))
+		}},
+		{"CameraStopPTZPatrol", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.CameraStopPTZPatrol(ctx, protectmock.CameraID))
+		}},
+		{"CameraGotoPTZPreset", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.CameraGotoPTZPreset(ctx, protectmock.CameraID, 0))
+		}},
+		{"CameraCreateRTSPSStream", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.CameraCreateRTSPSStream(ctx, protectmock.CameraID, nil))
+		}},
+		{"CameraCreateTalkbackSession", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.CameraCreateTalkbackSession(ctx, protectmock.DoorbellID, nil))
+		}},
+		{"CreateTalkbackSession", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.CreateTalkbackSession(ctx, protectmock.DoorbellID))
+		}},
+		{"GetCameraSnapshot", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetCameraSnapshot(ctx, protectmock.CameraID, true))
+		}},
+		{"CameraDisableMicPermanently", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.CameraDisableMicPermanently(ctx, protectmock.CameraID))
+		}},
+		{"TriggerWebhookAlarm", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.TriggerWebhookAlarm(ctx, "front-gate", map[string]interface{}{}))
+		}},
+		{"GetSensors", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.GetSensors(ctx)) }},
+		{"GetSensor", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetSensor(ctx, protectmock.SensorID))
+		}},
+		{"GetSensorDetailed", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetSensorDetailed(ctx, protectmock.SensorID))
+		}},
+		{"PatchSensor", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.PatchSensor(ctx, protectmock.SensorID, map[string]interface{}{"name": "Back Door"}))
+		}},
+		{"ConfigureSensor", func(ctx context.Context, pc *ProtectClient) error {
+			update := SensorConfigUpdate{Thresholds: map[string]ThresholdUpdate{"temperature": {HighThreshold: &high}}}
+			return ignore(pc.ConfigureSensor(ctx, protectmock.SensorID, update))
+		}},
+		{"GetLights", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.GetLights(ctx)) }},
+		{"GetLight", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetLight(ctx, protectmock.LightID))
+		}},
+		{"GetLightDetailed", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetLightDetailed(ctx, protectmock.LightID))
+		}},
+		{"PatchLight", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.PatchLight(ctx, protectmock.LightID, map[string]interface{}{"name": "Porch"}))
+		}},
+		{"SetLightMode", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.SetLightMode(ctx, protectmock.LightID, "motion", "dark"))
+		}},
+		{"SetLightDeviceSettings", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.SetLightDeviceSettings(ctx, protectmock.LightID, LightDeviceUpdate{LEDLevel: &level, PIRDuration: &pir}))
+		}},
+		{"SetLightForced", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.SetLightForced(ctx, protectmock.LightID, true))
+		}},
+		{"GetChimes", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.GetChimes(ctx)) }},
+		{"GetChime", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetChime(ctx, protectmock.ChimeID))
+		}},
+		{"GetChimeDetailed", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetChimeDetailed(ctx, protectmock.ChimeID))
+		}},
+		{"PatchChime", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.PatchChime(ctx, protectmock.ChimeID, map[string]interface{}{"name": "Hallway"}))
+		}},
+		{"PairChimeDoorbell", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.PairChimeDoorbell(ctx, protectmock.ChimeID, protectmock.DoorbellID))
+		}},
+		{"SetChimeRingSettings", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.SetChimeRingSettings(ctx, protectmock.ChimeID, protectmock.DoorbellID, RingUpdate{Volume: &volume}))
+		}},
+		{"UnpairChimeDoorbell", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.UnpairChimeDoorbell(ctx, protectmock.ChimeID, protectmock.DoorbellID))
+		}},
+		{"GetViewers", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.GetViewers(ctx)) }},
+		{"GetViewerDetailed", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetViewerDetailed(ctx, protectmock.ViewerID))
+		}},
+		{"PatchViewer", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.PatchViewer(ctx, protectmock.ViewerID, map[string]interface{}{"name": "Lobby"}))
+		}},
+		{"AssignLiveviewToViewer", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.AssignLiveviewToViewer(ctx, protectmock.ViewerID, protectmock.LiveviewID))
+		}},
+		{"GetLiveviews", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.GetLiveviews(ctx)) }},
+		{"ListLiveviews", func(ctx context.Context, pc *ProtectClient) error { return ignore(pc.ListLiveviews(ctx)) }},
+		{"GetLiveviewDetailed", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.GetLiveviewDetailed(ctx, protectmock.LiveviewID))
+		}},
+		{"PatchLiveview", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.PatchLiveview(ctx, protectmock.LiveviewID, map[string]interface{}{"name": "Outside"}))
+		}},
+		{"CreateLiveview", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.CreateLiveview(ctx, map[string]interface{}{
+				"name": "Raw", "isDefault": false, "isGlobal": true, "layout": 1,
+				"slots": []interface{}{map[string]interface{}{"cameras": []string{protectmock.CameraID}, "cycleMode": "time", "cycleInterval": 10}},
+			}))
+		}},
+		{"SaveLiveview", func(ctx context.Context, pc *ProtectClient) error {
+			lv := ProtectLiveview{Name: "Doors", Layout: 2, Slots: []ProtectLiveviewSlot{{Cameras: []string{protectmock.DoorbellID}, CycleMode: "time"}}}
+			return ignore(pc.SaveLiveview(ctx, lv))
+		}},
+		{"ListAssetFiles", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.ListAssetFiles(ctx, AssetFileTypeAnimations))
+		}},
+		{"UploadAssetFile", func(ctx context.Context, pc *ProtectClient) error {
+			return ignore(pc.UploadAssetFile(ctx, AssetFileTypeAnimations, "wave.gif", gif))
+		}},
+	}
+}
+
+// TestClientContract runs every client method against the mock console with
+// each request and response checked against the OpenAPI spec
+func TestClientContract(t *testing.T) {
+	spec, err := openapi.Load(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := openapi.NewValidator(openapi.NewChecker(spec, openapi.ProtectQuirks...), protectmock.New(), protectmock.Prefixes...)
+	srv := httptest.NewServer(validator)
+	defer srv.Close()
+
+	pc := NewProtectClient(srv.URL, protectmock.DefaultAPIKey, false)
+	ctx := context.Background()
+
+	covered := map[string]bool{}
+	used := map[string]bool{}
+	for _, c := range contractCases() {
+		covered[c.method] = true
+		validator.Reset()
+		if err := c.call(ctx, pc); err != nil {
+			t.Errorf("%s: %v", c.method, err)
+		}
+		calls := validator.Calls()
+		if len(calls) == 0 {
+			t.Errorf("%s: made no requests", c.method)
+		}
+		for _, call := range calls {
+			used[call.Method+" "+call.Operation] = true
+		}
+		for _, v := range validator.Violations() {
+			t.Errorf("%s: %s", c.method, v)
+		}
+	}
+
+	// New client methods must be added to the table or exempted with a reason
+	clientType := reflect.TypeOf(pc)
+	for i := 0; i < clientType.NumMethod(); i++ {
+		name := clientType.Method(i).Name
+		if _, exempt := contractExempt[name]; !covered[name] && !exempt {
+			t.Errorf("%s is not covered by the contract test", name)
+		}
+	}
+
+	// Spec operations the client never calls are drift in the other direction
+	var unused []string
+	for _, ep := range spec.Endpoints() {
+		key := ep.Method + " " + ep.Path
+		if _, ok := specUnused[key]; !used[key] && !ok {
+			unused = append(unused, key)
+		}
+		if _, ok := specUnused[key]; used[key] && ok {
+			t.Errorf("%s is called by the client; remove it from specUnused", key)
+		}
+	}
+	sort.Strings(unused)
+	if len(unused) > 0 {
+		t.Errorf("spec operations not called by any client method: %s", strings.Join(unused, ", "))
+	}
+}
+
+// TestMockFixturesMatchSpec checks the devices only announced over the
+// devices subscription, which the contract test does not see
+func TestMockFixturesMatchSpec(t *testing.T) {
+	spec, err := openapi.Load(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas := map[string]string{
+		ModelKeySpeaker:     "speaker",
+		ModelKeyBridge:      "bridge",
+		ModelKeyAIProcessor: "aiProcessor",
+		ModelKeyAIPort:      "aiPort",
+		ModelKeyLinkStation: "linkStation",
+	}
+	for _, device := range protectmock.DefaultState().OtherDevices {
+		modelKey, _ := device["modelKey"].(string)
+		schema, ok := spec.Schema(schemas[modelKey])
+		if !ok {
+			t.Errorf("no schema for %s devices", modelKey)
+			continue
+		}
+		for _, err := range spec.Validate(schema, roundTrip(t, device), openapi.Options{}) {
+			t.Errorf("%s fixture: %v", modelKey, err)
+		}
+	}
+}
+
+// roundTrip converts fixture values to the types encoding/json decodes into
+func roundTrip(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+)
+
+// Model keys identifying each class of Protect hardware
+const (
+	ModelKeyNVR         = "nvr"
+	ModelKeyCamera      = "camera"
+	ModelKeyChime       = "chime"
+	ModelKeyLight       = "light"
+	ModelKeyViewer      = "viewer"
+	ModelKeySpeaker     = "speaker"
+	ModelKeyBridge      = "bridge"
+	ModelKeySensor      = "sensor"
+	ModelKeyAIProcessor = "aiprocessor"
+	ModelKeyAIPort      = "aiport"
+	ModelKeyLinkStation = "linkstation"
+)
+
+// ModelKeys lists every device class reported by the devices subscription
+var ModelKeys = []string{
+	ModelKeyNVR, ModelKeyCamera, ModelKeyChime, ModelKeyLight, ModelKeyViewer, ModelKeySpeaker,
+	ModelKeyBridge, ModelKeySensor, ModelKeyAIProcessor, ModelKeyAIPort, ModelKeyLinkStation,
+}
+
+// Device connection states
+const (
+	DeviceStateConnected    = "CONNECTED"
+	DeviceStateConnecting   = "CONNECTING"
+	DeviceStateDisconnected = "DISCONNECTED"
+)
+
+// deviceListPaths maps the device classes the API can list to their endpoints.
+// Speakers, bridges, AI processors, AI ports and link stations have no list
+// endpoint and are only known through the devices subscription.
+var deviceListPaths = map[string]string{
+	ModelKeyCamera: "cameras",
+	ModelKeySensor: "sensors",
+	ModelKeyLight:  "lights",
+	ModelKeyChime:  "chimes",
+	ModelKeyViewer: "viewers",
+}
+
+// ProtectDeviceSummary is the identity and connection state every Protect
+// device shares
+type ProtectDeviceSummary struct {
+	ID       string `json:"id"`
+	ModelKey string `json:"modelKey"`
+	State    string `json:"state,omitempty"`
+	Name     string `json:"name"`
+	MAC      string `json:"mac,omitempty"`
+}
+
+// GetDevices lists every device the API can enumerate directly: the NVR,
+// cameras, sensors, lights, chimes and viewers
+func (pc *ProtectClient) GetDevices(ctx context.Context) ([]ProtectDeviceSummary, error) {
+	pc.logger.Debug("Fetching all devices")
+
+	var devices []ProtectDeviceSummary
+	for _, modelKey := range ModelKeys {
+		path, ok := deviceListPaths[modelKey]
+		if !ok {
+			continue
+		}
+		url := fmt.Sprintf("%s/proxy/protect/integration/v1/%s", pc.baseURL, path)
+		var list []ProtectDeviceSummary
+		if err := pc.makeTypedRequest(ctx, url, &list); err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", path, err)
+		}
+		for _, d := range list {
+			if d.ModelKey == "" {
+				d.ModelKey = modelKey
+			}
+			devices = append(devices, d)
+		}
+	}
+
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/nvrs", pc.baseURL)
+	var nvr ProtectDeviceSummary
+	if err := pc.makeTypedRequest(ctx, url, &nvr); err != nil {
+		return nil, fmt.Errorf("failed to get nvr: %w", err)
+	}
+	if nvr.ModelKey == "" {
+		nvr.ModelKey = ModelKeyNVR
+	}
+	// The NVR schema carries no connection state; it answered, so it is up
+	if nvr.State == "" {
+		nvr.State = DeviceStateConnected
+	}
+	devices = append(devices, nvr)
+
+	return devices, nil
+}
+
+// ListDevicesRaw lists one class of device as raw API objects. Only classes
+// with a list endpoint are supported; see ListableModelKeys.
+func (pc *ProtectClient) ListDevicesRaw(ctx context.Context, modelKey string) ([]map[string]interface{}, error) {
+	if modelKey == ModelKeyNVR {
+		nvr, err := pc.GetNVR(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []map[string]interface{}{nvr}, nil
+	}
+	path, ok := deviceListPaths[modelKey]
+	if !ok {
+		return nil, fmt.Errorf("%s devices cannot be listed through the API", modelKey)
+	}
+	pc.logger.Debugf("Fetching %s", path)
+	return pc.makeArrayRequest(ctx, fmt.Sprintf("%s/proxy/protect/integration/v1/%s", pc.baseURL, path))
+}
+
+// ListableModelKeys returns the device classes ListDevicesRaw supports
+func ListableModelKeys() []string {
+	keys := []string{ModelKeyNVR}
+	for _, key := range ModelKeys {
+		if _, ok := deviceListPaths[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,173 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// AssetFileTypeAnimations holds the images and sounds used by doorbell messages
+const AssetFileTypeAnimations = "animations"
+
+// AssetFileTypes lists the device asset file types the API accepts
+var AssetFileTypes = []string{AssetFileTypeAnimations}
+
+// MaxAssetFileSize is the largest asset file the client will upload. The API
+// does not document a limit; doorbell displays only need small files.
+const MaxAssetFileSize = 5 << 20
+
+// AssetMIMETypes lists the MIME types the API accepts for asset files
+var AssetMIMETypes = []string{
+	"image/gif", "image/jpeg", "image/png",
+	"audio/mpeg", "audio/mp4", "audio/wave", "audio/x-caf",
+}
+
+// ProtectAssetFile is an uploaded device asset file. Name is the value to use
+// as the text of an IMAGE doorbell message.
+type ProtectAssetFile struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	OriginalName string `json:"originalName,omitempty"`
+	Path         string `json:"path"`
+}
+
+// DetectAssetMIMEType sniffs the content type of an asset file, returning an
+// error if it is not one the API accepts
+func DetectAssetMIMEType(data []byte) (string, error) {
+	mimeType := sniffAsset(data)
+	if !slices.Contains(AssetMIMETypes, mimeType) {
+		return "", fmt.Errorf("unsupported file type %s, expected one of %v", mimeType, AssetMIMETypes)
+	}
+	return mimeType, nil
+}
+
+// sniffAsset extends http.DetectContentType with the audio containers it does
+// not recognise or reports under a video type
+func sniffAsset(data []byte) string {
+	switch {
+	case len(data) >= 4 && string(data[:4]) == "caff":
+		return "audio/x-caf"
+	case len(data) >= 12 && string(data[4:8]) == "ftyp" && (string(data[8:12]) == "M4A " || string(data[8:12]) == "M4B "):
+		return "audio/mp4"
+	}
+	switch mimeType := http.DetectContentType(data); mimeType {
+	case "audio/wav", "audio/x-wav", "audio/vnd.wave":
+		return "audio/wave"
+	default:
+		mimeType, _, _ = strings.Cut(mimeType, ";")
+		return mimeType
+	}
+}
+
+// ValidateAssetFile checks an asset file's size and content type and returns
+// the detected MIME type
+func ValidateAssetFile(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("file is empty")
+	}
+	if len(data) > MaxAssetFileSize {
+		return "", fmt.Errorf("file is %d bytes, maximum is %d", len(data), MaxAssetFileSize)
+	}
+	return DetectAssetMIMEType(data)
+}
+
+func validateAssetFileType(fileType string) error {
+	if !slices.Contains(AssetFileTypes, fileType) {
+		return fmt.Errorf("invalid asset file type %q, expected one of %v", fileType, AssetFileTypes)
+	}
+	return nil
+}
+
+// ListAssetFiles lists the uploaded asset files of a type
+func (pc *ProtectClient) ListAssetFiles(ctx context.Context, fileType string) ([]ProtectAssetFile, error) {
+	if err := pc.RequireCapability(CapabilityFiles); err != nil {
+		return nil, err
+	}
+	if err := validateAssetFileType(fileType); err != nil {
+		return nil, err
+	}
+	pc.logger.Debugf("Fetching %s asset files", fileType)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/files/%s", pc.baseURL, fileType)
+	var files []ProtectAssetFile
+	if err := pc.makeTypedRequest(ctx, url, &files); err != nil {
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && statusErr.status == http.StatusNotFound {
+			return nil, pc.unavailable(CapabilityFiles)
+		}
+		return nil, err
+	}
+	return files, nil
+}
+
+// UploadAssetFile uploads an asset file as multipart form data after checking
+// its size and content type
+func (pc *ProtectClient) UploadAssetFile(ctx context.Context, fileType, filename string, data []byte) (*ProtectAssetFile, error) {
+	if err := pc.RequireCapability(CapabilityFiles); err != nil {
+		return nil, err
+	}
+	if err := validateAssetFileType(fileType); err != nil {
+		return nil, err
+	}
+	mimeType, err := ValidateAssetFile(data)
+	if err != nil {
+		return nil, err
+	}
+	if filename == "" {
+		filename = "upload"
+	}
+	pc.logger.Debugf("Uploading %s asset file %s (%s, %d bytes)", fileType, filename, mimeType, len(data))
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filepath.Base(filename)))
+	header.Set("Content-Type", mimeType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form part: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to write form part: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish form: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/files/%s", pc.baseURL, fileType)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-KEY", pc.apiKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := pc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, pc.unavailable(CapabilityFiles)
+	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var file ProtectAssetFile
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &file, nil
+}
@@ -0,0 +1,175 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Video modes, HDR types and OSD overlay locations accepted by the cameras endpoint
+var (
+	VideoModes       = []string{"default", "highFps", "sport", "slowShutter", "lprReflex", "lprNoneReflex"}
+	HDRTypes         = []string{"auto", "on", "off"}
+	OverlayLocations = []string{"topLeft", "topMiddle", "topRight", "bottomLeft", "bottomMiddle", "bottomRight"}
+)
+
+// ProtectOSDSettings is a camera's on screen display configuration
+type ProtectOSDSettings struct {
+	IsNameEnabled   bool   `json:"isNameEnabled"`
+	IsDateEnabled   bool   `json:"isDateEnabled"`
+	IsLogoEnabled   bool   `json:"isLogoEnabled"`
+	IsDebugEnabled  bool   `json:"isDebugEnabled"`
+	OverlayLocation string `json:"overlayLocation"`
+}
+
+// OSDUpdate changes selected OSD fields; nil and empty fields are left as is
+type OSDUpdate struct {
+	IsNameEnabled   *bool  `json:"isNameEnabled,omitempty"`
+	IsDateEnabled   *bool  `json:"isDateEnabled,omitempty"`
+	IsLogoEnabled   *bool  `json:"isLogoEnabled,omitempty"`
+	IsDebugEnabled  *bool  `json:"isDebugEnabled,omitempty"`
+	OverlayLocation string `json:"overlayLocation,omitempty"`
+}
+
+// apply returns current with the update merged in
+func (u OSDUpdate) apply(current ProtectOSDSettings) ProtectOSDSettings {
+	if u.IsNameEnabled != nil {
+		current.IsNameEnabled = *u.IsNameEnabled
+	}
+	if u.IsDateEnabled != nil {
+		current.IsDateEnabled = *u.IsDateEnabled
+	}
+	if u.IsLogoEnabled != nil {
+		current.IsLogoEnabled = *u.IsLogoEnabled
+	}
+	if u.IsDebugEnabled != nil {
+		current.IsDebugEnabled = *u.IsDebugEnabled
+	}
+	if u.OverlayLocation != "" {
+		current.OverlayLocation = u.OverlayLocation
+	}
+	return current
+}
+
+// ImagingProfile is a set of imaging settings that can be applied to several
+// cameras. Empty fields are left unchanged.
+type ImagingProfile struct {
+	VideoMode string     `json:"videoMode,omitempty"`
+	HdrType   string     `json:"hdrType,omitempty"`
+	OSD       *OSDUpdate `json:"osdSettings,omitempty"`
+}
+
+// Validate checks the profile against the values the API accepts and the
+// camera's feature flags
+func (p ImagingProfile) Validate(camera *ProtectCamera) error {
+	if p.VideoMode == "" && p.HdrType == "" && p.OSD == nil {
+		return fmt.Errorf("profile changes nothing")
+	}
+	if p.VideoMode != "" {
+		if !slices.Contains(VideoModes, p.VideoMode) {
+			return fmt.Errorf("invalid video mode %q, expected one of %v", p.VideoMode, VideoModes)
+		}
+		if camera.FeatureFlags == nil || !slices.Contains(camera.FeatureFlags.VideoModes, p.VideoMode) {
+			var supported []string
+			if camera.FeatureFlags != nil {
+				supported = camera.FeatureFlags.VideoModes
+			}
+			return fmt.Errorf("camera %s does not support video mode %s (supported: %v)", camera.ID, p.VideoMode, supported)
+		}
+	}
+	if p.HdrType != "" {
+		if !slices.Contains(HDRTypes, p.HdrType) {
+			return fmt.Errorf("invalid HDR type %q, expected one of %v", p.HdrType, HDRTypes)
+		}
+		if p.HdrType != "off" && (camera.FeatureFlags == nil || !camera.FeatureFlags.HasHdr) {
+			return fmt.Errorf("camera %s does not support HDR", camera.ID)
+		}
+	}
+	if p.OSD != nil && p.OSD.OverlayLocation != "" && !slices.Contains(OverlayLocations, p.OSD.OverlayLocation) {
+		return fmt.Errorf("invalid overlay location %q, expected one of %v", p.OSD.OverlayLocation, OverlayLocations)
+	}
+	return nil
+}
+
+// Diff returns the patch needed to bring camera in line with the profile,
+// together with the camera's current values of the changed fields
+func (p ImagingProfile) Diff(camera *ProtectCamera) (changes, previous map[string]interface{}) {
+	changes = map[string]interface{}{}
+	previous = map[string]interface{}{}
+	if p.VideoMode != "" && p.VideoMode != camera.VideoMode {
+		changes["videoMode"] = p.VideoMode
+		previous["videoMode"] = camera.VideoMode
+	}
+	if p.HdrType != "" && p.HdrType != camera.HdrType {
+		changes["hdrType"] = p.HdrType
+		previous["hdrType"] = camera.HdrType
+	}
+	if p.OSD != nil {
+		var current ProtectOSDSettings
+		if camera.OsdSettings != nil {
+			current = *camera.OsdSettings
+		}
+		if updated := p.OSD.apply(current); updated != current {
+			changes["osdSettings"] = updated
+			previous["osdSettings"] = current
+		}
+	}
+	return changes, previous
+}
+
+// ApplyImagingProfile validates the profile against the camera and patches the
+// fields that differ. It returns the previous values of the changed fields.
+func (pc *ProtectClient) ApplyImagingProfile(ctx context.Context, camera *ProtectCamera, profile ImagingProfile) (map[string]interface{}, error) {
+	if err := profile.Validate(camera); err != nil {
+		return nil, err
+	}
+	changes, previous := profile.Diff(camera)
+	if len(changes) == 0 {
+		return previous, nil
+	}
+	pc.logger.Debugf("Updating imaging settings on camera %s", camera.ID)
+	if _, err := pc.PatchCamera(ctx, camera.ID, changes); err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// SetCameraVideoMode changes a camera's video mode and returns the previous mode
+func (pc *ProtectClient) SetCameraVideoMode(ctx context.Context, cameraID, mode string) (string, error) {
+	camera, err := pc.GetCamera(ctx, cameraID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := pc.ApplyImagingProfile(ctx, camera, ImagingProfile{VideoMode: mode}); err != nil {
+		return "", err
+	}
+	return camera.VideoMode, nil
+}
+
+// SetCameraHDRType changes a camera's HDR mode and returns the previous mode
+func (pc *ProtectClient) SetCameraHDRType(ctx context.Context, cameraID, hdrType string) (string, error) {
+	camera, err := pc.GetCamera(ctx, cameraID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := pc.ApplyImagingProfile(ctx, camera, ImagingProfile{HdrType: hdrType}); err != nil {
+		return "", err
+	}
+	return camera.HdrType, nil
+}
+
+// SetCameraOSD updates a camera's on screen display and returns the previous settings
+func (pc *ProtectClient) SetCameraOSD(ctx context.Context, cameraID string, update OSDUpdate) (*ProtectOSDSettings, error) {
+	camera, err := pc.GetCamera(ctx, cameraID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pc.ApplyImagingProfile(ctx, camera, ImagingProfile{OSD: &update}); err != nil {
+		return nil, err
+	}
+	previous := ProtectOSDSettings{}
+	if camera.OsdSettings != nil {
+		previous = *camera.OsdSettings
+	}
+	return &previous, nil
+}
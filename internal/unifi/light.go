@@ -0,0 +1,151 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Floodlight modes and activation windows accepted by the lights endpoint
+var (
+	LightModes     = []string{"always", "motion", "off"}
+	LightEnableAts = []string{"fulltime", "dark"}
+)
+
+// Floodlight hardware setting ranges
+const (
+	MinLightLEDLevel       = 1
+	MaxLightLEDLevel       = 6
+	MaxLightPIRSensitivity = 100
+)
+
+// ProtectLightModeSettings controls when a floodlight turns on
+type ProtectLightModeSettings struct {
+	Mode     string `json:"mode"`
+	EnableAt string `json:"enableAt"`
+}
+
+// ProtectLightDeviceSettings holds a floodlight's hardware settings.
+// PIRDuration is in milliseconds.
+type ProtectLightDeviceSettings struct {
+	IsIndicatorEnabled bool `json:"isIndicatorEnabled"`
+	PIRDuration        int  `json:"pirDuration"`
+	PIRSensitivity     int  `json:"pirSensitivity"`
+	LEDLevel           int  `json:"ledLevel"`
+}
+
+// LightDeviceUpdate changes selected floodlight hardware settings; nil fields
+// are left as is
+type LightDeviceUpdate struct {
+	IsIndicatorEnabled *bool
+	PIRDuration        *time.Duration
+	PIRSensitivity     *int
+	LEDLevel           *int
+}
+
+// Validate checks the update against the ranges the API accepts
+func (u LightDeviceUpdate) Validate() error {
+	if u.IsIndicatorEnabled == nil && u.PIRDuration == nil && u.PIRSensitivity == nil && u.LEDLevel == nil {
+		return fmt.Errorf("no light settings to change")
+	}
+	if u.LEDLevel != nil && (*u.LEDLevel < MinLightLEDLevel || *u.LEDLevel > MaxLightLEDLevel) {
+		return fmt.Errorf("brightness level must be between %d and %d", MinLightLEDLevel, MaxLightLEDLevel)
+	}
+	if u.PIRSensitivity != nil && (*u.PIRSensitivity < 0 || *u.PIRSensitivity > MaxLightPIRSensitivity) {
+		return fmt.Errorf("motion sensitivity must be between 0 and %d", MaxLightPIRSensitivity)
+	}
+	if u.PIRDuration != nil && *u.PIRDuration < 0 {
+		return fmt.Errorf("on duration must not be negative")
+	}
+	return nil
+}
+
+// GetLight retrieves a single light as a typed model
+func (pc *ProtectClient) GetLight(ctx context.Context, lightID string) (*ProtectLight, error) {
+	pc.logger.Debugf("Fetching light %s", lightID)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/lights/%s", pc.baseURL, lightID)
+
+	var light ProtectLight
+	if err := pc.makeTypedRequest(ctx, url, &light); err != nil {
+		return nil, err
+	}
+	return &light, nil
+}
+
+// SetLightMode changes when a floodlight turns on and returns the previous
+// mode settings. An empty enableAt keeps the current value.
+func (pc *ProtectClient) SetLightMode(ctx context.Context, lightID, mode, enableAt string) (*ProtectLightModeSettings, error) {
+	if !slices.Contains(LightModes, mode) {
+		return nil, fmt.Errorf("invalid light mode %q, expected one of %v", mode, LightModes)
+	}
+	if enableAt != "" && !slices.Contains(LightEnableAts, enableAt) {
+		return nil, fmt.Errorf("invalid enableAt %q, expected one of %v", enableAt, LightEnableAts)
+	}
+
+	light, err := pc.GetLight(ctx, lightID)
+	if err != nil {
+		return nil, err
+	}
+	previous := ProtectLightModeSettings{}
+	if light.LightModeSettings != nil {
+		previous = *light.LightModeSettings
+	}
+
+	settings := map[string]interface{}{"mode": mode}
+	if enableAt != "" {
+		settings["enableAt"] = enableAt
+	}
+	if _, err := pc.PatchLight(ctx, lightID, map[string]interface{}{"lightModeSettings": settings}); err != nil {
+		return nil, err
+	}
+	return &previous, nil
+}
+
+// SetLightDeviceSettings changes a floodlight's brightness, motion duration,
+// sensitivity or indicator LED and returns the previous settings
+func (pc *ProtectClient) SetLightDeviceSettings(ctx context.Context, lightID string, update LightDeviceUpdate) (*ProtectLightDeviceSettings, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	light, err := pc.GetLight(ctx, lightID)
+	if err != nil {
+		return nil, err
+	}
+	previous := ProtectLightDeviceSettings{}
+	if light.LightDeviceSettings != nil {
+		previous = *light.LightDeviceSettings
+	}
+
+	settings := map[string]interface{}{}
+	if update.IsIndicatorEnabled != nil {
+		settings["isIndicatorEnabled"] = *update.IsIndicatorEnabled
+	}
+	if update.PIRDuration != nil {
+		settings["pirDuration"] = update.PIRDuration.Milliseconds()
+	}
+	if update.PIRSensitivity != nil {
+		settings["pirSensitivity"] = *update.PIRSensitivity
+	}
+	if update.LEDLevel != nil {
+		settings["ledLevel"] = *update.LEDLevel
+	}
+	if _, err := pc.PatchLight(ctx, lightID, map[string]interface{}{"lightDeviceSettings": settings}); err != nil {
+		return nil, err
+	}
+	return &previous, nil
+}
+
+// SetLightForced turns a floodlight's manual override on or off and returns
+// whether it was previously forced on
+func (pc *ProtectClient) SetLightForced(ctx context.Context, lightID string, enabled bool) (bool, error) {
+	light, err := pc.GetLight(ctx, lightID)
+	if err != nil {
+		return false, err
+	}
+	if _, err := pc.PatchLight(ctx, lightID, map[string]interface{}{"isLightForceEnabled": enabled}); err != nil {
+		return false, err
+	}
+	return light.IsLightForceEnabled, nil
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -18,7 +19,13 @@ type ProtectClient struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+	tlsConfig  *tls.Config
 	logger     *logrus.Entry
+
+	mu                  sync.RWMutex
+	version             *Version            // set by DetectVersion
+	unserved            map[Capability]bool // capabilities the console answered with 404
+	unavailableHandlers []func(Capability)
 }
 
 // ProtectDevice represents a device in Unifi Protect
@@ -64,6 +71,32 @@ type ProtectCamera struct {
 	Recording       bool   `json:"recording,omitempty"`
 	Motion          bool   `json:"motion,omitempty"`
 	LastMotion      int64  `json:"lastMotion,omitempty"`
+
+	ModelKey            string                      `json:"modelKey,omitempty"`
+	State               string                      `json:"state,omitempty"`
+	VideoMode           string                      `json:"videoMode,omitempty"`
+	HdrType             string                      `json:"hdrType,omitempty"`
+	OsdSettings         *ProtectOSDSettings         `json:"osdSettings,omitempty"`
+	FeatureFlags        *ProtectCameraFeatureFlags  `json:"featureFlags,omitempty"`
+	SmartDetectSettings *ProtectSmartDetectSettings `json:"smartDetectSettings,omitempty"`
+}
+
+// ProtectCameraFeatureFlags describes what a camera's hardware supports
+type ProtectCameraFeatureFlags struct {
+	SupportFullHdSnapshot bool     `json:"supportFullHdSnapshot"`
+	HasHdr                bool     `json:"hasHdr"`
+	SmartDetectTypes      []string `json:"smartDetectTypes"`
+	SmartDetectAudioTypes []string `json:"smartDetectAudioTypes"`
+	VideoModes            []string `json:"videoModes"`
+	HasMic                bool     `json:"hasMic"`
+	HasLedStatus          bool     `json:"hasLedStatus"`
+	HasSpeaker            bool     `json:"hasSpeaker"`
+}
+
+// ProtectSmartDetectSettings holds the smart detection classes enabled on a camera
+type ProtectSmartDetectSettings struct {
+	ObjectTypes []string `json:"objectTypes"`
+	AudioTypes  []string `json:"audioTypes"`
 }
 
 // ProtectSensor represents a sensor device
@@ -76,6 +109,27 @@ type ProtectSensor struct {
 	Battery       int    `json:"battery,omitempty"`
 	LastEvent     int64  `json:"lastEvent,omitempty"`
 	LastEventType string `json:"lastEventType,omitempty"`
+
+	ModelKey               string                   `json:"modelKey,omitempty"`
+	State                  string                   `json:"state,omitempty"`
+	MAC                    string                   `json:"mac,omitempty"`
+	MountType              string                   `json:"mountType,omitempty"`
+	BatteryStatus          *ProtectBatteryStatus    `json:"batteryStatus,omitempty"`
+	Stats                  *ProtectSensorStats      `json:"stats,omitempty"`
+	LightSettings          *ProtectSensorThresholds `json:"lightSettings,omitempty"`
+	HumiditySettings       *ProtectSensorThresholds `json:"humiditySettings,omitempty"`
+	TemperatureSettings    *ProtectSensorThresholds `json:"temperatureSettings,omitempty"`
+	IsOpened               bool                     `json:"isOpened"`
+	OpenStatusChangedAt    *int64                   `json:"openStatusChangedAt,omitempty"`
+	IsMotionDetected       bool                     `json:"isMotionDetected"`
+	MotionDetectedAt       *int64                   `json:"motionDetectedAt,omitempty"`
+	MotionSettings         *ProtectMotionSettings   `json:"motionSettings,omitempty"`
+	AlarmTriggeredAt       *int64                   `json:"alarmTriggeredAt,omitempty"`
+	AlarmSettings          *ProtectAlarmSettings    `json:"alarmSettings,omitempty"`
+	LeakDetectedAt         *int64                   `json:"leakDetectedAt,omitempty"`
+	ExternalLeakDetectedAt *int64                   `json:"externalLeakDetectedAt,omitempty"`
+	LeakSettings           *ProtectLeakSettings     `json:"leakSettings,omitempty"`
+	TamperingDetectedAt    *int64                   `json:"tamperingDetectedAt,omitempty"`
 }
 
 // ProtectLight represents a light device
@@ -86,6 +140,17 @@ type ProtectLight struct {
 	Model  string `json:"model"`
 	Status string `json:"status"`
 	On     bool   `json:"on,omitempty"`
+
+	ModelKey            string                      `json:"modelKey,omitempty"`
+	State               string                      `json:"state,omitempty"`
+	LightModeSettings   *ProtectLightModeSettings   `json:"lightModeSettings,omitempty"`
+	LightDeviceSettings *ProtectLightDeviceSettings `json:"lightDeviceSettings,omitempty"`
+	IsDark              bool                        `json:"isDark"`
+	IsLightOn           bool                        `json:"isLightOn"`
+	IsLightForceEnabled bool                        `json:"isLightForceEnabled"`
+	LastMotion          *int64                      `json:"lastMotion,omitempty"`
+	IsPirMotionDetected bool                        `json:"isPirMotionDetected"`
+	Camera              *string                     `json:"camera,omitempty"`
 }
 
 // ProtectChime represents a chime device
@@ -95,6 +160,12 @@ type ProtectChime struct {
 	Type   string `json:"type"`
 	Model  string `json:"model"`
 	Status string `json:"status"`
+
+	ModelKey     string                `json:"modelKey,omitempty"`
+	State        string                `json:"state,omitempty"`
+	MAC          string                `json:"mac,omitempty"`
+	CameraIDs    []string              `json:"cameraIds"`
+	RingSettings []ProtectRingSettings `json:"ringSettings"`
 }
 
 // NewProtectClient creates a new Unifi Protect API client
@@ -120,10 +191,52 @@ func NewProtectClient(baseURL, apiKey string, skipSSLVerify bool) *ProtectClient
 		baseURL:    baseURL,
 		apiKey:     apiKey,
 		httpClient: httpClient,
+		tlsConfig:  tlsConfig,
 		logger:     logrus.WithField("component", "ProtectClient"),
 	}
 }
 
+// WrapTransport installs a transport around the client's HTTP transport, such
+// as a cassette recorder or player
+func (pc *ProtectClient) WrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	next := pc.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	pc.httpClient.Transport = wrap(next)
+}
+
+// BaseURL returns the console URL the client talks to
+func (pc *ProtectClient) BaseURL() string {
+	return pc.baseURL
+}
+
+// SkipsTLSVerify reports whether the client accepts untrusted certificates
+func (pc *ProtectClient) SkipsTLSVerify() bool {
+	return pc.tlsConfig != nil && pc.tlsConfig.InsecureSkipVerify
+}
+
+// Probe sends an authenticated GET to an integration API path such as
+// "meta/info" and returns the response status. Only transport failures are
+// returned as errors, so callers can diagnose any status.
+func (pc *ProtectClient) Probe(ctx context.Context, path string) (int, error) {
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/%s", pc.baseURL, path)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-KEY", pc.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := pc.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
 // Authenticate verifies API key connectivity
 func (pc *ProtectClient) Authenticate(ctx context.Context) error {
 	pc.logger.Debug("Verifying Unifi Protect API key")
@@ -136,9 +249,14 @@ func (pc *ProtectClient) Authenticate(ctx context.Context) error {
 	return nil
 }
 
-// GetEvents retrieves events from Unifi Protect
-// Note: This endpoint may not be available in all Unifi Protect versions
+// GetEvents retrieves events from Unifi Protect. The events list is not part
+// of the documented integration API; consoles that answer it with 404 return
+// a NotServedError.
 func (pc *ProtectClient) GetEvents(ctx context.Context, limit int, offset int) ([]ProtectEvent, error) {
+	if err := pc.RequireCapability(CapabilityEvents); err != nil {
+		return nil, err
+	}
+
 	pc.logger.WithFields(logrus.Fields{
 		"limit":  limit,
 		"offset": offset,
@@ -160,10 +278,9 @@ func (pc *ProtectClient) GetEvents(ctx context.Context, limit int, offset int) (
 	}
 	defer resp.Body.Close()
 
-	// If endpoint not found, return empty slice
 	if resp.StatusCode == http.StatusNotFound {
-		pc.logger.Warn("Events endpoint not available in this Unifi Protect version")
-		return []ProtectEvent{}, nil
+		pc.logger.Warn("Events endpoint not served by this Unifi Protect console")
+		return nil, pc.unavailable(CapabilityEvents)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -345,39 +462,20 @@ func (pc *ProtectClient) GetChimes(ctx context.Context) ([]ProtectChime, error)
 	return chimes, nil
 }
 
-// GetHealth retrieves health status from Unifi Protect
+// GetHealth retrieves health status from Unifi Protect. The integration API
+// has no dedicated health endpoint; the application info answering with the
+// key accepted is the health signal.
 func (pc *ProtectClient) GetHealth(ctx context.Context) (map[string]interface{}, error) {
 	pc.logger.Debug("Fetching health status from Unifi Protect")
 
-	url := fmt.Sprintf("%s/proxy/protect/integration/v1/cameras", pc.baseURL)
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("X-API-KEY", pc.apiKey)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := pc.httpClient.Do(req)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/meta/info", pc.baseURL)
+	info, err := pc.makeDetailRequest(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	var response struct {
-		Data map[string]interface{} `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, err
 	}
 
 	pc.logger.Debug("Retrieved health status")
-	return response.Data, nil
+	return map[string]interface{}{"status": "ok", "info": info}, nil
 }
 
 // GetCameraDetailed retrieves details for a specific camera
@@ -387,6 +485,18 @@ func (pc *ProtectClient) GetCameraDetailed(ctx context.Context, cameraID string)
 	return pc.makeDetailRequest(ctx, url)
 }
 
+// GetCamera retrieves a single camera as a typed model
+func (pc *ProtectClient) GetCamera(ctx context.Context, cameraID string) (*ProtectCamera, error) {
+	pc.logger.Debugf("Fetching camera %s", cameraID)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/cameras/%s", pc.baseURL, cameraID)
+
+	var camera ProtectCamera
+	if err := pc.makeTypedRequest(ctx, url, &camera); err != nil {
+		return nil, err
+	}
+	return &camera, nil
+}
+
 // GetSensorDetailed retrieves details for a specific sensor
 func (pc *ProtectClient) GetSensorDetailed(ctx context.Context, sensorID string) (map[string]interface{}, error) {
 	pc.logger.Debugf("Fetching sensor details for ID: %s", sensorID)
@@ -494,6 +604,32 @@ func (pc *ProtectClient) makeDetailRequest(ctx context.Context, url string) (map
 	return result, nil
 }
 
+// makeTypedRequest is a helper to fetch a resource into a typed value
+func (pc *ProtectClient) makeTypedRequest(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-KEY", pc.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := pc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return &statusError{status: resp.StatusCode, body: string(bodyBytes)}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
 // makeArrayRequest is a helper to fetch array resources
 func (pc *ProtectClient) makeArrayRequest(ctx context.Context, url string) ([]map[string]interface{}, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -523,18 +659,31 @@ func (pc *ProtectClient) makeArrayRequest(ctx context.Context, url string) ([]ma
 
 // makePatchRequest is a helper to send PATCH requests
 func (pc *ProtectClient) makePatchRequest(ctx context.Context, url string, payload map[string]interface{}) (map[string]interface{}, error) {
-	bodyBytes, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	return pc.makeBodyRequest(ctx, "PATCH", url, payload)
+}
+
+// makeBodyRequest sends a JSON request and decodes the object the API returns.
+// A nil payload sends no body, for actions the spec defines without one.
+// Responses without content, such as 204 from PTZ actions, yield an empty map.
+func (pc *ProtectClient) makeBodyRequest(ctx context.Context, method, url string, payload map[string]interface{}) (map[string]interface{}, error) {
+	var body io.Reader
+	if payload != nil {
+		bodyBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		body = bytes.NewReader(bodyBytes)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "PATCH", url, nil)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 	req.Header.Set("X-API-KEY", pc.apiKey)
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	resp, err := pc.httpClient.Do(req)
 	if err != nil {
@@ -542,151 +691,162 @@ func (pc *ProtectClient) makePatchRequest(ctx context.Context, url string, paylo
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, &statusError{status: resp.StatusCode, body: string(bodyBytes)}
 	}
 
-	var response struct {
-		Data map[string]interface{} `json:"data"`
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	result := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return result, nil
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	return response.Data, nil
+	return result, nil
 }
 
 // PatchCamera updates camera settings
 func (pc *ProtectClient) PatchCamera(ctx context.Context, cameraID string, settings map[string]interface{}) (map[string]interface{}, error) {
 	pc.logger.Debugf("Updating camera settings for ID: %s", cameraID)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/cameras/%s", pc.baseURL, cameraID)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/cameras/%s", pc.baseURL, cameraID)
 	return pc.makePatchRequest(ctx, url, settings)
 }
 
 // PatchSensor updates sensor settings
 func (pc *ProtectClient) PatchSensor(ctx context.Context, sensorID string, settings map[string]interface{}) (map[string]interface{}, error) {
 	pc.logger.Debugf("Updating sensor settings for ID: %s", sensorID)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/sensors/%s", pc.baseURL, sensorID)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/sensors/%s", pc.baseURL, sensorID)
 	return pc.makePatchRequest(ctx, url, settings)
 }
 
 // PatchLight updates light settings
 func (pc *ProtectClient) PatchLight(ctx context.Context, lightID string, settings map[string]interface{}) (map[string]interface{}, error) {
 	pc.logger.Debugf("Updating light settings for ID: %s", lightID)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/lights/%s", pc.baseURL, lightID)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/lights/%s", pc.baseURL, lightID)
 	return pc.makePatchRequest(ctx, url, settings)
 }
 
 // PatchChime updates chime settings
 func (pc *ProtectClient) PatchChime(ctx context.Context, chimeID string, settings map[string]interface{}) (map[string]interface{}, error) {
 	pc.logger.Debugf("Updating chime settings for ID: %s", chimeID)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/chimes/%s", pc.baseURL, chimeID)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/chimes/%s", pc.baseURL, chimeID)
 	return pc.makePatchRequest(ctx, url, settings)
 }
 
 // PatchViewer updates viewer settings
 func (pc *ProtectClient) PatchViewer(ctx context.Context, viewerID string, settings map[string]interface{}) (map[string]interface{}, error) {
 	pc.logger.Debugf("Updating viewer settings for ID: %s", viewerID)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/viewers/%s", pc.baseURL, viewerID)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/viewers/%s", pc.baseURL, viewerID)
 	return pc.makePatchRequest(ctx, url, settings)
 }
 
 // PatchLiveview updates liveview settings
 func (pc *ProtectClient) PatchLiveview(ctx context.Context, liveviewID string, settings map[string]interface{}) (map[string]interface{}, error) {
 	pc.logger.Debugf("Updating liveview settings for ID: %s", liveviewID)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/liveviews/%s", pc.baseURL, liveviewID)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/liveviews/%s", pc.baseURL, liveviewID)
 	return pc.makePatchRequest(ctx, url, settings)
 }
 
 // makePostRequest is a helper to send POST requests
 func (pc *ProtectClient) makePostRequest(ctx context.Context, url string, payload map[string]interface{}) (map[string]interface{}, error) {
-	bodyBytes, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-	req.Header.Set("X-API-KEY", pc.apiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := pc.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	var response struct {
-		Data map[string]interface{} `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return response.Data, nil
+	return pc.makeBodyRequest(ctx, "POST", url, payload)
 }
 
 // CreateLiveview creates a new liveview
 func (pc *ProtectClient) CreateLiveview(ctx context.Context, config map[string]interface{}) (map[string]interface{}, error) {
 	pc.logger.Debug("Creating new liveview")
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/liveviews", pc.baseURL)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/liveviews", pc.baseURL)
 	return pc.makePostRequest(ctx, url, config)
 }
 
 // CameraStartPTZPatrol starts a PTZ patrol on a camera
 func (pc *ProtectClient) CameraStartPTZPatrol(ctx context.Context, cameraID string, slot int) (map[string]interface{}, error) {
+	if err := pc.RequireCapability(CapabilityPTZ); err != nil {
+		return nil, err
+	}
 	pc.logger.Debugf("Starting PTZ patrol on camera %s, slot %d", cameraID, slot)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/cameras/%s/ptz/patrol/start/%d", pc.baseURL, cameraID, slot)
-	return pc.makePostRequest(ctx, url, map[string]interface{}{})
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/cameras/%s/ptz/patrol/start/%d", pc.baseURL, cameraID, slot)
+	return pc.ptzRequest(ctx, cameraID, url)
 }
 
 // CameraStopPTZPatrol stops a PTZ patrol on a camera
 func (pc *ProtectClient) CameraStopPTZPatrol(ctx context.Context, cameraID string) (map[string]interface{}, error) {
+	if err := pc.RequireCapability(CapabilityPTZ); err != nil {
+		return nil, err
+	}
 	pc.logger.Debugf("Stopping PTZ patrol on camera %s", cameraID)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/cameras/%s/ptz/patrol/stop", pc.baseURL, cameraID)
-	return pc.makePostRequest(ctx, url, map[string]interface{}{})
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/cameras/%s/ptz/patrol/stop", pc.baseURL, cameraID)
+	return pc.ptzRequest(ctx, cameraID, url)
 }
 
 // CameraGotoPTZPreset moves camera to a PTZ preset
 func (pc *ProtectClient) CameraGotoPTZPreset(ctx context.Context, cameraID string, slot int) (map[string]interface{}, error) {
+	if err := pc.RequireCapability(CapabilityPTZ); err != nil {
+		return nil, err
+	}
 	pc.logger.Debugf("Moving camera %s to PTZ preset %d", cameraID, slot)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/cameras/%s/ptz/goto/%d", pc.baseURL, cameraID, slot)
-	return pc.makePostRequest(ctx, url, map[string]interface{}{})
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/cameras/%s/ptz/goto/%d", pc.baseURL, cameraID, slot)
+	return pc.ptzRequest(ctx, cameraID, url)
+}
+
+// ptzRequest sends a PTZ action, recording PTZ as unserved when the console
+// answers 404 for a camera it knows
+func (pc *ProtectClient) ptzRequest(ctx context.Context, cameraID, url string) (map[string]interface{}, error) {
+	result, err := pc.makePostRequest(ctx, url, nil)
+	if err != nil {
+		return nil, pc.unavailableUnderCamera(ctx, CapabilityPTZ, cameraID, err)
+	}
+	return result, nil
 }
 
-// CameraCreateRTSPSStream creates an RTSPS stream for a camera
+// CameraCreateRTSPSStream creates an RTSPS stream for a camera. The config
+// must list the qualities to publish; an empty config requests high quality.
 func (pc *ProtectClient) CameraCreateRTSPSStream(ctx context.Context, cameraID string, config map[string]interface{}) (map[string]interface{}, error) {
 	pc.logger.Debugf("Creating RTSPS stream for camera %s", cameraID)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/cameras/%s/rtsps-stream", pc.baseURL, cameraID)
+	if len(config) == 0 {
+		config = map[string]interface{}{"qualities": []string{"high"}}
+	}
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/cameras/%s/rtsps-stream", pc.baseURL, cameraID)
 	return pc.makePostRequest(ctx, url, config)
 }
 
-// CameraCreateTalkbackSession creates a talkback session for a camera
+// CameraCreateTalkbackSession creates a talkback session for a camera. The
+// API takes no options; a non-empty config is sent for forward compatibility.
 func (pc *ProtectClient) CameraCreateTalkbackSession(ctx context.Context, cameraID string, config map[string]interface{}) (map[string]interface{}, error) {
+	if err := pc.RequireCapability(CapabilityTalkback); err != nil {
+		return nil, err
+	}
 	pc.logger.Debugf("Creating talkback session for camera %s", cameraID)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/cameras/%s/talkback-session", pc.baseURL, cameraID)
-	return pc.makePostRequest(ctx, url, config)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/cameras/%s/talkback-session", pc.baseURL, cameraID)
+	if len(config) == 0 {
+		config = nil
+	}
+	result, err := pc.makePostRequest(ctx, url, config)
+	if err != nil {
+		return nil, pc.unavailableUnderCamera(ctx, CapabilityTalkback, cameraID, err)
+	}
+	return result, nil
 }
 
 // CameraDisableMicPermanently disables microphone permanently on a camera
 func (pc *ProtectClient) CameraDisableMicPermanently(ctx context.Context, cameraID string) (map[string]interface{}, error) {
 	pc.logger.Debugf("Disabling microphone permanently on camera %s", cameraID)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/cameras/%s/disable-mic-permanently", pc.baseURL, cameraID)
-	return pc.makePostRequest(ctx, url, map[string]interface{}{})
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/cameras/%s/disable-mic-permanently", pc.baseURL, cameraID)
+	return pc.makePostRequest(ctx, url, nil)
 }
 
-// TriggerWebhookAlarm triggers a configured alarm webhook
+// TriggerWebhookAlarm triggers a configured alarm webhook. The API defines
+// no body; a non-empty payload is passed through for alarms that read one.
 func (pc *ProtectClient) TriggerWebhookAlarm(ctx context.Context, webhookID string, payload map[string]interface{}) (map[string]interface{}, error) {
 	pc.logger.Debugf("Triggering webhook alarm %s", webhookID)
-	url := fmt.Sprintf("%s/proxy/protect/api/v1/alarm-manager/webhook/%s", pc.baseURL, webhookID)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/alarm-manager/webhook/%s", pc.baseURL, webhookID)
+	if len(payload) == 0 {
+		payload = nil
+	}
 	return pc.makePostRequest(ctx, url, payload)
 }
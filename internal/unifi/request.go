@@ -0,0 +1,68 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// statusError is a response outside 2xx, kept typed so a 404 can be told
+// apart from other failures
+type statusError struct {
+	status int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.status, e.body)
+}
+
+// APIResponse is the raw result of a request made with Request
+type APIResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// Request sends a request to an integration API path such as
+// /v1/cameras/abc, for operations without a dedicated method. A non-empty body
+// is sent as JSON. Responses outside 2xx are returned as errors.
+func (pc *ProtectClient) Request(ctx context.Context, method, path string, query url.Values, body []byte) (*APIResponse, error) {
+	pc.logger.Debugf("Requesting %s %s", method, path)
+
+	target := pc.baseURL + "/proxy/protect/integration" + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if len(body) > 0 {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-KEY", pc.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := pc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(data))
+	}
+	return &APIResponse{Status: resp.StatusCode, ContentType: resp.Header.Get("Content-Type"), Body: data}, nil
+}
@@ -0,0 +1,250 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProtectBatteryStatus is a sensor's battery charge
+type ProtectBatteryStatus struct {
+	Percentage *float64 `json:"percentage"`
+	IsLow      bool     `json:"isLow"`
+}
+
+// ProtectSensorStat is a single measured value and the range it falls into
+// (neutral, low, safe, high or unknown)
+type ProtectSensorStat struct {
+	Value  *float64 `json:"value"`
+	Status string   `json:"status"`
+}
+
+// ProtectSensorStats holds a sensor's current environmental readings
+type ProtectSensorStats struct {
+	Light       ProtectSensorStat `json:"light"`
+	Humidity    ProtectSensorStat `json:"humidity"`
+	Temperature ProtectSensorStat `json:"temperature"`
+}
+
+// ProtectSensorThresholds configures one environmental sensing capability.
+// Margin is read-only.
+type ProtectSensorThresholds struct {
+	IsEnabled     bool     `json:"isEnabled"`
+	Margin        float64  `json:"margin,omitempty"`
+	LowThreshold  *float64 `json:"lowThreshold"`
+	HighThreshold *float64 `json:"highThreshold"`
+}
+
+// ProtectMotionSettings configures a sensor's motion detection
+type ProtectMotionSettings struct {
+	IsEnabled   bool `json:"isEnabled"`
+	Sensitivity int  `json:"sensitivity"`
+}
+
+// ProtectAlarmSettings configures smoke and carbon monoxide alarm detection
+type ProtectAlarmSettings struct {
+	IsEnabled bool `json:"isEnabled"`
+}
+
+// ProtectLeakSettings configures water leak detection; it is read-only in the API
+type ProtectLeakSettings struct {
+	IsInternalEnabled bool `json:"isInternalEnabled"`
+	IsExternalEnabled bool `json:"isExternalEnabled"`
+}
+
+// SensorMetric describes an environmental metric: its settings field, unit
+// and accepted threshold range
+type SensorMetric struct {
+	Name  string
+	Field string
+	Unit  string
+	Min   float64
+	Max   float64
+}
+
+// SensorMetrics lists the environmental metrics a sensor can measure
+var SensorMetrics = []SensorMetric{
+	{Name: "temperature", Field: "temperatureSettings", Unit: "°C", Min: -39, Max: 124},
+	{Name: "humidity", Field: "humiditySettings", Unit: "%", Min: 1, Max: 99},
+	{Name: "light", Field: "lightSettings", Unit: "lux", Min: 1, Max: 503192},
+}
+
+// SensorReading is one environmental reading with its unit and thresholds
+type SensorReading struct {
+	Metric        string   `json:"metric"`
+	Value         *float64 `json:"value"`
+	Unit          string   `json:"unit"`
+	Status        string   `json:"status"`
+	Enabled       bool     `json:"enabled"`
+	LowThreshold  *float64 `json:"low_threshold"`
+	HighThreshold *float64 `json:"high_threshold"`
+}
+
+// Readings returns the sensor's temperature, humidity and light readings
+func (s *ProtectSensor) Readings() []SensorReading {
+	readings := make([]SensorReading, 0, len(SensorMetrics))
+	for _, m := range SensorMetrics {
+		reading := SensorReading{Metric: m.Name, Unit: m.Unit, Status: "unknown"}
+		if stat := s.stat(m.Name); stat != nil {
+			reading.Value = stat.Value
+			if stat.Status != "" {
+				reading.Status = stat.Status
+			}
+		}
+		if t := s.thresholds(m.Name); t != nil {
+			reading.Enabled = t.IsEnabled
+			reading.LowThreshold = t.LowThreshold
+			reading.HighThreshold = t.HighThreshold
+		}
+		readings = append(readings, reading)
+	}
+	return readings
+}
+
+func (s *ProtectSensor) stat(metric string) *ProtectSensorStat {
+	if s.Stats == nil {
+		return nil
+	}
+	switch metric {
+	case "temperature":
+		return &s.Stats.Temperature
+	case "humidity":
+		return &s.Stats.Humidity
+	case "light":
+		return &s.Stats.Light
+	}
+	return nil
+}
+
+func (s *ProtectSensor) thresholds(metric string) *ProtectSensorThresholds {
+	switch metric {
+	case "temperature":
+		return s.TemperatureSettings
+	case "humidity":
+		return s.HumiditySettings
+	case "light":
+		return s.LightSettings
+	}
+	return nil
+}
+
+// ThresholdUpdate changes one environmental metric's settings; nil fields are
+// left as is
+type ThresholdUpdate struct {
+	IsEnabled     *bool
+	LowThreshold  *float64
+	HighThreshold *float64
+}
+
+// SensorConfigUpdate changes a sensor's sensing capabilities and thresholds.
+// Thresholds are keyed by metric name (temperature, humidity or light).
+type SensorConfigUpdate struct {
+	Thresholds        map[string]ThresholdUpdate
+	MotionEnabled     *bool
+	MotionSensitivity *int
+	AlarmEnabled      *bool
+}
+
+// Patch validates the update against the sensor's current settings and builds
+// the PATCH body
+func (u SensorConfigUpdate) Patch(sensor *ProtectSensor) (map[string]interface{}, error) {
+	patch := map[string]interface{}{}
+	for name, t := range u.Thresholds {
+		metric, ok := sensorMetric(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown sensor metric %q", name)
+		}
+
+		merged := ProtectSensorThresholds{}
+		if current := sensor.thresholds(name); current != nil {
+			merged = *current
+		}
+		if t.IsEnabled != nil {
+			merged.IsEnabled = *t.IsEnabled
+		}
+		if t.LowThreshold != nil {
+			merged.LowThreshold = t.LowThreshold
+		}
+		if t.HighThreshold != nil {
+			merged.HighThreshold = t.HighThreshold
+		}
+
+		for _, v := range []*float64{merged.LowThreshold, merged.HighThreshold} {
+			if v != nil && (*v < metric.Min || *v > metric.Max) {
+				return nil, fmt.Errorf("%s thresholds must be between %g and %g %s", name, metric.Min, metric.Max, metric.Unit)
+			}
+		}
+		if merged.LowThreshold != nil && merged.HighThreshold != nil && *merged.LowThreshold >= *merged.HighThreshold {
+			return nil, fmt.Errorf("%s low threshold must be below the high threshold", name)
+		}
+
+		patch[metric.Field] = map[string]interface{}{
+			"isEnabled":     merged.IsEnabled,
+			"lowThreshold":  merged.LowThreshold,
+			"highThreshold": merged.HighThreshold,
+		}
+	}
+
+	if u.MotionEnabled != nil || u.MotionSensitivity != nil {
+		motion := ProtectMotionSettings{}
+		if sensor.MotionSettings != nil {
+			motion = *sensor.MotionSettings
+		}
+		if u.MotionEnabled != nil {
+			motion.IsEnabled = *u.MotionEnabled
+		}
+		if u.MotionSensitivity != nil {
+			if *u.MotionSensitivity < 0 || *u.MotionSensitivity > 100 {
+				return nil, fmt.Errorf("motion sensitivity must be between 0 and 100")
+			}
+			motion.Sensitivity = *u.MotionSensitivity
+		}
+		patch["motionSettings"] = motion
+	}
+
+	if u.AlarmEnabled != nil {
+		patch["alarmSettings"] = ProtectAlarmSettings{IsEnabled: *u.AlarmEnabled}
+	}
+
+	if len(patch) == 0 {
+		return nil, fmt.Errorf("no sensor settings to change")
+	}
+	return patch, nil
+}
+
+func sensorMetric(name string) (SensorMetric, bool) {
+	for _, m := range SensorMetrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return SensorMetric{}, false
+}
+
+// GetSensor retrieves a single sensor as a typed model
+func (pc *ProtectClient) GetSensor(ctx context.Context, sensorID string) (*ProtectSensor, error) {
+	pc.logger.Debugf("Fetching sensor %s", sensorID)
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/sensors/%s", pc.baseURL, sensorID)
+
+	var sensor ProtectSensor
+	if err := pc.makeTypedRequest(ctx, url, &sensor); err != nil {
+		return nil, err
+	}
+	return &sensor, nil
+}
+
+// ConfigureSensor applies threshold and capability changes to a sensor and
+// returns the sensor as it was before the change
+func (pc *ProtectClient) ConfigureSensor(ctx context.Context, sensorID string, update SensorConfigUpdate) (*ProtectSensor, error) {
+	sensor, err := pc.GetSensor(ctx, sensorID)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := update.Patch(sensor)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pc.PatchSensor(ctx, sensorID, patch); err != nil {
+		return nil, err
+	}
+	return sensor, nil
+}
@@ -0,0 +1,49 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// UnsupportedSmartDetectTypes returns the requested object and audio classes
+// the camera hardware cannot detect
+func (f ProtectCameraFeatureFlags) UnsupportedSmartDetectTypes(objectTypes, audioTypes []string) []string {
+	var unsupported []string
+	for _, t := range objectTypes {
+		if !slices.Contains(f.SmartDetectTypes, t) {
+			unsupported = append(unsupported, t)
+		}
+	}
+	for _, t := range audioTypes {
+		if !slices.Contains(f.SmartDetectAudioTypes, t) {
+			unsupported = append(unsupported, t)
+		}
+	}
+	return unsupported
+}
+
+// SetCameraSmartDetection replaces the enabled smart detection classes on a
+// camera after checking them against its feature flags
+func (pc *ProtectClient) SetCameraSmartDetection(ctx context.Context, camera *ProtectCamera, objectTypes, audioTypes []string) (map[string]interface{}, error) {
+	if camera.FeatureFlags == nil {
+		return nil, fmt.Errorf("camera %s did not report its feature flags", camera.ID)
+	}
+	if unsupported := camera.FeatureFlags.UnsupportedSmartDetectTypes(objectTypes, audioTypes); len(unsupported) > 0 {
+		return nil, fmt.Errorf("camera %s does not support smart detection of %v", camera.ID, unsupported)
+	}
+
+	pc.logger.Debugf("Updating smart detection on camera %s", camera.ID)
+	if objectTypes == nil {
+		objectTypes = []string{}
+	}
+	if audioTypes == nil {
+		audioTypes = []string{}
+	}
+	return pc.PatchCamera(ctx, camera.ID, map[string]interface{}{
+		"smartDetectSettings": map[string]interface{}{
+			"objectTypes": objectTypes,
+			"audioTypes":  audioTypes,
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// GetCameraSnapshot fetches a JPEG snapshot from a camera. highQuality forces
+// a 1080p or larger image on cameras that support it.
+func (pc *ProtectClient) GetCameraSnapshot(ctx context.Context, cameraID string, highQuality bool) ([]byte, error) {
+	if err := pc.RequireCapability(CapabilitySnapshot); err != nil {
+		return nil, err
+	}
+	pc.logger.Debugf("Fetching snapshot from camera %s", cameraID)
+
+	url := fmt.Sprintf("%s/proxy/protect/integration/v1/cameras/%s/snapshot", pc.baseURL, cameraID)
+	if highQuality {
+		url += "?highQuality=true"
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-API-KEY", pc.apiKey)
+	req.Header.Set("Accept", "image/jpeg")
+
+	resp, err := pc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		err := &statusError{status: resp.StatusCode, body: string(bodyBytes)}
+		return nil, pc.unavailableUnderCamera(ctx, CapabilitySnapshot, cameraID, err)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,348 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// subscribeReconnectDelay is how long to wait before re-dialling a dropped subscription
+const subscribeReconnectDelay = 5 * time.Second
+
+// ProtectEventItem represents an event delivered by the events subscription
+type ProtectEventItem struct {
+	ID               string                 `json:"id"`
+	ModelKey         string                 `json:"modelKey"`
+	Type             string                 `json:"type"`
+	Start            int64                  `json:"start"`
+	End              *int64                 `json:"end,omitempty"`
+	Device           string                 `json:"device"`
+	SmartDetectTypes []string               `json:"smartDetectTypes,omitempty"`
+	Score            float64                `json:"score,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ProtectEventMessage represents an add or update message from the events subscription
+type ProtectEventMessage struct {
+	Type string           `json:"type"`
+	Item ProtectEventItem `json:"item"`
+}
+
+// SubscribeEvents opens the events WebSocket subscription. The returned channel
+// is closed when ctx is cancelled; dropped connections are re-established.
+func (pc *ProtectClient) SubscribeEvents(ctx context.Context) (<-chan ProtectEventMessage, error) {
+	if err := pc.RequireCapability(CapabilitySubscribeEvents); err != nil {
+		return nil, err
+	}
+	pc.logger.Debug("Subscribing to Unifi Protect events")
+
+	conn, err := pc.dialSubscription(ctx, "events")
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan ProtectEventMessage)
+	go func() {
+		defer close(events)
+		pc.runSubscription(ctx, conn, "events", func(data []byte) {
+			var msg ProtectEventMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				pc.logger.WithError(err).Warn("Failed to decode event message")
+				return
+			}
+			select {
+			case events <- msg:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return events, nil
+}
+
+// ProtectDeviceMessage represents an add, update or remove message from the
+// devices subscription. Item holds the full device for add, the changed fields
+// plus id and modelKey for update, and only id and modelKey for remove.
+type ProtectDeviceMessage struct {
+	Type string                 `json:"type"`
+	Item map[string]interface{} `json:"item"`
+}
+
+// ID returns the device ID the message refers to
+func (m ProtectDeviceMessage) ID() string {
+	id, _ := m.Item["id"].(string)
+	return id
+}
+
+// ModelKey returns the class of device the message refers to
+func (m ProtectDeviceMessage) ModelKey() string {
+	key, _ := m.Item["modelKey"].(string)
+	return key
+}
+
+// SubscribeDevices opens the devices WebSocket subscription. The returned
+// channel is closed when ctx is cancelled; dropped connections are re-established.
+func (pc *ProtectClient) SubscribeDevices(ctx context.Context) (<-chan ProtectDeviceMessage, error) {
+	if err := pc.RequireCapability(CapabilitySubscribeDevices); err != nil {
+		return nil, err
+	}
+	pc.logger.Debug("Subscribing to Unifi Protect device updates")
+
+	conn, err := pc.dialSubscription(ctx, "devices")
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make(chan ProtectDeviceMessage)
+	go func() {
+		defer close(devices)
+		pc.runSubscription(ctx, conn, "devices", func(data []byte) {
+			var msg ProtectDeviceMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				pc.logger.WithError(err).Warn("Failed to decode device message")
+				return
+			}
+			select {
+			case devices <- msg:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return devices, nil
+}
+
+// ProbeSubscription opens a subscription's WebSocket and closes it again,
+// checking that the console accepts the upgrade
+func (pc *ProtectClient) ProbeSubscription(ctx context.Context, topic string) error {
+	if capability, ok := subscriptionCapabilities[topic]; ok {
+		if err := pc.RequireCapability(capability); err != nil {
+			return err
+		}
+	}
+	conn, err := pc.dialSubscription(ctx, topic)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// subscriptionCapabilities maps each subscription topic to its capability
+var subscriptionCapabilities = map[string]Capability{
+	"events":  CapabilitySubscribeEvents,
+	"devices": CapabilitySubscribeDevices,
+}
+
+// dialSubscription opens a WebSocket connection to a subscription endpoint
+func (pc *ProtectClient) dialSubscription(ctx context.Context, topic string) (*websocket.Conn, error) {
+	wsURL := pc.baseURL
+	switch {
+	case strings.HasPrefix(wsURL, "https://"):
+		wsURL = "wss://" + strings.TrimPrefix(wsURL, "https://")
+	case strings.HasPrefix(wsURL, "http://"):
+		wsURL = "ws://" + strings.TrimPrefix(wsURL, "http://")
+	}
+	wsURL = fmt.Sprintf("%s/proxy/protect/integration/v1/subscribe/%s", wsURL, topic)
+
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 30 * time.Second,
+		TLSClientConfig:  pc.tlsConfig,
+	}
+	header := http.Header{}
+	header.Set("X-API-KEY", pc.apiKey)
+
+	conn, resp, err := dialer.DialContext(ctx, wsURL, header)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, pc.unavailable(subscriptionCapabilities[topic])
+		}
+		if resp != nil {
+			return nil, fmt.Errorf("subscription failed with status %d: %w", resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("subscription failed: %w", err)
+	}
+	return conn, nil
+}
+
+// runSubscription reads messages until ctx is cancelled, reconnecting whenever
+// the connection drops
+func (pc *ProtectClient) runSubscription(ctx context.Context, conn *websocket.Conn, topic string, handle func([]byte)) {
+	logger := pc.logger.WithField("subscription", topic)
+
+	for {
+		stop := make(chan struct{})
+		go func(conn *websocket.Conn) {
+			select {
+			case <-ctx.Done():
+				conn.Close()
+			case <-stop:
+			}
+		}(conn)
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.WithError(err).Warn("Subscription connection lost")
+				}
+				break
+			}
+			handle(data)
+		}
+		close(stop)
+		conn.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(subscribeReconnectDelay):
+			}
+
+			var err error
+			conn, err = pc.dialSubscription(ctx, topic)
+			if err == nil {
+				logger.Info("Subscription reconnected")
+				break
+			}
+			logger.WithError(err).Warn("Failed to reconnect subscription")
+		}
+	}
+}
+
+// fanout forwards messages from one subscription to many consumers
+type fanout[T any] struct {
+	mu          sync.Mutex
+	subscribers []chan T
+	logger      *logrus.Entry
+}
+
+func (f *fanout[T]) subscribe() <-chan T {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan T, 64)
+	f.subscribers = append(f.subscribers, ch)
+	return ch
+}
+
+// run forwards messages until source closes, then closes every consumer.
+// Consumers that fall behind miss messages rather than stalling the others.
+func (f *fanout[T]) run(source <-chan T, describe func(T) logrus.Fields) {
+	defer f.close()
+
+	for msg := range source {
+		f.mu.Lock()
+		for _, ch := range f.subscribers {
+			select {
+			case ch <- msg:
+			default:
+				f.logger.WithFields(describe(msg)).Warn("Consumer is falling behind, dropping message")
+			}
+		}
+		f.mu.Unlock()
+	}
+}
+
+// start opens the subscription and forwards its messages until ctx is
+// cancelled. A failed first dial is retried like a dropped connection, unless
+// the console does not serve the subscription.
+func (f *fanout[T]) start(ctx context.Context, subscribe func(context.Context) (<-chan T, error), describe func(T) logrus.Fields) error {
+	for {
+		source, err := subscribe(ctx)
+		if err == nil {
+			f.run(source, describe)
+			return nil
+		}
+		var notServed *NotServedError
+		if errors.As(err, &notServed) {
+			f.close()
+			return err
+		}
+		if ctx.Err() == nil {
+			f.logger.WithError(err).Warn("Failed to open subscription, retrying")
+		}
+
+		select {
+		case <-ctx.Done():
+			f.close()
+			return nil
+		case <-time.After(subscribeReconnectDelay):
+		}
+	}
+}
+
+func (f *fanout[T]) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, ch := range f.subscribers {
+		close(ch)
+	}
+	f.subscribers = nil
+}
+
+// EventStream fans out a single events subscription to multiple consumers
+type EventStream struct {
+	client *ProtectClient
+	fanout fanout[ProtectEventMessage]
+}
+
+// NewEventStream creates a new event stream for the given client
+func NewEventStream(client *ProtectClient) *EventStream {
+	return &EventStream{
+		client: client,
+		fanout: fanout[ProtectEventMessage]{logger: logrus.WithField("component", "EventStream")},
+	}
+}
+
+// Subscribe registers a new consumer. Subscribe must be called before Run;
+// the returned channel is closed when the stream stops.
+func (es *EventStream) Subscribe() <-chan ProtectEventMessage {
+	return es.fanout.subscribe()
+}
+
+// Run forwards events to all consumers until ctx is cancelled. Consumers that
+// fall behind miss events rather than stalling the others. Run returns early
+// only when the console does not serve the events subscription.
+func (es *EventStream) Run(ctx context.Context) error {
+	return es.fanout.start(ctx, es.client.SubscribeEvents, func(msg ProtectEventMessage) logrus.Fields {
+		return logrus.Fields{"event_id": msg.Item.ID}
+	})
+}
+
+// DeviceStream fans out a single devices subscription to multiple consumers
+type DeviceStream struct {
+	client *ProtectClient
+	fanout fanout[ProtectDeviceMessage]
+}
+
+// NewDeviceStream creates a new device stream for the given client
+func NewDeviceStream(client *ProtectClient) *DeviceStream {
+	return &DeviceStream{
+		client: client,
+		fanout: fanout[ProtectDeviceMessage]{logger: logrus.WithField("component", "DeviceStream")},
+	}
+}
+
+// Subscribe registers a new consumer. Subscribe must be called before Run;
+// the returned channel is closed when the stream stops.
+func (ds *DeviceStream) Subscribe() <-chan ProtectDeviceMessage {
+	return ds.fanout.subscribe()
+}
+
+// Run forwards device messages to all consumers until ctx is cancelled. Run
+// returns early only when the console does not serve the devices subscription.
+func (ds *DeviceStream) Run(ctx context.Context) error {
+	return ds.fanout.start(ctx, ds.client.SubscribeDevices, func(msg ProtectDeviceMessage) logrus.Fields {
+		return logrus.Fields{"device_id": msg.ID(), "model_key": msg.ModelKey()}
+	})
+}
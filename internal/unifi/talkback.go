@@ -0,0 +1,36 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ProtectTalkbackSession is where and how to send audio to a camera speaker
+type ProtectTalkbackSession struct {
+	URL           string `json:"url"`
+	Codec         string `json:"codec"`
+	SamplingRate  int    `json:"samplingRate"`
+	BitsPerSample int    `json:"bitsPerSample"`
+}
+
+// CreateTalkbackSession creates a talkback session for a camera and returns
+// its stream URL and audio configuration
+func (pc *ProtectClient) CreateTalkbackSession(ctx context.Context, cameraID string) (*ProtectTalkbackSession, error) {
+	result, err := pc.CameraCreateTalkbackSession(ctx, cameraID, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode talkback session: %w", err)
+	}
+	var session ProtectTalkbackSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to decode talkback session: %w", err)
+	}
+	if session.URL == "" || session.Codec == "" || session.SamplingRate <= 0 {
+		return nil, fmt.Errorf("incomplete talkback session for camera %s: %v", cameraID, result)
+	}
+	return &session, nil
+}
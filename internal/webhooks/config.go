@@ -0,0 +1,124 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// Default delivery settings used when an endpoint does not override them
+const (
+	defaultMaxAttempts = 8
+	defaultTimeout     = 10 * time.Second
+)
+
+// Config holds the outbound webhook configuration
+type Config struct {
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// Endpoint is a destination that receives matching Protect events
+type Endpoint struct {
+	ID          string `json:"id"`
+	URL         string `json:"url"`
+	Secret      string `json:"secret,omitempty"`
+	Filter      Filter `json:"filter"`
+	MaxAttempts int    `json:"max_attempts,omitempty"`
+	TimeoutSecs int    `json:"timeout_seconds,omitempty"`
+}
+
+// Filter selects which events are delivered to an endpoint. Empty lists match
+// everything.
+type Filter struct {
+	EventTypes       []string `json:"event_types,omitempty"`
+	Cameras          []string `json:"cameras,omitempty"`
+	SmartDetectTypes []string `json:"smart_detect_types,omitempty"`
+	MinScore         float64  `json:"min_score,omitempty"`
+	IncludeUpdates   bool     `json:"include_updates,omitempty"`
+}
+
+// LoadConfig reads a webhook configuration file
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read webhook config: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse webhook config: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// Validate checks that every endpoint is usable
+func (c Config) Validate() error {
+	seen := map[string]bool{}
+	for i, ep := range c.Endpoints {
+		if ep.ID == "" {
+			return fmt.Errorf("webhook endpoint %d: id is required", i)
+		}
+		if seen[ep.ID] {
+			return fmt.Errorf("webhook endpoint %s: duplicate id", ep.ID)
+		}
+		seen[ep.ID] = true
+		if ep.URL == "" {
+			return fmt.Errorf("webhook endpoint %s: url is required", ep.ID)
+		}
+		if ep.Filter.MinScore < 0 || ep.Filter.MinScore > 100 {
+			return fmt.Errorf("webhook endpoint %s: min_score must be between 0 and 100", ep.ID)
+		}
+	}
+	return nil
+}
+
+func (ep Endpoint) maxAttempts() int {
+	if ep.MaxAttempts > 0 {
+		return ep.MaxAttempts
+	}
+	return defaultMaxAttempts
+}
+
+func (ep Endpoint) timeout() time.Duration {
+	if ep.TimeoutSecs > 0 {
+		return time.Duration(ep.TimeoutSecs) * time.Second
+	}
+	return defaultTimeout
+}
+
+// Matches reports whether an event message passes the filter. Events that do
+// not carry a score are not subject to MinScore.
+func (f Filter) Matches(msg unifi.ProtectEventMessage) bool {
+	if msg.Type != "add" && !f.IncludeUpdates {
+		return false
+	}
+	if len(f.EventTypes) > 0 && !slices.Contains(f.EventTypes, msg.Item.Type) {
+		return false
+	}
+	if len(f.Cameras) > 0 && !slices.Contains(f.Cameras, msg.Item.Device) {
+		return false
+	}
+	if len(f.SmartDetectTypes) > 0 {
+		matched := false
+		for _, t := range msg.Item.SmartDetectTypes {
+			if slices.Contains(f.SmartDetectTypes, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if f.MinScore > 0 && msg.Item.Score > 0 && msg.Item.Score < f.MinScore {
+		return false
+	}
+	return true
+}
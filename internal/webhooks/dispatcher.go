@@ -0,0 +1,344 @@
+// Package webhooks delivers Protect events to external HTTP endpoints
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderDelivery  = "X-Protect-Webhook-Delivery"
+	HeaderTimestamp = "X-Protect-Webhook-Timestamp"
+	HeaderSignature = "X-Protect-Webhook-Signature"
+)
+
+// Retry backoff bounds
+const (
+	retryBaseDelay = 10 * time.Second
+	retryMaxDelay  = time.Hour
+)
+
+// Payload is the JSON body posted to endpoints
+type Payload struct {
+	DeliveryID string                 `json:"delivery_id"`
+	EndpointID string                 `json:"endpoint_id"`
+	Action     string                 `json:"action"`
+	Event      unifi.ProtectEventItem `json:"event"`
+}
+
+// Notification is the JSON body posted for messages that are not Protect
+// events, such as the daily event digest
+type Notification struct {
+	DeliveryID string      `json:"delivery_id"`
+	EndpointID string      `json:"endpoint_id"`
+	Action     string      `json:"action"`
+	Data       interface{} `json:"data"`
+}
+
+// EndpointStatus summarises deliveries for one endpoint
+type EndpointStatus struct {
+	ID        string `json:"id"`
+	URL       string `json:"url"`
+	Signed    bool   `json:"signed"`
+	Pending   int    `json:"pending"`
+	Delivered int    `json:"delivered"`
+	Failed    int    `json:"failed"`
+}
+
+// Dispatcher matches events against endpoints and delivers them with retries
+type Dispatcher struct {
+	config     Config
+	queue      *queue
+	httpClient *http.Client
+	wake       chan struct{}
+	logger     *logrus.Entry
+}
+
+// NewDispatcher creates a dispatcher whose queue is stored under dataDir
+func NewDispatcher(config Config, dataDir string) (*Dispatcher, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	q, err := openQueue(filepath.Join(dataDir, "webhooks", "queue.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dispatcher{
+		config:     config,
+		queue:      q,
+		httpClient: &http.Client{},
+		wake:       make(chan struct{}, 1),
+		logger:     logrus.WithField("component", "WebhookDispatcher"),
+	}, nil
+}
+
+// Run consumes events and delivers queued payloads until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context, events <-chan unifi.ProtectEventMessage) {
+	d.logger.WithField("endpoints", len(d.config.Endpoints)).Info("Starting webhook dispatcher")
+
+	go d.deliverLoop(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-events:
+			if !ok {
+				return
+			}
+			d.Enqueue(msg)
+		}
+	}
+}
+
+// Enqueue queues a delivery for every endpoint whose filter matches the event
+func (d *Dispatcher) Enqueue(msg unifi.ProtectEventMessage) {
+	now := time.Now()
+	queued := 0
+
+	for _, ep := range d.config.Endpoints {
+		if !ep.Filter.Matches(msg) {
+			continue
+		}
+
+		id := uuid.NewString()
+		body, err := json.Marshal(Payload{
+			DeliveryID: id,
+			EndpointID: ep.ID,
+			Action:     msg.Type,
+			Event:      msg.Item,
+		})
+		if err != nil {
+			d.logger.WithError(err).Error("Failed to encode webhook payload")
+			continue
+		}
+
+		delivery := &Delivery{
+			ID:          id,
+			EndpointID:  ep.ID,
+			EventID:     msg.Item.ID,
+			EventType:   msg.Item.Type,
+			Payload:     body,
+			Status:      StatusPending,
+			NextAttempt: now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := d.queue.add(delivery); err != nil {
+			d.logger.WithError(err).Error("Failed to persist webhook delivery")
+			continue
+		}
+		queued++
+	}
+
+	if queued > 0 {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Notify queues a notification for one endpoint regardless of its event
+// filter. It is delivered, retried and signed like an event.
+func (d *Dispatcher) Notify(endpointID, action string, data interface{}) error {
+	if _, ok := d.endpoint(endpointID); !ok {
+		return fmt.Errorf("webhook endpoint %s is not configured", endpointID)
+	}
+
+	now := time.Now()
+	id := uuid.NewString()
+	body, err := json.Marshal(Notification{
+		DeliveryID: id,
+		EndpointID: endpointID,
+		Action:     action,
+		Data:       data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	delivery := &Delivery{
+		ID:          id,
+		EndpointID:  endpointID,
+		EventType:   action,
+		Payload:     body,
+		Status:      StatusPending,
+		NextAttempt: now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := d.queue.add(delivery); err != nil {
+		return fmt.Errorf("failed to persist notification: %w", err)
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Deliveries returns recent deliveries, newest first
+func (d *Dispatcher) Deliveries(status, endpointID string, limit int) []Delivery {
+	return d.queue.list(status, endpointID, limit)
+}
+
+// Endpoints returns the configured endpoints with delivery counts
+func (d *Dispatcher) Endpoints() []EndpointStatus {
+	statuses := make([]EndpointStatus, 0, len(d.config.Endpoints))
+	for _, ep := range d.config.Endpoints {
+		st := EndpointStatus{ID: ep.ID, URL: ep.URL, Signed: ep.Secret != ""}
+		for _, del := range d.queue.list("", ep.ID, 0) {
+			switch del.Status {
+			case StatusPending:
+				st.Pending++
+			case StatusDelivered:
+				st.Delivered++
+			case StatusFailed:
+				st.Failed++
+			}
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
+
+func (d *Dispatcher) deliverLoop(ctx context.Context) {
+	for {
+		for _, del := range d.queue.due(time.Now()) {
+			if ctx.Err() != nil {
+				return
+			}
+			d.attempt(ctx, del)
+		}
+
+		wait := time.Minute
+		if next, ok := d.queue.nextAttempt(); ok {
+			wait = time.Until(next)
+		}
+		if wait < 0 {
+			wait = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, del Delivery) {
+	ep, ok := d.endpoint(del.EndpointID)
+	logger := d.logger.WithFields(logrus.Fields{
+		"delivery_id": del.ID,
+		"endpoint_id": del.EndpointID,
+	})
+
+	del.Attempts++
+	del.UpdatedAt = time.Now()
+
+	if !ok {
+		// The endpoint was removed from the config since this was queued
+		del.Status = StatusFailed
+		del.LastError = "endpoint no longer configured"
+	} else if code, err := d.post(ctx, ep, del); err != nil {
+		del.LastStatusCode = code
+		del.LastError = err.Error()
+		if del.Attempts >= ep.maxAttempts() {
+			del.Status = StatusFailed
+			logger.WithError(err).Warn("Webhook delivery failed permanently")
+		} else {
+			del.NextAttempt = time.Now().Add(backoff(del.Attempts))
+			logger.WithError(err).Debug("Webhook delivery failed, will retry")
+		}
+	} else {
+		del.LastStatusCode = code
+		del.LastError = ""
+		del.Status = StatusDelivered
+		logger.Debug("Webhook delivered")
+	}
+
+	if err := d.queue.update(del); err != nil {
+		logger.WithError(err).Error("Failed to persist webhook delivery")
+	}
+}
+
+func (d *Dispatcher) post(ctx context.Context, ep Endpoint, del Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, ep.timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", ep.URL, bytes.NewReader(del.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDelivery, del.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if ep.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(ep.Secret, timestamp, del.Payload))
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) endpoint(id string) (Endpoint, bool) {
+	for _, ep := range d.config.Endpoints {
+		if ep.ID == id {
+			return ep, true
+		}
+	}
+	return Endpoint{}, false
+}
+
+// Sign returns the signature header value for a payload: the hex encoded
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the endpoint secret
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func backoff(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,139 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/unifi"
+)
+
+func TestFilterMatches(t *testing.T) {
+	person := unifi.ProtectEventMessage{
+		Type: "add",
+		Item: unifi.ProtectEventItem{ID: "e1", Type: "smartDetectZone", Device: "cam1", SmartDetectTypes: []string{"person"}, Score: 80},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		msg    unifi.ProtectEventMessage
+		want   bool
+	}{
+		{"empty filter", Filter{}, person, true},
+		{"event type", Filter{EventTypes: []string{"ring"}}, person, false},
+		{"camera", Filter{Cameras: []string{"cam1"}}, person, true},
+		{"smart detect class", Filter{SmartDetectTypes: []string{"vehicle"}}, person, false},
+		{"score below minimum", Filter{MinScore: 90}, person, false},
+		{"score above minimum", Filter{MinScore: 50}, person, true},
+		{"update excluded", Filter{}, unifi.ProtectEventMessage{Type: "update", Item: person.Item}, false},
+		{"update included", Filter{IncludeUpdates: true}, unifi.ProtectEventMessage{Type: "update", Item: person.Item}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.Matches(tt.msg); got != tt.want {
+			t.Errorf("%s: Matches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDeliverySignedAndRetried(t *testing.T) {
+	var calls int32
+	var gotSignature, gotTimestamp string
+	var gotBody []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		gotSignature = r.Header.Get(HeaderSignature)
+		gotTimestamp = r.Header.Get(HeaderTimestamp)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	dataDir := t.TempDir()
+	config := Config{Endpoints: []Endpoint{{ID: "ops", URL: srv.URL, Secret: "s3cret"}}}
+	d, err := NewDispatcher(config, dataDir)
+	if err != nil {
+		t.Fatalf("NewDispatcher failed: %v", err)
+	}
+
+	d.Enqueue(unifi.ProtectEventMessage{Type: "add", Item: unifi.ProtectEventItem{ID: "e1", Type: "ring", Device: "door"}})
+
+	pending := d.Deliveries(StatusPending, "", 0)
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending delivery, got %d", len(pending))
+	}
+
+	// The queue must survive a restart
+	d, err = NewDispatcher(config, dataDir)
+	if err != nil {
+		t.Fatalf("NewDispatcher failed: %v", err)
+	}
+
+	due := d.queue.due(pending[0].CreatedAt)
+	if len(due) != 1 {
+		t.Fatalf("expected persisted delivery to be due, got %d", len(due))
+	}
+	d.attempt(context.Background(), due[0])
+
+	retry := d.Deliveries(StatusPending, "ops", 0)
+	if len(retry) != 1 || retry[0].Attempts != 1 || retry[0].LastStatusCode != http.StatusBadGateway {
+		t.Fatalf("expected delivery to be pending retry, got %+v", retry)
+	}
+
+	d.attempt(context.Background(), d.queue.due(retry[0].NextAttempt)[0])
+
+	delivered := d.Deliveries(StatusDelivered, "", 0)
+	if len(delivered) != 1 {
+		t.Fatalf("expected 1 delivered delivery, got %d", len(delivered))
+	}
+	if want := Sign("s3cret", gotTimestamp, gotBody); gotSignature != want {
+		t.Errorf("signature = %q, want %q", gotSignature, want)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	// The filter applies to events only
+	config := Config{Endpoints: []Endpoint{{ID: "ops", URL: srv.URL, Filter: Filter{EventTypes: []string{"ring"}}}}}
+	d, err := NewDispatcher(config, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDispatcher failed: %v", err)
+	}
+
+	if err := d.Notify("missing", "digest", nil); err == nil {
+		t.Error("expected an unknown endpoint to be refused")
+	}
+	if err := d.Notify("ops", "digest", map[string]string{"title": "Daily digest"}); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	pending := d.Deliveries(StatusPending, "ops", 0)
+	if len(pending) != 1 || pending[0].EventType != "digest" {
+		t.Fatalf("expected 1 pending digest delivery, got %+v", pending)
+	}
+	d.attempt(context.Background(), d.queue.due(pending[0].NextAttempt)[0])
+
+	var payload Notification
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := payload.Data.(map[string]interface{})
+	if payload.Action != "digest" || payload.DeliveryID != pending[0].ID || data["title"] != "Daily digest" {
+		t.Errorf("unexpected notification %s", gotBody)
+	}
+}
@@ -0,0 +1,150 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/surrealwolf/unifi-protect-mcp/internal/store"
+)
+
+// historyLimit is how many finished deliveries are kept for status reporting
+const historyLimit = 200
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Delivery is a single event queued for a single endpoint
+type Delivery struct {
+	ID             string          `json:"id"`
+	EndpointID     string          `json:"endpoint_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload,omitempty"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttempt    time.Time       `json:"next_attempt,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// queue is the on-disk delivery queue. Pending deliveries survive restarts;
+// finished ones are kept in a bounded history.
+type queue struct {
+	path string
+	mu   sync.Mutex
+	// state is what gets persisted
+	state struct {
+		Pending []*Delivery `json:"pending"`
+		History []*Delivery `json:"history"`
+	}
+}
+
+func openQueue(path string) (*queue, error) {
+	q := &queue{path: path}
+	if err := store.Load(path, &q.state); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *queue) add(d *Delivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.state.Pending = append(q.state.Pending, d)
+	return q.saveLocked()
+}
+
+// due returns copies of the pending deliveries whose next attempt has passed
+func (q *queue) due(now time.Time) []Delivery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var due []Delivery
+	for _, d := range q.state.Pending {
+		if !d.NextAttempt.After(now) {
+			due = append(due, *d)
+		}
+	}
+	return due
+}
+
+// nextAttempt returns the earliest scheduled attempt, or false if the queue is empty
+func (q *queue) nextAttempt() (time.Time, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var next time.Time
+	for _, d := range q.state.Pending {
+		if next.IsZero() || d.NextAttempt.Before(next) {
+			next = d.NextAttempt
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// update stores the outcome of an attempt, moving finished deliveries to history
+func (q *queue) update(d Delivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, p := range q.state.Pending {
+		if p.ID != d.ID {
+			continue
+		}
+		if d.Status == StatusPending {
+			*p = d
+		} else {
+			q.state.Pending = append(q.state.Pending[:i], q.state.Pending[i+1:]...)
+			finished := d
+			finished.Payload = nil
+			q.state.History = append(q.state.History, &finished)
+			if len(q.state.History) > historyLimit {
+				q.state.History = q.state.History[len(q.state.History)-historyLimit:]
+			}
+		}
+		break
+	}
+	return q.saveLocked()
+}
+
+// list returns deliveries newest first, optionally filtered by status and endpoint
+func (q *queue) list(status, endpointID string, limit int) []Delivery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var result []Delivery
+	for _, group := range [][]*Delivery{q.state.Pending, q.state.History} {
+		for _, d := range group {
+			if status != "" && d.Status != status {
+				continue
+			}
+			if endpointID != "" && d.EndpointID != endpointID {
+				continue
+			}
+			c := *d
+			c.Payload = nil
+			result = append(result, c)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UpdatedAt.After(result[j].UpdatedAt)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func (q *queue) saveLocked() error {
+	return store.Save(q.path, &q.state)
+}